@@ -19,6 +19,7 @@ type Config struct {
 	Flutter    FlutterConfig    `yaml:"flutter"`
 	Settings   GlobalSettings   `yaml:"settings"`
 	BugManager BugManagerConfig `yaml:"bug_manager"`
+	Release    ReleaseConfig    `yaml:"release_manager,omitempty"`
 }
 
 // GitHubConfig holds GitHub-related configuration
@@ -98,11 +99,40 @@ type LinearProject struct {
 // BugManagerConfig holds bug manager specific configuration
 type BugManagerConfig struct {
 	Connections []BugManagerConnection `yaml:"connections"`
+	Webhook     BugManagerWebhook      `yaml:"webhook,omitempty"`
+	Templates   []BugManagerTemplate   `yaml:"templates,omitempty"`
 }
 
-// BugManagerConnection represents a connection between Linear and Sentry instances
+// BugManagerTemplate is a named issue template used for manual creation and Sentry syncs
+type BugManagerTemplate struct {
+	Name        string               `yaml:"name"`
+	Type        string               `yaml:"type,omitempty"`         // Issue type, e.g. "Feature"
+	TitlePrefix string               `yaml:"title_prefix,omitempty"` // Prepended to the title, e.g. "[Feature] "
+	Description string               `yaml:"description,omitempty"`  // Markdown skeleton for the description
+	Labels      []string             `yaml:"labels,omitempty"`
+	Priority    string               `yaml:"priority,omitempty"` // "none", "urgent", "high", "medium" or "low"
+	State       string               `yaml:"state,omitempty"`    // Workflow state name
+	SubIssues   []BugManagerSubIssue `yaml:"sub_issues,omitempty"`
+}
+
+// BugManagerSubIssue is a follow-up issue created under issues using a template
+type BugManagerSubIssue struct {
+	Title       string   `yaml:"title"`
+	Description string   `yaml:"description,omitempty"`
+	Labels      []string `yaml:"labels,omitempty"`
+	Priority    string   `yaml:"priority,omitempty"` // Defaults to the parent's priority
+}
+
+// BugManagerWebhook configures the Sentry webhook receiver
+type BugManagerWebhook struct {
+	Addr         string `yaml:"addr,omitempty"`          // Listen address, e.g. ":8080"
+	ClientSecret string `yaml:"client_secret,omitempty"` // Sentry integration client secret used to verify signatures
+}
+
+// BugManagerConnection represents a connection between Sentry and an issue tracker
 type BugManagerConnection struct {
 	Name            string                     `yaml:"name"`
+	Tracker         string                     `yaml:"tracker,omitempty"` // "linear" (default) or "github"
 	LinearInstance  string                     `yaml:"linear_instance"`
 	SentryInstance  string                     `yaml:"sentry_instance"`
 	ProjectMappings []BugManagerProjectMapping `yaml:"project_mappings"`
@@ -110,12 +140,64 @@ type BugManagerConnection struct {
 
 // BugManagerProjectMapping represents a mapping between Sentry and Linear projects
 type BugManagerProjectMapping struct {
-	SentryOrganization string   `yaml:"sentry_organization"`
-	SentryProject      string   `yaml:"sentry_project"`
-	LinearTeamID       string   `yaml:"linear_team_id"`
-	LinearProjectID    string   `yaml:"linear_project_id"`
-	LinearProjectName  string   `yaml:"linear_project_name"`
-	DefaultLabels      []string `yaml:"default_labels"`
+	SentryOrganization string               `yaml:"sentry_organization"`
+	SentryProject      string               `yaml:"sentry_project"`
+	LinearTeamID       string               `yaml:"linear_team_id"`
+	LinearProjectID    string               `yaml:"linear_project_id"`
+	LinearProjectName  string               `yaml:"linear_project_name"`
+	Repository         string               `yaml:"repository,omitempty"`     // GitHub "owner/repo"
+	Milestone          string               `yaml:"milestone,omitempty"`      // GitHub milestone number, optional
+	MilestoneName      string               `yaml:"milestone_name,omitempty"` // GitHub milestone or repository label
+	DefaultLabels      []string             `yaml:"default_labels"`
+	IssueQuery         BugManagerIssueQuery `yaml:"issue_query,omitempty"`
+	Assignment         BugManagerAssignment `yaml:"assignment,omitempty"`
+	Template           string               `yaml:"template,omitempty"` // Issue template applied to synced issues
+}
+
+// TeamID returns the tracker team issues are filed in: the GitHub repository, or the
+// Linear team. GitHub mappings written before repository existed keep it in linear_team_id.
+func (m BugManagerProjectMapping) TeamID() string {
+	if m.Repository != "" {
+		return m.Repository
+	}
+	return m.LinearTeamID
+}
+
+// ProjectID returns the GitHub milestone or Linear project issues are added to, if any
+func (m BugManagerProjectMapping) ProjectID() string {
+	if m.Milestone != "" {
+		return m.Milestone
+	}
+	return m.LinearProjectID
+}
+
+// ProjectName returns the display name of the mapping's milestone or project
+func (m BugManagerProjectMapping) ProjectName() string {
+	if m.MilestoneName != "" {
+		return m.MilestoneName
+	}
+	return m.LinearProjectName
+}
+
+// BugManagerAssignment configures automatic assignment of synced issues
+type BugManagerAssignment struct {
+	RepoPath         string            `yaml:"repo_path,omitempty"`         // Local checkout used to read CODEOWNERS
+	UseCodeowners    bool              `yaml:"use_codeowners,omitempty"`    // Match the top in-app frame against CODEOWNERS
+	PathOwners       map[string]string `yaml:"path_owners,omitempty"`       // CODEOWNERS-style pattern -> owner, checked before CODEOWNERS
+	FallbackAssignee string            `yaml:"fallback_assignee,omitempty"` // Used when no rule matches
+	RoundRobin       bool              `yaml:"round_robin,omitempty"`       // Rotate between owners when a rule lists several; the position is kept in the sync state
+	SuspectCommits   string            `yaml:"suspect_commits,omitempty"`   // "mention" or "assign" the likely author of a bug, "off" to skip the lookup
+}
+
+// BugManagerIssueQuery customizes which Sentry issues are listed for a mapping
+type BugManagerIssueQuery struct {
+	Environment string `yaml:"environment,omitempty"`
+	Release     string `yaml:"release,omitempty"`
+	Assigned    string `yaml:"assigned,omitempty"`     // "me", "none", "#team" or a username/email
+	Regressed   bool   `yaml:"regressed,omitempty"`    // Only issues that regressed
+	Custom      string `yaml:"custom,omitempty"`       // Appended verbatim to the search query
+	Sort        string `yaml:"sort,omitempty"`         // "date", "new", "freq", "user" or "priority"
+	StatsPeriod string `yaml:"stats_period,omitempty"` // "24h", "14d" or "none"
 }
 
 // FlutterConfig holds Flutter-related configuration
@@ -136,6 +218,66 @@ type FlutterProject struct {
 	LastBuildNum string `yaml:"last_build_num"`
 }
 
+// ReleaseConfig holds release manager configuration
+type ReleaseConfig struct {
+	Repos map[string]*ReleaseRepo `yaml:"repos,omitempty"` // Keyed by name; matched to the current checkout by path
+}
+
+// ReleaseRepo configures releases of one repository
+type ReleaseRepo struct {
+	Path          string            `yaml:"path"`
+	Branch        string            `yaml:"branch,omitempty"`                 // Release branch, defaults to "main"
+	Remote        string            `yaml:"remote,omitempty"`                 // Defaults to "origin"
+	RequireCI     bool              `yaml:"require_ci,omitempty"`             // Require green GitHub checks on HEAD before releasing
+	RequireSigned bool              `yaml:"require_signed_commits,omitempty"` // Require signed commits since the last tag before releasing
+	Env           map[string]string `yaml:"env,omitempty"`                    // Environment of every step and hook
+	Steps         []ReleaseStep     `yaml:"steps,omitempty"`                  // Pipeline steps in order, e.g. build, test, lint, package, publish
+	Hooks         ReleaseHooks      `yaml:"hooks,omitempty"`
+	VersionFiles  []VersionFile     `yaml:"version_files,omitempty"` // Bumped and committed as "chore(release): vX.Y.Z" before tagging
+	Build         *ReleaseBuild     `yaml:"build,omitempty"`         // Cross-compiled release archives; Go modules get defaults
+	Packages      []ReleasePackage  `yaml:"packages,omitempty"`      // Monorepo packages released separately with prefixed tags
+}
+
+// ReleasePackage is a separately versioned package of a monorepo
+type ReleasePackage struct {
+	Name         string        `yaml:"name"`
+	Path         string        `yaml:"path"`                    // Relative to the repository; only commits touching it count
+	TagPrefix    string        `yaml:"tag_prefix,omitempty"`    // Defaults to "<name>/", giving tags like api/v1.4.0
+	VersionFiles []VersionFile `yaml:"version_files,omitempty"` // Relative to the package path
+}
+
+// ReleaseBuild configures the cross-compiled archives of a Go release
+type ReleaseBuild struct {
+	Binary  string   `yaml:"binary,omitempty"`  // Defaults to the last element of the go.mod module path
+	Package string   `yaml:"package,omitempty"` // Main package, defaults to "."
+	Targets []string `yaml:"targets,omitempty"` // GOOS/GOARCH pairs, e.g. linux/amd64
+	Output  string   `yaml:"output,omitempty"`  // Defaults to "dist"
+	Ldflags string   `yaml:"ldflags,omitempty"` // Added to the main.Version and main.BuildTime flags
+	Files   []string `yaml:"files,omitempty"`   // Archived with the binary, defaults to LICENSE and README
+}
+
+// ReleaseStep is a shell command of a release pipeline
+type ReleaseStep struct {
+	Name      string            `yaml:"name"`
+	Run       string            `yaml:"run"`           // Run through the shell
+	Dir       string            `yaml:"dir,omitempty"` // Working directory, relative to the repository
+	Env       map[string]string `yaml:"env,omitempty"`
+	OnFailure string            `yaml:"on_failure,omitempty"` // abort (default), continue or prompt
+}
+
+// VersionFile is a file holding the project version
+type VersionFile struct {
+	Path    string `yaml:"path"`              // Relative to the repository
+	Format  string `yaml:"format,omitempty"`  // json, pubspec, cargo, pyproject, go or helm; detected from the file name
+	Pattern string `yaml:"pattern,omitempty"` // Regex whose first group is the version; replaces the format
+}
+
+// ReleaseHooks are steps run around tagging a release
+type ReleaseHooks struct {
+	PreTag  []ReleaseStep `yaml:"pre_tag,omitempty"`  // Before the tag is created; a failure aborts the release
+	PostTag []ReleaseStep `yaml:"post_tag,omitempty"` // After the branch and tag are pushed
+}
+
 // New creates a new default configuration
 func New() *Config {
 	homeDir, _ := os.UserHomeDir()
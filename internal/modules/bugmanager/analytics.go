@@ -0,0 +1,423 @@
+package bugmanager
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kkz6/devtools/internal/config"
+	"github.com/kkz6/devtools/internal/types"
+	"github.com/kkz6/devtools/internal/ui"
+)
+
+const (
+	analyticsTopIssues      = 10  // Issues listed per "top" table
+	analyticsReleases       = 10  // Most recent releases listed
+	analyticsHotSpotSample  = 100 // Most frequent issues grouped into culprit hot spots
+	analyticsHotSpotEntries = 10  // Hot spots listed
+)
+
+// analyticsWindows are the time ranges a report can cover
+var analyticsWindows = []string{"24h", "7d", "14d", "30d"}
+
+// analyticsReport holds Sentry analytics for every mapping of a connection
+type analyticsReport struct {
+	Connection  string
+	Window      string
+	GeneratedAt time.Time
+	Projects    []*projectAnalytics
+}
+
+// projectAnalytics holds the analytics of a single mapped Sentry project
+type projectAnalytics struct {
+	Organization string
+	Project      string
+	Stats        *SentryEventStats
+	Open         int
+	New          int
+	Regressed    int
+	TopByUsers   []SentryIssue
+	TopByEvents  []SentryIssue
+	Releases     []SentryReleaseSummary
+	HotSpots     []culpritHotSpot
+	Errors       []string // Parts of the report that could not be fetched
+}
+
+// culpritHotSpot aggregates issues sharing the same culprit
+type culpritHotSpot struct {
+	Culprit string
+	Issues  int
+	Events  int64
+	Users   int
+}
+
+// sentryAnalytics builds an analytics report for a connection and offers to export it
+func (m *Module) sentryAnalytics(cfg *config.Config) error {
+	conn, err := m.selectConnection(cfg, "Select connection to report on")
+	if err != nil {
+		return err
+	}
+
+	windowOptions := make([]string, len(analyticsWindows))
+	for i, window := range analyticsWindows {
+		windowOptions[i] = fmt.Sprintf("Last %s", window)
+	}
+	windowChoice, err := ui.SelectFromList("Select reporting window", windowOptions)
+	if err != nil {
+		if err.Error() == "cancelled" {
+			return types.ErrNavigateBack
+		}
+		return err
+	}
+
+	report, err := m.buildAnalyticsReport(cfg, conn, analyticsWindows[windowChoice], true)
+	if err != nil {
+		ui.ShowError(err.Error())
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Println(report.Terminal())
+
+	for {
+		options := []string{"Export as Markdown", "Export as CSV", "Done"}
+		choice, err := ui.SelectFromList("Export report", options)
+		if err != nil || choice == 2 {
+			return nil
+		}
+
+		format, ext := "markdown", ".md"
+		if choice == 1 {
+			format, ext = "csv", ".csv"
+		}
+		defaultPath := fmt.Sprintf("sentry-report-%s-%s%s",
+			strings.ToLower(strings.ReplaceAll(conn.Name, " ", "-")), report.GeneratedAt.Format("2006-01-02"), ext)
+
+		path, err := ui.GetInput(fmt.Sprintf("Output file (default: %s)", defaultPath), defaultPath, false, nil)
+		if err != nil {
+			if err.Error() != "cancelled" {
+				return err
+			}
+			path = defaultPath
+		}
+
+		if err := report.export(expandHome(path), format); err != nil {
+			ui.ShowError(fmt.Sprintf("Failed to export report: %v", err))
+			continue
+		}
+		ui.ShowSuccess(fmt.Sprintf("Report exported to %s", path))
+	}
+}
+
+// buildAnalyticsReport collects analytics for every mapping of a connection.
+// Failures of individual requests are recorded in the project instead of aborting the report.
+func (m *Module) buildAnalyticsReport(cfg *config.Config, conn *config.BugManagerConnection, window string, showProgress bool) (*analyticsReport, error) {
+	sentryInstance := cfg.Sentry.Instances[conn.SentryInstance]
+	if sentryInstance == nil {
+		return nil, fmt.Errorf("Sentry instance '%s' of connection '%s' not found", conn.SentryInstance, conn.Name)
+	}
+	if len(conn.ProjectMappings) == 0 {
+		return nil, fmt.Errorf("connection '%s' has no project mappings", conn.Name)
+	}
+
+	client := NewSentryClient(sentryInstance.APIKey, sentryInstance.BaseURL)
+
+	// The stats endpoints take numeric project IDs while mappings store slugs
+	projects, err := client.GetProjects()
+	if err != nil {
+		return nil, err
+	}
+	projectIDs := make(map[string]string)
+	for _, project := range projects {
+		projectIDs[project.OrganizationSlug+"/"+project.Slug] = project.ID
+	}
+
+	report := &analyticsReport{
+		Connection:  conn.Name,
+		Window:      window,
+		GeneratedAt: time.Now(),
+	}
+
+	var progressBar *ui.ProgressBar
+	if showProgress {
+		progressBar = ui.NewProgressBar("Collecting Sentry analytics", len(conn.ProjectMappings))
+	}
+	for i := range conn.ProjectMappings {
+		mapping := &conn.ProjectMappings[i]
+		if progressBar != nil {
+			progressBar.UpdateTitle(fmt.Sprintf("Collecting %s/%s", mapping.SentryOrganization, mapping.SentryProject))
+		}
+
+		project := &projectAnalytics{Organization: mapping.SentryOrganization, Project: mapping.SentryProject}
+		if projectID, ok := projectIDs[mapping.SentryOrganization+"/"+mapping.SentryProject]; ok {
+			m.collectProjectAnalytics(client, project, projectID, window)
+		} else {
+			project.Errors = append(project.Errors, "project not found in Sentry")
+		}
+		report.Projects = append(report.Projects, project)
+
+		if progressBar != nil {
+			progressBar.Increment()
+		}
+	}
+	if progressBar != nil {
+		progressBar.Finish()
+	}
+
+	return report, nil
+}
+
+// collectProjectAnalytics fills in the analytics of one project
+func (m *Module) collectProjectAnalytics(client *SentryClient, project *projectAnalytics, projectID, window string) {
+	org := project.Organization
+	fail := func(part string, err error) {
+		project.Errors = append(project.Errors, fmt.Sprintf("%s: %v", part, err))
+	}
+
+	if stats, err := client.GetEventStats(org, projectID, window); err != nil {
+		fail("event stats", err)
+	} else {
+		project.Stats = stats
+	}
+
+	// Only the number of matches is needed for the counts
+	count := func(query string) (int, error) {
+		_, hits, err := client.SearchOrganizationIssues(org, projectID, IssueQuery{Query: query, StatsPeriod: window, Limit: 1})
+		return hits, err
+	}
+	var err error
+	if project.Open, err = count("is:unresolved"); err != nil {
+		fail("open issues", err)
+	}
+	if project.New, err = count(fmt.Sprintf("firstSeen:-%s", window)); err != nil {
+		fail("new issues", err)
+	}
+	if project.Regressed, err = count("is:regressed"); err != nil {
+		fail("regressed issues", err)
+	}
+
+	if project.TopByUsers, _, err = client.SearchOrganizationIssues(org, projectID, IssueQuery{
+		Query: "is:unresolved", Sort: "user", StatsPeriod: window, Limit: analyticsTopIssues,
+	}); err != nil {
+		fail("top issues by users", err)
+	}
+
+	sample, _, err := client.SearchOrganizationIssues(org, projectID, IssueQuery{
+		Query: "is:unresolved", Sort: "freq", StatsPeriod: window, Limit: analyticsHotSpotSample,
+	})
+	if err != nil {
+		fail("top issues by events", err)
+	} else {
+		project.TopByEvents = sample[:min(len(sample), analyticsTopIssues)]
+		project.HotSpots = culpritHotSpots(sample, analyticsHotSpotEntries)
+	}
+
+	if project.Releases, err = client.GetReleases(org, projectID, analyticsReleases); err != nil {
+		fail("releases", err)
+	}
+}
+
+// culpritHotSpots groups issues by culprit and returns the culprits with the most events
+func culpritHotSpots(issues []SentryIssue, limit int) []culpritHotSpot {
+	byCulprit := make(map[string]*culpritHotSpot)
+	for _, issue := range issues {
+		culprit := strings.TrimSpace(issue.Culprit)
+		if culprit == "" {
+			continue
+		}
+		spot, ok := byCulprit[culprit]
+		if !ok {
+			spot = &culpritHotSpot{Culprit: culprit}
+			byCulprit[culprit] = spot
+		}
+		spot.Issues++
+		spot.Events += issueEventCount(issue)
+		spot.Users += issue.UserCount
+	}
+
+	spots := make([]culpritHotSpot, 0, len(byCulprit))
+	for _, spot := range byCulprit {
+		spots = append(spots, *spot)
+	}
+	sort.Slice(spots, func(i, j int) bool {
+		if spots[i].Events != spots[j].Events {
+			return spots[i].Events > spots[j].Events
+		}
+		return spots[i].Culprit < spots[j].Culprit
+	})
+
+	if len(spots) > limit {
+		spots = spots[:limit]
+	}
+	return spots
+}
+
+// issueEventCount parses the event count Sentry reports as a string
+func issueEventCount(issue SentryIssue) int64 {
+	count, _ := strconv.ParseInt(issue.Count, 10, 64)
+	return count
+}
+
+// summaryTable lists the totals of every project
+func (r *analyticsReport) summaryTable() *ui.Table {
+	table := ui.NewTable(fmt.Sprintf("Summary (last %s)", r.Window))
+	table.AddHeader("Project", "Events", "Dropped", "Open", "New", "Regressed")
+	for _, project := range r.Projects {
+		events, dropped := "-", "-"
+		if project.Stats != nil {
+			events = fmt.Sprintf("%d", project.Stats.Totals["accepted"])
+			dropped = fmt.Sprintf("%d", project.Stats.Dropped())
+		}
+		table.AddRow(project.Organization+"/"+project.Project, events, dropped,
+			fmt.Sprintf("%d", project.Open), fmt.Sprintf("%d", project.New), fmt.Sprintf("%d", project.Regressed))
+	}
+	return table
+}
+
+// projectTables returns the detail tables of a project.
+// Markdown tables link issues to Sentry.
+func (p *projectAnalytics) projectTables(markdown bool) []*ui.Table {
+	issueTable := func(title string, issues []SentryIssue) *ui.Table {
+		table := ui.NewTable(title)
+		table.AddHeader("Issue", "Title", "Users", "Events", "Level", "First Seen")
+		for _, issue := range issues {
+			id := issue.ShortID
+			if markdown {
+				id = fmt.Sprintf("[%s](%s)", issue.ShortID, issue.Permalink)
+			}
+			table.AddRow(id, truncate(issue.Title, 60), fmt.Sprintf("%d", issue.UserCount),
+				issue.Count, issue.Level, issue.FirstSeen.Format("2006-01-02"))
+		}
+		return table
+	}
+
+	tables := []*ui.Table{
+		issueTable("Top Issues by Users", p.TopByUsers),
+		issueTable("Top Issues by Events", p.TopByEvents),
+	}
+
+	releases := ui.NewTable("Issues per Release")
+	releases.AddHeader("Release", "Created", "New Issues")
+	for _, release := range p.Releases {
+		version := release.ShortVersion
+		if version == "" {
+			version = release.Version
+		}
+		releases.AddRow(version, release.DateCreated.Format("2006-01-02"), fmt.Sprintf("%d", release.NewGroups))
+	}
+	tables = append(tables, releases)
+
+	hotSpots := ui.NewTable("Culprit Hot Spots")
+	hotSpots.AddHeader("Culprit", "Issues", "Events", "Users")
+	for _, spot := range p.HotSpots {
+		hotSpots.AddRow(truncate(spot.Culprit, 70), fmt.Sprintf("%d", spot.Issues),
+			fmt.Sprintf("%d", spot.Events), fmt.Sprintf("%d", spot.Users))
+	}
+	tables = append(tables, hotSpots)
+
+	return tables
+}
+
+// Terminal renders the report for the terminal
+func (r *analyticsReport) Terminal() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("205")).
+		MarginBottom(1)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Sentry Report: %s", r.Connection)))
+	b.WriteString("\n")
+	b.WriteString(r.summaryTable().Render())
+	b.WriteString("\n")
+
+	for _, project := range r.Projects {
+		b.WriteString("\n")
+		b.WriteString(titleStyle.Render(fmt.Sprintf("%s/%s", project.Organization, project.Project)))
+		b.WriteString("\n")
+		for _, message := range project.Errors {
+			b.WriteString(fmt.Sprintf("⚠ %s\n", message))
+		}
+		for _, table := range project.projectTables(false) {
+			b.WriteString(table.Render())
+			b.WriteString("\n\n")
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// Markdown renders the report as a markdown document
+func (r *analyticsReport) Markdown() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# Sentry Report: %s\n\n", r.Connection))
+	b.WriteString(fmt.Sprintf("_Last %s, generated %s_\n\n", r.Window, r.GeneratedAt.Format("2006-01-02 15:04")))
+	b.WriteString(r.summaryTable().Markdown())
+
+	for _, project := range r.Projects {
+		b.WriteString(fmt.Sprintf("\n## %s/%s\n\n", project.Organization, project.Project))
+		for _, message := range project.Errors {
+			b.WriteString(fmt.Sprintf("> ⚠ %s\n", message))
+		}
+		if len(project.Errors) > 0 {
+			b.WriteString("\n")
+		}
+		for _, table := range project.projectTables(true) {
+			b.WriteString(table.Markdown())
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
+
+// WriteCSV writes the report as CSV. Every table becomes a block whose rows
+// start with the project and table name, separated by an empty line.
+func (r *analyticsReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writeTable := func(project, name string, table *ui.Table) {
+		for i, record := range table.Records() {
+			prefix := []string{project, name}
+			if i == 0 {
+				prefix = []string{"project", "table"}
+			}
+			writer.Write(append(prefix, record...))
+		}
+		writer.Write(nil)
+	}
+
+	writeTable("all", "Summary", r.summaryTable())
+	for _, project := range r.Projects {
+		name := project.Organization + "/" + project.Project
+		for _, table := range project.projectTables(false) {
+			writeTable(name, table.Title(), table)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// export writes the report to path as markdown or CSV
+func (r *analyticsReport) export(path, format string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if format == "csv" {
+		return r.WriteCSV(file)
+	}
+	_, err = file.WriteString(r.Markdown())
+	return err
+}
@@ -0,0 +1,462 @@
+package bugmanager
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kkz6/devtools/internal/config"
+	"github.com/kkz6/devtools/internal/types"
+	"github.com/kkz6/devtools/internal/ui"
+)
+
+// codeownersLocations lists where GitHub looks for a CODEOWNERS file, in order
+var codeownersLocations = []string{
+	".github/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+}
+
+// OwnerRule is a single CODEOWNERS-style rule
+type OwnerRule struct {
+	Pattern string
+	Owners  []string
+}
+
+// ownerSource is a named set of owner rules evaluated during assignment
+type ownerSource struct {
+	name  string
+	rules []OwnerRule
+}
+
+// AssignmentResult describes who a synced issue is assigned to and why
+type AssignmentResult struct {
+	User   *TrackerUser
+	Owner  string // Owner as written in the rule, e.g. "@jane" or "jane@example.com"
+	Reason string
+}
+
+// topInAppFrame returns the most recent in-app frame of an event
+func topInAppFrame(event *SentryEvent) *SentryFrame {
+	if event == nil {
+		return nil
+	}
+	for _, exception := range event.Exception.Values {
+		frames := exception.Stacktrace.Frames
+		for i := len(frames) - 1; i >= 0; i-- {
+			if frames[i].InApp && frames[i].Filename != "" {
+				return &frames[i]
+			}
+		}
+	}
+	return nil
+}
+
+// loadCodeowners reads and parses the CODEOWNERS file of a repository
+func loadCodeowners(repoPath string) ([]OwnerRule, error) {
+	repoPath = expandHome(repoPath)
+	for _, location := range codeownersLocations {
+		file, err := os.Open(filepath.Join(repoPath, location))
+		if err != nil {
+			continue
+		}
+		defer file.Close()
+
+		var rules []OwnerRule
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			fields := strings.Fields(line)
+			rules = append(rules, OwnerRule{Pattern: fields[0], Owners: fields[1:]})
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", location, err)
+		}
+		return rules, nil
+	}
+
+	return nil, fmt.Errorf("no CODEOWNERS file found in %s", repoPath)
+}
+
+// matchOwners returns the rule matching the file; as in CODEOWNERS, the last matching rule wins
+func matchOwners(rules []OwnerRule, file string) *OwnerRule {
+	var matched *OwnerRule
+	for i := range rules {
+		if matchCodeownersPattern(rules[i].Pattern, file) {
+			matched = &rules[i]
+		}
+	}
+	return matched
+}
+
+// matchCodeownersPattern reports whether a repository-relative path matches a
+// CODEOWNERS (gitignore-style) pattern
+func matchCodeownersPattern(pattern, file string) bool {
+	file = strings.TrimPrefix(file, "/")
+
+	if pattern == "*" {
+		return true
+	}
+
+	anchored := strings.HasPrefix(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	// Patterns without a slash (other than a trailing one) match at any depth
+	if !anchored && !strings.Contains(strings.TrimSuffix(pattern, "/"), "/") {
+		pattern = "**/" + pattern
+	}
+
+	// A trailing slash matches everything inside the directory
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+
+	patternSegments := strings.Split(pattern, "/")
+	fileSegments := strings.Split(file, "/")
+	if matchGlobSegments(patternSegments, fileSegments) {
+		return true
+	}
+
+	// A pattern naming a directory without wildcards also matches its contents
+	last := patternSegments[len(patternSegments)-1]
+	if strings.ContainsAny(last, "*?[") {
+		return false
+	}
+	return matchGlobSegments(append(patternSegments, "**"), fileSegments)
+}
+
+// matchGlobSegments matches path segments against pattern segments supporting "**"
+func matchGlobSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchGlobSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchGlobSegments(pattern[1:], segments[1:])
+}
+
+// repoRelativePath maps a stack frame filename onto a path in the local repository.
+// Frames often carry a deployment prefix (e.g. /usr/src/app/), so the longest
+// suffix that exists in the checkout is used.
+func repoRelativePath(repoPath, filename string) string {
+	filename = filepath.ToSlash(strings.TrimPrefix(filename, "./"))
+	filename = strings.TrimPrefix(filename, "/")
+
+	if repoPath == "" {
+		return filename
+	}
+
+	repoPath = expandHome(repoPath)
+	parts := strings.Split(filename, "/")
+	for i := range parts {
+		candidate := strings.Join(parts[i:], "/")
+		if info, err := os.Stat(filepath.Join(repoPath, candidate)); err == nil && !info.IsDir() {
+			return candidate
+		}
+	}
+
+	return filename
+}
+
+// resolveTrackerUser finds the tracker user for a CODEOWNERS owner or configured identifier
+func resolveTrackerUser(users []TrackerUser, owner string) *TrackerUser {
+	owner = strings.TrimSpace(owner)
+	// GitHub teams (@org/team) cannot be mapped to a single user
+	if owner == "" || strings.Contains(owner, "/") {
+		return nil
+	}
+
+	handle := strings.ToLower(strings.TrimPrefix(owner, "@"))
+	for i := range users {
+		user := &users[i]
+		if !user.Active {
+			continue
+		}
+		email := strings.ToLower(user.Email)
+		if email == handle ||
+			strings.ToLower(user.DisplayName) == handle ||
+			strings.ToLower(user.Name) == handle ||
+			user.ID == owner {
+			return user
+		}
+	}
+
+	// Fall back to matching the local part of the email against a handle
+	for i := range users {
+		user := &users[i]
+		if user.Active && strings.ToLower(strings.SplitN(user.Email, "@", 2)[0]) == handle {
+			return user
+		}
+	}
+
+	return nil
+}
+
+// resolveAssignee determines the assignee for a synced issue based on the
+// mapping's path owners, the repository CODEOWNERS, and the fallback assignee.
+// Round-robin rotation is kept in state; without state every rotation starts at the first owner.
+func (m *Module) resolveAssignee(tracker IssueTracker, mapping *config.BugManagerProjectMapping, event *SentryEvent, state *SyncState) (*AssignmentResult, error) {
+	assignment := &mapping.Assignment
+	if len(assignment.PathOwners) == 0 && !assignment.UseCodeowners && assignment.FallbackAssignee == "" {
+		return nil, nil
+	}
+
+	users, err := tracker.GetUsers(mapping.TeamID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s users: %w", tracker.Name(), err)
+	}
+
+	if frame := topInAppFrame(event); frame != nil {
+		file := repoRelativePath(assignment.RepoPath, frame.Filename)
+
+		var sources []ownerSource
+
+		if len(assignment.PathOwners) > 0 {
+			patterns := make([]string, 0, len(assignment.PathOwners))
+			for pattern := range assignment.PathOwners {
+				patterns = append(patterns, pattern)
+			}
+			// Longer patterns are more specific, so they are evaluated last and win
+			sort.Slice(patterns, func(i, j int) bool { return len(patterns[i]) < len(patterns[j]) })
+
+			rules := make([]OwnerRule, 0, len(patterns))
+			for _, pattern := range patterns {
+				rules = append(rules, OwnerRule{Pattern: pattern, Owners: strings.Fields(assignment.PathOwners[pattern])})
+			}
+			sources = append(sources, ownerSource{name: "path owners", rules: rules})
+		}
+
+		if assignment.UseCodeowners && assignment.RepoPath != "" {
+			rules, err := loadCodeowners(assignment.RepoPath)
+			if err != nil {
+				ui.ShowWarning(fmt.Sprintf("Could not load CODEOWNERS: %v", err))
+			} else {
+				sources = append(sources, ownerSource{name: "CODEOWNERS", rules: rules})
+			}
+		}
+
+		for _, source := range sources {
+			rule := matchOwners(source.rules, file)
+			if rule == nil || len(rule.Owners) == 0 {
+				continue
+			}
+
+			owners := rule.Owners
+			if assignment.RoundRobin && len(owners) > 1 && state != nil {
+				// Rotate the starting owner so work is spread across the rule's owners
+				start := state.NextRotation(mapping) % len(owners)
+				owners = append(append([]string{}, owners[start:]...), owners[:start]...)
+			}
+
+			for _, owner := range owners {
+				if user := resolveTrackerUser(users, owner); user != nil {
+					return &AssignmentResult{
+						User:   user,
+						Owner:  owner,
+						Reason: fmt.Sprintf("%s rule `%s` matched `%s`", source.name, rule.Pattern, file),
+					}, nil
+				}
+			}
+		}
+	}
+
+	if assignment.FallbackAssignee != "" {
+		if user := resolveTrackerUser(users, assignment.FallbackAssignee); user != nil {
+			return &AssignmentResult{
+				User:   user,
+				Owner:  assignment.FallbackAssignee,
+				Reason: "fallback assignee",
+			}, nil
+		}
+		return nil, fmt.Errorf("fallback assignee '%s' is not an active %s user", assignment.FallbackAssignee, tracker.Name())
+	}
+
+	return nil, nil
+}
+
+// editAssignment configures automatic assignment for a project mapping
+func (m *Module) editAssignment(cfg *config.Config, mapping *config.BugManagerProjectMapping) error {
+	for {
+		assignment := &mapping.Assignment
+
+		codeowners := "Off"
+		if assignment.UseCodeowners {
+			codeowners = "On"
+		}
+		roundRobin := "Off"
+		if assignment.RoundRobin {
+			roundRobin = "On"
+		}
+
+		options := []string{
+			fmt.Sprintf("Repository Path (current: %s)", valueOrNone(assignment.RepoPath)),
+			fmt.Sprintf("Use CODEOWNERS (current: %s)", codeowners),
+			fmt.Sprintf("Manage Path Owners (%d)", len(assignment.PathOwners)),
+			fmt.Sprintf("Fallback Assignee (current: %s)", valueOrNone(assignment.FallbackAssignee)),
+			fmt.Sprintf("Round-Robin Between Owners (current: %s)", roundRobin),
+			fmt.Sprintf("Suspect Commit Author (current: %s)", suspectModeLabel(assignment.SuspectCommits)),
+			"Back",
+		}
+
+		choice, err := ui.SelectFromList("Auto-Assignment", options)
+		if err != nil {
+			if err.Error() == "cancelled" {
+				return types.ErrNavigateBack
+			}
+			return err
+		}
+
+		switch choice {
+		case 0: // Repository path
+			value, err := ui.GetInput("Local repository path", assignment.RepoPath, false, func(s string) error {
+				s = strings.TrimSpace(s)
+				if s == "" {
+					return nil
+				}
+				if info, err := os.Stat(expandHome(s)); err != nil || !info.IsDir() {
+					return fmt.Errorf("directory does not exist")
+				}
+				return nil
+			})
+			if err != nil {
+				continue
+			}
+			assignment.RepoPath = strings.TrimSpace(value)
+		case 1: // CODEOWNERS
+			assignment.UseCodeowners = !assignment.UseCodeowners
+			if assignment.UseCodeowners && assignment.RepoPath != "" {
+				if rules, err := loadCodeowners(assignment.RepoPath); err != nil {
+					ui.ShowWarning(err.Error())
+				} else {
+					ui.ShowInfo(fmt.Sprintf("Loaded %d CODEOWNERS rules", len(rules)))
+				}
+			}
+		case 2: // Path owners
+			if err := m.managePathOwners(cfg, assignment); err != nil && err != types.ErrNavigateBack {
+				return err
+			}
+		case 3: // Fallback
+			value, err := ui.GetInput("Fallback assignee (email, display name or GitHub login, empty for none)", assignment.FallbackAssignee, false, nil)
+			if err != nil {
+				continue
+			}
+			assignment.FallbackAssignee = strings.TrimSpace(value)
+		case 4: // Round robin
+			assignment.RoundRobin = !assignment.RoundRobin
+		case 5: // Suspect commits
+			modes := []string{suspectDescribe, suspectMention, suspectAssign, suspectOff}
+			options := make([]string, len(modes))
+			for i, mode := range modes {
+				options[i] = suspectModeLabel(mode)
+			}
+			mode, err := ui.SelectFromList("What to do with the author of the suspect commit", options)
+			if err != nil {
+				continue
+			}
+			assignment.SuspectCommits = modes[mode]
+		case 6: // Back
+			return types.ErrNavigateBack
+		}
+
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+	}
+}
+
+// managePathOwners adds and removes path→owner rules of a mapping
+func (m *Module) managePathOwners(cfg *config.Config, assignment *config.BugManagerAssignment) error {
+	for {
+		patterns := make([]string, 0, len(assignment.PathOwners))
+		for pattern := range assignment.PathOwners {
+			patterns = append(patterns, pattern)
+		}
+		sort.Strings(patterns)
+
+		options := []string{"Add Path Owner"}
+		for _, pattern := range patterns {
+			options = append(options, fmt.Sprintf("Remove: %s → %s", pattern, assignment.PathOwners[pattern]))
+		}
+		options = append(options, "Back")
+
+		choice, err := ui.SelectFromList("Path Owners", options)
+		if err != nil || choice == len(options)-1 {
+			return types.ErrNavigateBack
+		}
+
+		if choice == 0 {
+			pattern, err := ui.GetInput("Path pattern (CODEOWNERS syntax, e.g. src/payments/)", "", false, func(s string) error {
+				if strings.TrimSpace(s) == "" {
+					return fmt.Errorf("pattern must not be empty")
+				}
+				return nil
+			})
+			if err != nil {
+				continue
+			}
+			owners, err := ui.GetInput("Owners (space-separated emails, display names or GitHub logins)", "", false, func(s string) error {
+				if strings.TrimSpace(s) == "" {
+					return fmt.Errorf("at least one owner is required")
+				}
+				return nil
+			})
+			if err != nil {
+				continue
+			}
+			if assignment.PathOwners == nil {
+				assignment.PathOwners = make(map[string]string)
+			}
+			assignment.PathOwners[strings.TrimSpace(pattern)] = strings.TrimSpace(owners)
+		} else {
+			delete(assignment.PathOwners, patterns[choice-1])
+		}
+
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+	}
+}
+
+// suspectModeLabel describes a suspect commit mode
+func suspectModeLabel(mode string) string {
+	switch mode {
+	case suspectMention:
+		return "Describe and mention"
+	case suspectAssign:
+		return "Describe and assign when no rule matches"
+	case suspectOff:
+		return "Off"
+	default:
+		return "Describe only"
+	}
+}
+
+// valueOrNone returns the value or "none" when it is empty
+func valueOrNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
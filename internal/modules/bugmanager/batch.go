@@ -0,0 +1,348 @@
+package bugmanager
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/kkz6/devtools/internal/config"
+	"github.com/kkz6/devtools/internal/types"
+	"github.com/kkz6/devtools/internal/ui"
+)
+
+// syncWorkers bounds the number of concurrent Sentry requests during batch syncs
+const syncWorkers = 4
+
+// cachedTracker wraps an IssueTracker and remembers workflow states, users and
+// labels so a batch run looks each of them up only once per team
+type cachedTracker struct {
+	IssueTracker
+	mu     sync.Mutex
+	states map[string][]TrackerState
+	users  map[string][]TrackerUser
+	labels map[string]string // teamID + "/" + lowercase label name -> label ID
+}
+
+// newCachedTracker wraps tracker with a per-run lookup cache
+func newCachedTracker(tracker IssueTracker) *cachedTracker {
+	return &cachedTracker{
+		IssueTracker: tracker,
+		states:       make(map[string][]TrackerState),
+		users:        make(map[string][]TrackerUser),
+		labels:       make(map[string]string),
+	}
+}
+
+func (t *cachedTracker) GetWorkflowStates(teamID string) ([]TrackerState, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if states, ok := t.states[teamID]; ok {
+		return states, nil
+	}
+	states, err := t.IssueTracker.GetWorkflowStates(teamID)
+	if err != nil {
+		return nil, err
+	}
+	t.states[teamID] = states
+	return states, nil
+}
+
+func (t *cachedTracker) GetUsers(teamID string) ([]TrackerUser, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if users, ok := t.users[teamID]; ok {
+		return users, nil
+	}
+	users, err := t.IssueTracker.GetUsers(teamID)
+	if err != nil {
+		return nil, err
+	}
+	t.users[teamID] = users
+	return users, nil
+}
+
+// GetOrCreateLabel holds the lock while creating so concurrent callers never create a label twice
+func (t *cachedTracker) GetOrCreateLabel(teamID, name, color string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := teamID + "/" + strings.ToLower(name)
+	if id, ok := t.labels[key]; ok {
+		return id, nil
+	}
+	id, err := t.IssueTracker.GetOrCreateLabel(teamID, name, color)
+	if err != nil {
+		return "", err
+	}
+	t.labels[key] = id
+	return id, nil
+}
+
+// batchItem is a Sentry issue of a batch sync and its outcome
+type batchItem struct {
+	Issue        SentryIssue
+	Details      *SentryIssue
+	Event        *SentryEvent
+	Suspect      *SuspectCommit
+	TrackerIssue *TrackerIssue
+	Skipped      string   // Reason the issue was not synced
+	Warnings     []string // Problems that did not stop the sync
+	Err          error
+}
+
+// fetchSentryBatch fetches the details and latest event of every item with a bounded worker pool.
+// A missing event is only a warning, like in single-issue syncs.
+func (m *Module) fetchSentryBatch(source ErrorSource, suspects *suspectResolver, items []*batchItem, showProgress bool) {
+	jobs := make(chan *batchItem)
+	done := make(chan *batchItem)
+
+	workers := syncWorkers
+	if len(items) < workers {
+		workers = len(items)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range jobs {
+				details, err := source.GetIssueDetails(item.Issue.ID)
+				if err != nil {
+					item.Err = fmt.Errorf("failed to get issue details: %w", err)
+					done <- item
+					continue
+				}
+				item.Details = details
+
+				event, err := source.GetLatestEvent(item.Issue.ID)
+				if err != nil {
+					item.Warnings = append(item.Warnings, fmt.Sprintf("no event details: %v", err))
+				} else {
+					item.Event = event
+				}
+
+				item.Suspect, err = suspects.find(*details, item.Event)
+				if err != nil {
+					item.Warnings = append(item.Warnings, fmt.Sprintf("no suspect commit: %v", err))
+				}
+				done <- item
+			}
+		}()
+	}
+
+	go func() {
+		for _, item := range items {
+			jobs <- item
+		}
+		close(jobs)
+		wg.Wait()
+		close(done)
+	}()
+
+	if !showProgress {
+		for range done {
+		}
+		return
+	}
+
+	// The progress bar is only touched from this goroutine
+	progressBar := ui.NewProgressBar(fmt.Sprintf("Fetching %s issues", source.Name()), len(items))
+	for item := range done {
+		progressBar.UpdateTitle(fmt.Sprintf("Fetched %s", item.Issue.ShortID))
+		progressBar.Increment()
+	}
+	progressBar.Finish()
+}
+
+// syncBatch syncs every listed issue that has not been synced yet.
+// Failures are recorded per issue and reported at the end instead of aborting the run.
+func (m *Module) syncBatch(cfg *config.Config, conn *config.BugManagerConnection, mapping *config.BugManagerProjectMapping,
+	source ErrorSource, baseTracker IssueTracker, issues []SentryIssue) error {
+
+	state, err := LoadSyncState()
+	if err != nil {
+		ui.ShowWarning(fmt.Sprintf("Could not load sync state, already synced issues cannot be skipped: %v", err))
+	}
+
+	items := make([]*batchItem, len(issues))
+	for i, issue := range issues {
+		items[i] = &batchItem{Issue: issue}
+	}
+	stateKey := sourceStateKey(source, conn)
+	pending := skipSyncedItems(state, stateKey, items)
+
+	if len(pending) == 0 {
+		ui.ShowSuccess("All listed issues are already synced.")
+		return nil
+	}
+
+	tracker := newCachedTracker(baseTracker)
+	tmpl := findTemplate(cfg, mapping.Template)
+
+	if !ui.GetConfirmation(fmt.Sprintf("Create %d issues in %s (%d already synced)?",
+		len(pending), tracker.Name(), len(issues)-len(pending))) {
+		return types.ErrNavigateBack
+	}
+
+	// One initial state for the whole batch: the template's, or the user's choice
+	stateID, err := templateStateID(tracker, mapping.TeamID(), tmpl)
+	if err != nil {
+		ui.ShowWarning(err.Error())
+	}
+	if stateID == "" {
+		states, err := tracker.GetWorkflowStates(mapping.TeamID())
+		if err != nil {
+			ui.ShowWarning(fmt.Sprintf("Could not fetch workflow states: %v", err))
+		} else if len(states) > 0 {
+			options := []string{"Default State"}
+			for _, s := range states {
+				options = append(options, s.Name)
+			}
+			choice, err := ui.SelectFromList("Select the initial state for all issues", options)
+			if err != nil {
+				if err.Error() == "cancelled" {
+					return types.ErrNavigateBack
+				}
+				return err
+			}
+			if choice > 0 {
+				stateID = states[choice-1].ID
+			}
+		}
+	}
+
+	m.createBatch(cfg, mapping, source, stateKey, tracker, tmpl, stateID, state, pending, true)
+	if state != nil {
+		if err := state.Save(); err != nil {
+			ui.ShowWarning(fmt.Sprintf("Could not save sync state: %v", err))
+		}
+	}
+
+	fmt.Println()
+	created := printBatchReport(items)
+	fmt.Println()
+
+	failed := len(pending) - len(created)
+	if failed > 0 {
+		ui.ShowWarning(fmt.Sprintf("Created %d issues, %d failed.", len(created), failed))
+	} else {
+		ui.ShowSuccess(fmt.Sprintf("Created %d issues.", len(created)))
+	}
+
+	client, fromSentry := source.(*SentryClient)
+	if fromSentry && len(created) > 0 && ui.GetConfirmation(fmt.Sprintf("\nMark the %d synced issues as resolved in Sentry?", len(created))) {
+		for _, item := range created {
+			if err := client.ResolveIssue(item.Issue.ID); err != nil {
+				ui.ShowError(fmt.Sprintf("Failed to resolve %s in Sentry: %v", item.Issue.ShortID, err))
+			}
+		}
+		ui.ShowSuccess("Issues marked as resolved in Sentry")
+	}
+
+	return nil
+}
+
+// skipSyncedItems marks the items already synced to a tracker issue as skipped and returns the others
+func skipSyncedItems(state *SyncState, stateKey string, items []*batchItem) []*batchItem {
+	var pending []*batchItem
+	for _, item := range items {
+		if item.Skipped != "" {
+			continue
+		}
+		if state != nil {
+			if synced := state.Lookup(stateKey, item.Issue.ID); synced != nil {
+				item.Skipped = fmt.Sprintf("already synced to %s", synced.LinearURL)
+				continue
+			}
+		}
+		pending = append(pending, item)
+	}
+	return pending
+}
+
+// createBatch fetches the pending items from their source and creates their tracker issues.
+// Created issues are recorded in state under stateKey; the caller saves it.
+func (m *Module) createBatch(cfg *config.Config, mapping *config.BugManagerProjectMapping, source ErrorSource, stateKey string,
+	tracker IssueTracker, tmpl *config.BugManagerTemplate, stateID string,
+	state *SyncState, pending []*batchItem, showProgress bool) {
+
+	m.fetchSentryBatch(source, m.newSuspectResolver(cfg, mapping, source), pending, showProgress)
+
+	// Issues are created one at a time so labels and round-robin state stay consistent
+	var progressBar *ui.ProgressBar
+	if showProgress {
+		progressBar = ui.NewProgressBar(fmt.Sprintf("Creating issues in %s", tracker.Name()), len(pending))
+	}
+	for _, item := range pending {
+		if progressBar != nil {
+			progressBar.UpdateTitle(fmt.Sprintf("Creating %s", item.Issue.ShortID))
+		}
+		if item.Err == nil {
+			m.syncBatchItem(tracker, mapping, tmpl, stateID, state, item)
+			if item.Err == nil && state != nil {
+				state.Record(stateKey, *item.Details, item.TrackerIssue)
+			}
+		}
+		if progressBar != nil {
+			progressBar.Increment()
+		}
+	}
+	if progressBar != nil {
+		progressBar.Finish()
+	}
+}
+
+// syncBatchItem creates the tracker issue of one fetched batch item
+func (m *Module) syncBatchItem(tracker IssueTracker, mapping *config.BugManagerProjectMapping,
+	tmpl *config.BugManagerTemplate, stateID string, state *SyncState, item *batchItem) {
+
+	details := m.applyTemplateToBug(m.prepareBugDetails(*item.Details, item.Event), tmpl)
+
+	var assigneeID string
+	assignee, err := m.resolveAssignee(tracker, mapping, item.Event, state)
+	if err != nil {
+		item.Warnings = append(item.Warnings, fmt.Sprintf("no assignee: %v", err))
+	}
+	details, assignee = m.applySuspect(tracker, mapping, item.Suspect, details, assignee)
+	if assignee != nil {
+		assigneeID = assignee.User.ID
+	}
+
+	item.TrackerIssue, item.Err = m.createTrackerIssue(tracker, mapping, tmpl, *item.Details, details, stateID, assigneeID)
+	if item.Err != nil {
+		return
+	}
+
+	if _, err := m.createSyncSubIssues(tracker, mapping, tmpl, item.TrackerIssue, details); err != nil {
+		item.Warnings = append(item.Warnings, err.Error())
+	}
+}
+
+// printBatchReport prints the outcome of every issue of a batch sync and returns the created ones
+func printBatchReport(items []*batchItem) []*batchItem {
+	var created []*batchItem
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ISSUE\tTITLE\tRESULT")
+	for _, item := range items {
+		var result string
+		switch {
+		case item.Skipped != "":
+			result = "skipped: " + item.Skipped
+		case item.Err != nil:
+			result = fmt.Sprintf("failed: %v", item.Err)
+		default:
+			result = "created " + item.TrackerIssue.URL
+			created = append(created, item)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", item.Issue.ShortID, truncate(item.Issue.Title, 50), result)
+		for _, warning := range item.Warnings {
+			fmt.Fprintf(w, "\t\t  warning: %s\n", strings.ReplaceAll(warning, "\n", " "))
+		}
+	}
+	w.Flush()
+
+	return created
+}
@@ -0,0 +1,246 @@
+package bugmanager
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kkz6/devtools/internal/ui"
+	"gopkg.in/yaml.v3"
+)
+
+// bulkColumns are the CSV columns understood by bulk import
+var bulkColumns = []string{"title", "template", "description", "team", "project", "type", "priority", "labels", "state", "assignee"}
+
+// bulkRow is a single issue of a bulk import and its outcome
+type bulkRow struct {
+	Row      int // 1-based data row, excluding the CSV header
+	Draft    IssueDraft
+	Resolved *resolvedDraft
+	Issue    *TrackerIssue
+	Err      error
+}
+
+// loadBulkDrafts reads issue drafts from a CSV or YAML file.
+// Rows without a team use defaultTeam.
+func loadBulkDrafts(path, defaultTeam string) ([]IssueDraft, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var drafts []IssueDraft
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		drafts, err = parseBulkCSV(data)
+	case ".yaml", ".yml":
+		drafts, err = parseBulkYAML(data)
+	default:
+		return nil, fmt.Errorf("unsupported file type '%s' (use .csv, .yaml or .yml)", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range drafts {
+		if drafts[i].Team == "" {
+			drafts[i].Team = defaultTeam
+		}
+	}
+	return drafts, nil
+}
+
+// parseBulkCSV parses a CSV file with a header row naming bulkColumns
+func parseBulkCSV(data []byte) ([]IssueDraft, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		known := false
+		for _, column := range bulkColumns {
+			if name == column {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown CSV column '%s' (expected %s)", name, strings.Join(bulkColumns, ", "))
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, fmt.Errorf("CSV must have a 'title' column")
+	}
+
+	var drafts []IssueDraft
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		// Labels are separated by commas or semicolons within the cell
+		var labels []string
+		for _, label := range strings.FieldsFunc(field("labels"), func(r rune) bool { return r == ',' || r == ';' }) {
+			if label = strings.TrimSpace(label); label != "" {
+				labels = append(labels, label)
+			}
+		}
+
+		drafts = append(drafts, IssueDraft{
+			Title:       field("title"),
+			Template:    field("template"),
+			Description: field("description"),
+			Team:        field("team"),
+			Project:     field("project"),
+			Type:        field("type"),
+			Priority:    field("priority"),
+			Labels:      labels,
+			State:       field("state"),
+			Assignee:    field("assignee"),
+		})
+	}
+
+	return drafts, nil
+}
+
+// parseBulkYAML parses a YAML list of drafts, optionally nested under an "issues" key
+func parseBulkYAML(data []byte) ([]IssueDraft, error) {
+	var drafts []IssueDraft
+	if err := yaml.Unmarshal(data, &drafts); err == nil {
+		return drafts, nil
+	}
+
+	var wrapped struct {
+		Issues []IssueDraft `yaml:"issues"`
+	}
+	if err := yaml.Unmarshal(data, &wrapped); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	return wrapped.Issues, nil
+}
+
+// resolveBulk resolves every draft, recording validation errors per row
+func (m *Module) resolveBulk(resolver *draftResolver, drafts []IssueDraft) []bulkRow {
+	rows := make([]bulkRow, len(drafts))
+	for i, draft := range drafts {
+		rows[i] = bulkRow{Row: i + 1, Draft: draft}
+		rows[i].Resolved, rows[i].Err = resolver.resolve(draft)
+	}
+	return rows
+}
+
+// printBulkPreview shows what a bulk import will create
+func (m *Module) printBulkPreview(rows []bulkRow) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ROW\tTITLE\tTEAM\tPRIORITY\tLABELS\tSTATUS")
+	for _, row := range rows {
+		if row.Err != nil {
+			fmt.Fprintf(w, "%d\t%s\t%s\t-\t-\tinvalid: %v\n", row.Row, truncate(row.Draft.Title, 50), row.Draft.Team, row.Err)
+			continue
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\tok\n",
+			row.Row,
+			truncate(row.Resolved.Input.Title, 50),
+			row.Resolved.TeamName,
+			m.getPriorityName(row.Resolved.Input.Priority),
+			strings.Join(row.Resolved.Labels, ", "))
+	}
+	w.Flush()
+}
+
+// createBulk creates the issues of every valid row, leaving invalid rows untouched
+func (m *Module) createBulk(resolver *draftResolver, rows []bulkRow) {
+	valid := 0
+	for _, row := range rows {
+		if row.Err == nil {
+			valid++
+		}
+	}
+
+	progressBar := ui.NewProgressBar(fmt.Sprintf("Creating issues in %s", resolver.tracker.Name()), valid)
+	for i := range rows {
+		row := &rows[i]
+		if row.Err != nil {
+			continue
+		}
+		progressBar.UpdateTitle(fmt.Sprintf("Creating: %s", truncate(row.Resolved.Input.Title, 40)))
+		row.Issue, row.Err = resolver.create(row.Resolved)
+		progressBar.Increment()
+	}
+	progressBar.Finish()
+}
+
+// printBulkReport prints the outcome of every row of a bulk import
+func (m *Module) printBulkReport(rows []bulkRow) (created, failed int) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ROW\tTITLE\tRESULT")
+	for _, row := range rows {
+		if row.Issue != nil {
+			created++
+			fmt.Fprintf(w, "%d\t%s\tcreated %s\n", row.Row, truncate(row.Draft.Title, 50), row.Issue.URL)
+			continue
+		}
+		failed++
+		fmt.Fprintf(w, "%d\t%s\tfailed: %v\n", row.Row, truncate(row.Draft.Title, 50), row.Err)
+	}
+	w.Flush()
+	return created, failed
+}
+
+// writeBulkReport writes the per-row outcome of a bulk import as CSV
+func writeBulkReport(path string, rows []bulkRow) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Write([]string{"row", "title", "status", "identifier", "url", "error"})
+	for _, row := range rows {
+		record := []string{fmt.Sprintf("%d", row.Row), row.Draft.Title, "failed", "", "", ""}
+		if row.Issue != nil {
+			record[2], record[3], record[4] = "created", row.Issue.Identifier, row.Issue.URL
+		} else if row.Err != nil {
+			record[5] = row.Err.Error()
+		}
+		writer.Write(record)
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// truncate shortens s to at most n characters
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
@@ -0,0 +1,768 @@
+package bugmanager
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kkz6/devtools/internal/config"
+	"github.com/kkz6/devtools/internal/ui"
+)
+
+// commandUsage describes the bug manager command-line commands
+const commandUsage = `Usage: devtools bugmanager <command> [flags]
+
+Commands:
+  serve    Run the Sentry webhook receiver that creates Linear issues
+  sync     Create tracker issues for matching Sentry issues without prompting (cron friendly)
+  ingest   File crashes from local crash logs, tombstones, logcat dumps or JSON crash files
+  create   Create an issue from markdown with YAML front matter ($EDITOR, --file or stdin)
+  import   Bulk-create issues from a CSV or YAML file
+  report   Print a Sentry analytics report for a connection (table, markdown or CSV)
+  health   Check every instance and token: latency, identity, scopes, expiry and stale mappings
+  sentry   Act on Sentry issues: ignore, resolve, assign, bookmark, note or merge
+
+Sentry actions (issues are numeric IDs or short IDs such as BACKEND-1A):
+  sentry ignore [--for 2h] [--count N --window 1h] [--users N --user-window 1d] <issue>...
+  sentry resolve [--next-release] <issue>...
+  sentry assign <issue> <email|username|#team|none>
+  sentry bookmark [--remove] <issue>...
+  sentry note <issue> [text]   (reads the note from stdin without text)
+  sentry merge <issue> <issue>...
+  Every action accepts --instance <key> and --org <slug> before the issues.
+
+Sync:
+  sync [--incremental] [--connection name] [--mapping org/project] [--limit N] [--since 24h] [--dry-run]
+  --incremental only considers issues first seen or regressed since the mapping's last
+  completed run; --since sets how far back the first run looks.
+
+Ingest:
+  ingest [--connection name] [--mapping org/project] [--app-package pkg,...] [--dry-run] <file|dir>...
+  Crashes are grouped by exception type and top in-app frame; each group becomes one issue
+  under the mapping's team, labels and template. JSON crash files hold an object or array of
+  {type, message, level, platform, timestamp, release, device, device_id, os,
+   frames: [{function, file, line, column, in_app}]} with the most recent frame first.`
+
+// RunCommand runs bug manager commands from the command line
+func (m *Module) RunCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing command\n\n%s", commandUsage)
+	}
+
+	switch args[0] {
+	case "serve":
+		return m.runServeCommand(cfg, args[1:])
+	case "sync":
+		return m.runSyncCommand(cfg, args[1:])
+	case "ingest":
+		return m.runIngestCommand(cfg, args[1:])
+	case "create":
+		return m.runCreateCommand(cfg, args[1:])
+	case "import":
+		return m.runImportCommand(cfg, args[1:])
+	case "report":
+		return m.runReportCommand(cfg, args[1:])
+	case "sentry":
+		return m.runSentryCommand(cfg, args[1:])
+	case "health":
+		return m.runHealthCommand(cfg, args[1:])
+	case "help", "-h", "--help":
+		fmt.Println(commandUsage)
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q\n\n%s", args[0], commandUsage)
+	}
+}
+
+// runServeCommand parses the flags of "devtools bugmanager serve"
+func (m *Module) runServeCommand(cfg *config.Config, args []string) error {
+	defaultAddr := cfg.BugManager.Webhook.Addr
+	if defaultAddr == "" {
+		defaultAddr = ":8080"
+	}
+
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", defaultAddr, "Address to listen on")
+	secret := fs.String("secret", "", "Sentry integration client secret (defaults to config or $SENTRY_CLIENT_SECRET)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	clientSecret := *secret
+	if clientSecret == "" {
+		clientSecret = os.Getenv("SENTRY_CLIENT_SECRET")
+	}
+	if clientSecret == "" {
+		clientSecret = cfg.BugManager.Webhook.ClientSecret
+	}
+
+	return m.serve(cfg, *addr, clientSecret)
+}
+
+// trackerFlags registers the flags selecting the issue tracker of a command
+func trackerFlags(fs *flag.FlagSet) (instance *string, github *bool) {
+	instance = fs.String("instance", "", "Linear instance key (defaults to the only configured instance)")
+	github = fs.Bool("github", false, "Create issues in GitHub Issues using the configured GitHub token")
+	return instance, github
+}
+
+// trackerFromFlags creates the issue tracker selected on the command line
+func (m *Module) trackerFromFlags(cfg *config.Config, instance string, github bool) (IssueTracker, error) {
+	if github {
+		return m.newTracker(cfg, &config.BugManagerConnection{Tracker: TrackerGitHub})
+	}
+
+	if instance == "" {
+		keys := make([]string, 0, len(cfg.Linear.Instances))
+		for key := range cfg.Linear.Instances {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		switch len(keys) {
+		case 0:
+			return nil, fmt.Errorf("no Linear instances configured")
+		case 1:
+			instance = keys[0]
+		default:
+			return nil, fmt.Errorf("several Linear instances configured, choose one with --instance (%s)", strings.Join(keys, ", "))
+		}
+	}
+
+	return m.newTracker(cfg, &config.BugManagerConnection{Tracker: TrackerLinear, LinearInstance: instance})
+}
+
+// templateFromFlag looks up the template named by a --template flag
+func templateFromFlag(cfg *config.Config, name string) (*config.BugManagerTemplate, error) {
+	if name == "" {
+		return nil, nil
+	}
+	tmpl := findTemplate(cfg, name)
+	if tmpl == nil {
+		return nil, fmt.Errorf("template '%s' not found", name)
+	}
+	return tmpl, nil
+}
+
+// runCreateCommand parses the flags of "devtools bugmanager create"
+func (m *Module) runCreateCommand(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("create", flag.ContinueOnError)
+	file := fs.String("file", "", "Markdown file to create the issue from, or - for stdin (default: open $EDITOR)")
+	yes := fs.Bool("yes", false, "Create without asking for confirmation")
+	templateName := fs.String("template", "", "Issue template applied when the markdown names none")
+	instance, github := trackerFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	tracker, err := m.trackerFromFlags(cfg, *instance, *github)
+	if err != nil {
+		return err
+	}
+	tmpl, err := templateFromFlag(cfg, *templateName)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	switch *file {
+	case "":
+		draft := IssueDraft{Type: "Bug", Priority: "medium"}
+		if tmpl != nil {
+			draft = applyTemplate(IssueDraft{}, tmpl)
+		}
+		content, err := editInEditor(renderIssueMarkdown(draft, ""), "issue-*.md")
+		if err != nil {
+			return err
+		}
+		data = []byte(content)
+	case "-":
+		// Stdin is consumed by the issue, so it cannot answer a confirmation prompt
+		if !*yes {
+			return fmt.Errorf("--yes is required when reading the issue from stdin")
+		}
+		data, err = io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read stdin: %w", err)
+		}
+	default:
+		data, err = os.ReadFile(expandHome(*file))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", *file, err)
+		}
+	}
+
+	resolver := m.newDraftResolver(cfg, tracker, tmpl)
+	resolved, err := m.parseAndResolve(resolver, data)
+	if err != nil {
+		return err
+	}
+
+	if !*yes {
+		return m.confirmAndCreate(resolver, resolved)
+	}
+
+	issue, err := resolver.create(resolved)
+	if err != nil {
+		return fmt.Errorf("failed to create issue: %w", err)
+	}
+	fmt.Println(issue.URL)
+	return nil
+}
+
+// runImportCommand parses the flags of "devtools bugmanager import"
+func (m *Module) runImportCommand(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	team := fs.String("team", "", "Default team for rows without one")
+	yes := fs.Bool("yes", false, "Create without asking for confirmation")
+	dryRun := fs.Bool("dry-run", false, "Only validate and preview the rows")
+	report := fs.String("report", "", "Write the per-row result report to this CSV file")
+	templateName := fs.String("template", "", "Issue template for rows without one")
+	instance, github := trackerFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: devtools bugmanager import [flags] <file.csv|file.yaml>")
+	}
+
+	tracker, err := m.trackerFromFlags(cfg, *instance, *github)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := templateFromFlag(cfg, *templateName)
+	if err != nil {
+		return err
+	}
+
+	drafts, err := loadBulkDrafts(expandHome(fs.Arg(0)), *team)
+	if err != nil {
+		return err
+	}
+	if len(drafts) == 0 {
+		return fmt.Errorf("%s contains no issues", fs.Arg(0))
+	}
+
+	resolver := m.newDraftResolver(cfg, tracker, tmpl)
+	rows := m.resolveBulk(resolver, drafts)
+	m.printBulkPreview(rows)
+	fmt.Println()
+
+	if *dryRun {
+		return nil
+	}
+	if !*yes && !ui.GetConfirmation(fmt.Sprintf("Create the valid issues in %s?", tracker.Name())) {
+		return nil
+	}
+
+	m.createBulk(resolver, rows)
+	fmt.Println()
+	created, failed := m.printBulkReport(rows)
+
+	if *report != "" {
+		if err := writeBulkReport(expandHome(*report), rows); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("created %d issues, %d rows failed", created, failed)
+	}
+	return nil
+}
+
+// runSyncCommand parses the flags of "devtools bugmanager sync" and syncs every selected mapping
+func (m *Module) runSyncCommand(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
+	incremental := fs.Bool("incremental", false, "Only sync issues first seen or regressed since the last completed run")
+	connection := fs.String("connection", "", "Only sync this connection (defaults to all)")
+	mappingName := fs.String("mapping", "", "Only sync this Sentry project, as org/project or project")
+	limit := fs.Int("limit", 100, "Maximum issues considered per mapping and run")
+	since := fs.String("since", "24h", "How far back the first incremental run of a mapping looks")
+	dryRun := fs.Bool("dry-run", false, "Report what would be created without creating anything")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	lookback, err := parseMinutes(*since)
+	if err != nil || lookback == 0 {
+		return fmt.Errorf("invalid --since %q (use e.g. 30m, 2h, 1d or 1w)", *since)
+	}
+	opts := syncOptions{
+		Incremental: *incremental,
+		Lookback:    time.Duration(lookback) * time.Minute,
+		Limit:       *limit,
+		DryRun:      *dryRun,
+	}
+
+	state, err := LoadSyncState()
+	if err != nil {
+		return err
+	}
+
+	matched, failed := 0, 0
+	for i := range cfg.BugManager.Connections {
+		conn := &cfg.BugManager.Connections[i]
+		if *connection != "" && !strings.EqualFold(conn.Name, *connection) {
+			continue
+		}
+
+		sentryInstance := cfg.Sentry.Instances[conn.SentryInstance]
+		if sentryInstance == nil {
+			fmt.Fprintf(os.Stderr, "%s: Sentry instance '%s' not found\n", conn.Name, conn.SentryInstance)
+			failed++
+			continue
+		}
+		client := NewSentryClient(sentryInstance.APIKey, sentryInstance.BaseURL)
+		tracker, err := m.newTracker(cfg, conn)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", conn.Name, err)
+			failed++
+			continue
+		}
+
+		for j := range conn.ProjectMappings {
+			mapping := &conn.ProjectMappings[j]
+			if *mappingName != "" && !strings.EqualFold(*mappingName, mapping.SentryProject) &&
+				!strings.EqualFold(*mappingName, mapping.SentryOrganization+"/"+mapping.SentryProject) {
+				continue
+			}
+			matched++
+
+			fmt.Printf("\n%s: %s/%s → %s\n", conn.Name, mapping.SentryOrganization, mapping.SentryProject, tracker.Name())
+			run, err := m.syncMapping(cfg, conn, mapping, client, tracker, state, opts)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "  %v\n", err)
+				failed++
+			} else {
+				printSyncRun(run)
+				failed += run.Failed
+			}
+
+			if !*dryRun {
+				if err := state.Save(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if matched == 0 {
+		return fmt.Errorf("no project mappings matched")
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d issues or mappings failed to sync", failed)
+	}
+	return nil
+}
+
+// runIngestCommand parses the flags of "devtools bugmanager ingest" and files the grouped crashes
+func (m *Module) runIngestCommand(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("ingest", flag.ContinueOnError)
+	connection := fs.String("connection", "", "Connection whose tracker receives the issues (defaults to the only one)")
+	mappingName := fs.String("mapping", "", "Mapping providing team, labels and template, as org/project or project")
+	appPackages := fs.String("app-package", "", "Comma-separated Dart packages or Java package prefixes of the app")
+	dryRun := fs.Bool("dry-run", false, "List the crash groups without creating issues")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: devtools bugmanager ingest [flags] <file|dir>...")
+	}
+
+	conn, err := findConnection(cfg, *connection)
+	if err != nil {
+		return err
+	}
+	var mapping *config.BugManagerProjectMapping
+	for i := range conn.ProjectMappings {
+		candidate := &conn.ProjectMappings[i]
+		if (*mappingName == "" && len(conn.ProjectMappings) == 1) ||
+			strings.EqualFold(*mappingName, candidate.SentryProject) ||
+			strings.EqualFold(*mappingName, candidate.SentryOrganization+"/"+candidate.SentryProject) {
+			mapping = candidate
+			break
+		}
+	}
+	if mapping == nil {
+		if *mappingName == "" {
+			return fmt.Errorf("choose a mapping of %s with --mapping", conn.Name)
+		}
+		return fmt.Errorf("mapping %q not found in %s", *mappingName, conn.Name)
+	}
+
+	source := NewFileErrorSource(fs.Args(), splitList(*appPackages))
+	issues, warnings, err := source.Scan()
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "skipped %s\n", warning)
+	}
+	if len(issues) == 0 {
+		fmt.Println("No crashes found.")
+		return nil
+	}
+	fmt.Println(crashGroupTable(issues).Render())
+	if *dryRun {
+		return nil
+	}
+
+	tracker, err := m.newTracker(cfg, conn)
+	if err != nil {
+		return err
+	}
+	state, err := LoadSyncState()
+	if err != nil {
+		return err
+	}
+
+	items := make([]*batchItem, len(issues))
+	for i, issue := range issues {
+		items[i] = &batchItem{Issue: issue}
+	}
+	pending := skipSyncedItems(state, crashFilesStateKey, items)
+	if len(pending) > 0 {
+		tmpl := findTemplate(cfg, mapping.Template)
+		stateID, err := templateStateID(tracker, mapping.TeamID(), tmpl)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		m.createBatch(cfg, mapping, source, crashFilesStateKey, newCachedTracker(tracker), tmpl, stateID, state, pending, false)
+		if err := state.Save(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println()
+	created := printBatchReport(items)
+	if failed := len(pending) - len(created); failed > 0 {
+		return fmt.Errorf("%d crash groups failed to sync", failed)
+	}
+	return nil
+}
+
+// printSyncRun prints the per-issue outcome and totals of an unattended sync
+func printSyncRun(run *syncRun) {
+	if !run.Since.IsZero() {
+		fmt.Printf("  Since %s\n", run.Since.Local().Format("2006-01-02 15:04"))
+	}
+	if len(run.Items) == 0 {
+		fmt.Println("  No matching issues.")
+		return
+	}
+
+	printBatchReport(run.Items)
+	skipped := len(run.Items) - run.Created - run.Failed
+	fmt.Printf("  Created %d, skipped %d, failed %d.\n", run.Created, skipped, run.Failed)
+	if run.Left {
+		fmt.Println("  Limit reached; the remaining issues are left for the next run.")
+	}
+}
+
+// findConnection returns the connection with the given name, or the only connection when name is empty
+func findConnection(cfg *config.Config, name string) (*config.BugManagerConnection, error) {
+	for i := range cfg.BugManager.Connections {
+		conn := &cfg.BugManager.Connections[i]
+		if (name == "" && len(cfg.BugManager.Connections) == 1) || strings.EqualFold(conn.Name, name) {
+			return conn, nil
+		}
+	}
+	if name == "" {
+		return nil, fmt.Errorf("choose a connection with --connection")
+	}
+	return nil, fmt.Errorf("connection %q not found", name)
+}
+
+// runHealthCommand prints the connection health dashboard and fails when problems are found
+func (m *Module) runHealthCommand(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("health", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	results := m.checkConnectionHealth(cfg)
+	if len(results) == 0 {
+		return fmt.Errorf("no instances or GitHub token configured")
+	}
+	fmt.Print(renderHealthReport(results))
+
+	if problems := countHealthProblems(results); problems > 0 {
+		return fmt.Errorf("%d problems found", problems)
+	}
+	return nil
+}
+
+// runReportCommand parses the flags of "devtools bugmanager report"
+func (m *Module) runReportCommand(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	connection := fs.String("connection", "", "Connection name (defaults to the only configured connection)")
+	window := fs.String("window", "7d", "Reporting window: "+strings.Join(analyticsWindows, ", "))
+	format := fs.String("format", "table", "Output format: table, markdown or csv")
+	output := fs.String("output", "", "Write the report to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch *format {
+	case "table", "markdown", "csv":
+	default:
+		return fmt.Errorf("unknown format %q (use table, markdown or csv)", *format)
+	}
+
+	validWindow := false
+	for _, w := range analyticsWindows {
+		validWindow = validWindow || w == *window
+	}
+	if !validWindow {
+		return fmt.Errorf("unknown window %q (use %s)", *window, strings.Join(analyticsWindows, ", "))
+	}
+
+	conn, err := findConnection(cfg, *connection)
+	if err != nil {
+		return err
+	}
+
+	report, err := m.buildAnalyticsReport(cfg, conn, *window, false)
+	if err != nil {
+		return err
+	}
+
+	if *output != "" {
+		if *format == "table" {
+			return fmt.Errorf("--output requires --format markdown or csv")
+		}
+		return report.export(expandHome(*output), *format)
+	}
+
+	switch *format {
+	case "markdown":
+		fmt.Print(report.Markdown())
+	case "csv":
+		return report.WriteCSV(os.Stdout)
+	default:
+		fmt.Println(report.Terminal())
+	}
+	return nil
+}
+
+// runSentryCommand runs "devtools bugmanager sentry <action>"
+func (m *Module) runSentryCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing Sentry action\n\n%s", commandUsage)
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("sentry "+action, flag.ContinueOnError)
+	instance := fs.String("instance", "", "Sentry instance key (defaults to the only configured instance)")
+	org := fs.String("org", "", "Organization slug (defaults to the only organization in the connection mappings)")
+	ignoreFor := fs.String("for", "", "ignore: duration such as 30m, 2h, 1d or 1w")
+	count := fs.Int("count", 0, "ignore: until it occurs this many more times")
+	window := fs.String("window", "", "ignore: time window for --count")
+	users := fs.Int("users", 0, "ignore: until it affects this many more users")
+	userWindow := fs.String("user-window", "", "ignore: time window for --users")
+	nextRelease := fs.Bool("next-release", false, "resolve: resolve in the next release")
+	remove := fs.Bool("remove", false, "bookmark: remove the bookmark")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("missing issue ID\n\n%s", commandUsage)
+	}
+
+	client, organization, err := sentryFromFlags(cfg, *instance, *org)
+	if err != nil {
+		return err
+	}
+
+	// Actions with extra arguments only take a single issue
+	issueArgs := fs.Args()
+	var extra []string
+	if action == "assign" || action == "note" {
+		issueArgs, extra = fs.Args()[:1], fs.Args()[1:]
+	}
+	issueIDs := make([]string, len(issueArgs))
+	for i, arg := range issueArgs {
+		if issueIDs[i], err = resolveSentryIssueID(client, organization, arg); err != nil {
+			return err
+		}
+	}
+
+	switch action {
+	case "ignore":
+		ignore := SentryIgnoreOptions{Count: *count, UserCount: *users}
+		if ignore.Minutes, err = parseMinutes(*ignoreFor); err != nil {
+			return err
+		}
+		if ignore.Window, err = parseMinutes(*window); err != nil {
+			return err
+		}
+		if ignore.UserWindow, err = parseMinutes(*userWindow); err != nil {
+			return err
+		}
+		return forEachSentryIssue(issueArgs, issueIDs, "ignored", func(id string) error {
+			return client.IgnoreIssue(id, ignore)
+		})
+
+	case "resolve":
+		return forEachSentryIssue(issueArgs, issueIDs, "resolved", func(id string) error {
+			if *nextRelease {
+				return client.ResolveInNextRelease(id)
+			}
+			return client.ResolveIssue(id)
+		})
+
+	case "assign":
+		if len(extra) != 1 {
+			return fmt.Errorf("usage: devtools bugmanager sentry assign <issue> <email|username|#team|none>")
+		}
+		actor, err := sentryActor(client, organization, extra[0])
+		if err != nil {
+			return err
+		}
+		return forEachSentryIssue(issueArgs, issueIDs, "assigned to "+extra[0], func(id string) error {
+			return client.AssignIssue(id, actor)
+		})
+
+	case "bookmark":
+		result := "bookmarked"
+		if *remove {
+			result = "unbookmarked"
+		}
+		return forEachSentryIssue(issueArgs, issueIDs, result, func(id string) error {
+			return client.SetBookmark(id, !*remove)
+		})
+
+	case "note":
+		note := strings.Join(extra, " ")
+		if note == "" {
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("failed to read stdin: %w", err)
+			}
+			note = strings.TrimSpace(string(data))
+		}
+		if note == "" {
+			return fmt.Errorf("the note is empty")
+		}
+		return forEachSentryIssue(issueArgs, issueIDs, "noted", func(id string) error {
+			return client.AddNote(id, note)
+		})
+
+	case "merge":
+		parent, err := client.MergeIssues(organization, issueIDs)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Merged %d issues into %s\n", len(issueIDs), parent)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown Sentry action %q\n\n%s", action, commandUsage)
+	}
+}
+
+// sentryFromFlags creates the Sentry client and organization selected on the command line
+func sentryFromFlags(cfg *config.Config, instance, org string) (*SentryClient, string, error) {
+	if instance == "" {
+		keys := make([]string, 0, len(cfg.Sentry.Instances))
+		for key := range cfg.Sentry.Instances {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		switch len(keys) {
+		case 0:
+			return nil, "", fmt.Errorf("no Sentry instances configured")
+		case 1:
+			instance = keys[0]
+		default:
+			return nil, "", fmt.Errorf("several Sentry instances configured, choose one with --instance (%s)", strings.Join(keys, ", "))
+		}
+	}
+
+	sentryInstance := cfg.Sentry.Instances[instance]
+	if sentryInstance == nil {
+		return nil, "", fmt.Errorf("Sentry instance %q not found", instance)
+	}
+
+	// Default to the organization shared by every mapping of the instance
+	if org == "" {
+		orgs := make(map[string]bool)
+		for _, conn := range cfg.BugManager.Connections {
+			if conn.SentryInstance != instance {
+				continue
+			}
+			for _, mapping := range conn.ProjectMappings {
+				orgs[mapping.SentryOrganization] = true
+			}
+		}
+		if len(orgs) == 1 {
+			for o := range orgs {
+				org = o
+			}
+		}
+	}
+
+	return NewSentryClient(sentryInstance.APIKey, sentryInstance.BaseURL), org, nil
+}
+
+// resolveSentryIssueID accepts a numeric issue ID or a short ID such as BACKEND-1A
+func resolveSentryIssueID(client *SentryClient, organization, value string) (string, error) {
+	if _, err := strconv.ParseUint(value, 10, 64); err == nil {
+		return value, nil
+	}
+	if organization == "" {
+		return "", fmt.Errorf("--org is required to look up short ID %s", value)
+	}
+	return client.ResolveShortID(organization, value)
+}
+
+// sentryActor converts an assignee argument into a Sentry actor.
+// "#slug" names a team and "none" clears the assignment.
+func sentryActor(client *SentryClient, organization, value string) (string, error) {
+	if strings.EqualFold(value, "none") {
+		return "", nil
+	}
+	if !strings.HasPrefix(value, "#") {
+		return value, nil
+	}
+
+	if organization == "" {
+		return "", fmt.Errorf("--org is required to look up team %s", value)
+	}
+	teams, err := client.GetTeams(organization)
+	if err != nil {
+		return "", err
+	}
+	for _, team := range teams {
+		if strings.EqualFold(team.Slug, value[1:]) {
+			return "team:" + team.ID, nil
+		}
+	}
+	return "", fmt.Errorf("team %s not found in %s", value, organization)
+}
+
+// forEachSentryIssue applies an action to every issue, reporting each outcome
+// and failing if any issue failed
+func forEachSentryIssue(names, ids []string, result string, action func(id string) error) error {
+	failed := 0
+	for i, id := range ids {
+		if err := action(id); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", names[i], err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s %s\n", names[i], result)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d issues failed", failed, len(ids))
+	}
+	return nil
+}
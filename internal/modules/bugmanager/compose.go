@@ -0,0 +1,405 @@
+package bugmanager
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kkz6/devtools/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// IssueDraft is an issue written as markdown with YAML front matter, or a row of a bulk import
+type IssueDraft struct {
+	Title       string   `yaml:"title,omitempty"`
+	Template    string   `yaml:"template,omitempty"` // Issue template from the configuration
+	Team        string   `yaml:"team"`               // Team key, name or ID (repository for GitHub)
+	Project     string   `yaml:"project,omitempty"`  // Project name or ID (milestone for GitHub)
+	Type        string   `yaml:"type,omitempty"`     // Bug, Feature, Task, Improvement, Story
+	Priority    string   `yaml:"priority,omitempty"`
+	Labels      []string `yaml:"labels,omitempty"`
+	State       string   `yaml:"state,omitempty"`
+	Assignee    string   `yaml:"assignee,omitempty"`
+	Description string   `yaml:"description,omitempty"`
+}
+
+// resolvedDraft is a draft whose names have been resolved against a tracker
+type resolvedDraft struct {
+	Input       TrackerIssueInput
+	Labels      []string
+	TeamName    string
+	ProjectName string
+	StateName   string
+	Assignee    string
+	Template    *config.BugManagerTemplate
+}
+
+// priorityNames maps the accepted priority spellings to Linear priorities
+var priorityNames = map[string]int{
+	"":            0,
+	"none":        0,
+	"no priority": 0,
+	"urgent":      1,
+	"high":        2,
+	"medium":      3,
+	"normal":      3,
+	"low":         4,
+}
+
+// parsePriority accepts a priority name or its number (0-4)
+func parsePriority(value string) (int, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if priority, ok := priorityNames[value]; ok {
+		return priority, nil
+	}
+	if priority, err := strconv.Atoi(value); err == nil && priority >= 0 && priority <= 4 {
+		return priority, nil
+	}
+	return 0, fmt.Errorf("unknown priority '%s' (use urgent, high, medium, low or none)", value)
+}
+
+// parseIssueMarkdown parses markdown with optional YAML front matter into a draft.
+// The title comes from the front matter or the first "# " heading.
+func parseIssueMarkdown(data []byte) (*IssueDraft, error) {
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	draft := &IssueDraft{}
+
+	if strings.HasPrefix(content, "---\n") {
+		end := strings.Index(content[4:], "\n---")
+		if end < 0 {
+			return nil, fmt.Errorf("front matter is not closed with '---'")
+		}
+		frontMatter := content[4 : 4+end]
+		if err := yaml.Unmarshal([]byte(frontMatter), draft); err != nil {
+			return nil, fmt.Errorf("invalid front matter: %w", err)
+		}
+
+		content = content[4+end+4:]
+		if idx := strings.Index(content, "\n"); idx >= 0 {
+			content = content[idx+1:]
+		} else {
+			content = ""
+		}
+	}
+
+	var body []string
+	for _, line := range strings.Split(content, "\n") {
+		if draft.Title == "" && strings.HasPrefix(line, "# ") {
+			draft.Title = strings.TrimSpace(strings.TrimPrefix(line, "# "))
+			continue
+		}
+		body = append(body, line)
+	}
+	draft.Description = strings.TrimSpace(strings.Join(body, "\n"))
+
+	if draft.Title == "" {
+		return nil, fmt.Errorf("missing title: add 'title:' to the front matter or a '# Title' heading")
+	}
+
+	return draft, nil
+}
+
+// renderIssueMarkdown renders a draft as markdown with YAML front matter for editing.
+// Every field is written, even when empty, so the template shows what can be set.
+func renderIssueMarkdown(draft IssueDraft, hint string) string {
+	quotedLabels := make([]string, len(draft.Labels))
+	for i, label := range draft.Labels {
+		quotedLabels[i] = fmt.Sprintf("%q", label)
+	}
+
+	var b strings.Builder
+	b.WriteString("---\n")
+	if hint != "" {
+		b.WriteString(fmt.Sprintf("# %s\n", hint))
+	}
+	if draft.Template != "" {
+		b.WriteString(fmt.Sprintf("template: %q\n", draft.Template))
+	}
+	b.WriteString(fmt.Sprintf("team: %q\n", draft.Team))
+	b.WriteString(fmt.Sprintf("project: %q\n", draft.Project))
+	b.WriteString(fmt.Sprintf("type: %q\n", draft.Type))
+	b.WriteString(fmt.Sprintf("priority: %q # urgent, high, medium, low or none\n", draft.Priority))
+	b.WriteString(fmt.Sprintf("labels: [%s]\n", strings.Join(quotedLabels, ", ")))
+	b.WriteString(fmt.Sprintf("state: %q\n", draft.State))
+	b.WriteString(fmt.Sprintf("assignee: %q\n", draft.Assignee))
+	b.WriteString("---\n")
+	b.WriteString(fmt.Sprintf("# %s\n\n", draft.Title))
+	b.WriteString(draft.Description)
+	b.WriteString("\n")
+	return b.String()
+}
+
+// expandHome expands a leading ~ to the user's home directory
+func expandHome(path string) string {
+	path = strings.TrimSpace(path)
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		homeDir, _ := os.UserHomeDir()
+		return filepath.Join(homeDir, path[1:])
+	}
+	return path
+}
+
+// editorCommand returns the user's editor from $VISUAL or $EDITOR
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return nil
+}
+
+// editInEditor opens content in the user's editor and returns the saved result
+func editInEditor(content, pattern string) (string, error) {
+	command := editorCommand()
+	if command == nil {
+		command = []string{"vi"}
+	}
+
+	tmpFile, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.WriteString(content); err != nil {
+		tmpFile.Close()
+		return "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	tmpFile.Close()
+
+	cmd := exec.Command(command[0], append(command[1:], tmpFile.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor exited with error: %w", err)
+	}
+
+	data, err := os.ReadFile(tmpFile.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read edited file: %w", err)
+	}
+	return string(bytes.TrimRight(data, "\n")), nil
+}
+
+// draftResolver resolves draft names against a tracker, caching lookups across drafts
+type draftResolver struct {
+	m        *Module
+	cfg      *config.Config
+	tracker  IssueTracker
+	template string // Template applied to drafts that do not name one
+	teams    []TrackerTeam
+	projects map[string][]TrackerProject
+	states   map[string][]TrackerState
+	users    map[string][]TrackerUser
+}
+
+// newDraftResolver creates a resolver for a tracker.
+// Drafts without a template use defaultTemplate, if set.
+func (m *Module) newDraftResolver(cfg *config.Config, tracker IssueTracker, defaultTemplate *config.BugManagerTemplate) *draftResolver {
+	r := &draftResolver{
+		m:        m,
+		cfg:      cfg,
+		tracker:  tracker,
+		projects: make(map[string][]TrackerProject),
+		states:   make(map[string][]TrackerState),
+		users:    make(map[string][]TrackerUser),
+	}
+	if defaultTemplate != nil {
+		r.template = defaultTemplate.Name
+	}
+	return r
+}
+
+// resolve validates a draft and looks up the IDs of its team, project, state and assignee
+func (r *draftResolver) resolve(draft IssueDraft) (*resolvedDraft, error) {
+	if draft.Template == "" {
+		draft.Template = r.template
+	}
+	var tmpl *config.BugManagerTemplate
+	if draft.Template != "" {
+		tmpl = findTemplate(r.cfg, draft.Template)
+		if tmpl == nil {
+			return nil, fmt.Errorf("template '%s' not found", draft.Template)
+		}
+		draft = applyTemplate(draft, tmpl)
+	}
+
+	if strings.TrimSpace(draft.Title) == "" {
+		return nil, fmt.Errorf("missing title")
+	}
+	if strings.TrimSpace(draft.Team) == "" {
+		return nil, fmt.Errorf("missing team")
+	}
+
+	priority, err := parsePriority(draft.Priority)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.teams == nil {
+		teams, err := r.tracker.GetTeams()
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch teams: %w", err)
+		}
+		r.teams = teams
+	}
+
+	var team *TrackerTeam
+	for i := range r.teams {
+		if matchesName(draft.Team, r.teams[i].ID, r.teams[i].Key, r.teams[i].Name) {
+			team = &r.teams[i]
+			break
+		}
+	}
+	if team == nil {
+		return nil, fmt.Errorf("team '%s' not found", draft.Team)
+	}
+
+	resolved := &resolvedDraft{
+		Input: TrackerIssueInput{
+			TeamID:      team.ID,
+			Title:       draft.Title,
+			Description: draft.Description,
+			Priority:    priority,
+		},
+		TeamName: team.Name,
+		Template: tmpl,
+	}
+
+	// Prefix the title like guided creation does
+	resolved.Input.Title = templateTitle(draft.Title, draft.Type, tmpl)
+
+	if draft.Project != "" {
+		projects, ok := r.projects[team.ID]
+		if !ok {
+			projects, err = r.tracker.GetProjects(team.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch projects: %w", err)
+			}
+			r.projects[team.ID] = projects
+		}
+		for _, project := range projects {
+			if matchesName(draft.Project, project.ID, project.Name) {
+				resolved.Input.ProjectID = project.ID
+				resolved.ProjectName = project.Name
+				break
+			}
+		}
+		if resolved.Input.ProjectID == "" {
+			return nil, fmt.Errorf("project '%s' not found in %s", draft.Project, team.Name)
+		}
+	}
+
+	if draft.State != "" {
+		states, ok := r.states[team.ID]
+		if !ok {
+			states, err = r.tracker.GetWorkflowStates(team.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch workflow states: %w", err)
+			}
+			r.states[team.ID] = states
+		}
+		for _, state := range states {
+			if matchesName(draft.State, state.ID, state.Name) {
+				resolved.Input.StateID = state.ID
+				resolved.StateName = state.Name
+				break
+			}
+		}
+		if resolved.Input.StateID == "" {
+			return nil, fmt.Errorf("state '%s' not found in %s", draft.State, team.Name)
+		}
+	}
+
+	if draft.Assignee != "" {
+		users, ok := r.users[team.ID]
+		if !ok {
+			users, err = r.tracker.GetUsers(team.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch users: %w", err)
+			}
+			r.users[team.ID] = users
+		}
+		user := resolveTrackerUser(users, draft.Assignee)
+		if user == nil {
+			return nil, fmt.Errorf("assignee '%s' not found", draft.Assignee)
+		}
+		resolved.Input.AssigneeID = user.ID
+		resolved.Assignee = user.DisplayName
+	}
+
+	for _, label := range draft.Labels {
+		if label = strings.TrimSpace(label); label != "" {
+			resolved.Labels = append(resolved.Labels, label)
+		}
+	}
+	if len(resolved.Labels) == 0 && draft.Type != "" {
+		resolved.Labels = []string{strings.ToLower(draft.Type)}
+	}
+
+	return resolved, nil
+}
+
+// create creates the labels of a resolved draft, the issue and its template's sub-issues
+func (r *draftResolver) create(resolved *resolvedDraft) (*TrackerIssue, error) {
+	input := resolved.Input
+	input.LabelIDs = r.m.ensureLabels(r.tracker, input.TeamID, resolved.Labels)
+	issue, err := r.tracker.CreateIssue(input)
+	if err != nil {
+		return nil, err
+	}
+
+	r.m.reportSubIssues(r.tracker, issue, input, resolved.Template)
+	return issue, nil
+}
+
+// matchesName reports whether value equals any of the candidates, ignoring case
+func matchesName(value string, candidates ...string) bool {
+	value = strings.TrimSpace(value)
+	for _, candidate := range candidates {
+		if candidate != "" && strings.EqualFold(value, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// printResolvedDraft prints a summary of an issue about to be created
+func printResolvedDraft(m *Module, tracker IssueTracker, resolved *resolvedDraft) {
+	separator := strings.Repeat("─", 60)
+	fmt.Println("\n" + separator)
+	fmt.Println("Issue Summary:")
+	fmt.Println(separator)
+
+	fmt.Printf("Tracker: %s\n", tracker.Name())
+	fmt.Printf("Title: %s\n", resolved.Input.Title)
+	fmt.Printf("Team: %s\n", resolved.TeamName)
+	if resolved.ProjectName != "" {
+		fmt.Printf("Project: %s\n", resolved.ProjectName)
+	}
+	fmt.Printf("Priority: %s\n", m.getPriorityName(resolved.Input.Priority))
+	if resolved.StateName != "" {
+		fmt.Printf("State: %s\n", resolved.StateName)
+	}
+	if resolved.Assignee != "" {
+		fmt.Printf("Assignee: %s\n", resolved.Assignee)
+	}
+	fmt.Printf("Labels: %s\n", strings.Join(resolved.Labels, ", "))
+	if resolved.Template != nil && len(resolved.Template.SubIssues) > 0 {
+		fmt.Printf("Sub-issues (%s):\n", resolved.Template.Name)
+		for _, sub := range resolved.Template.SubIssues {
+			fmt.Printf("  - %s\n", sub.Title)
+		}
+	}
+	if resolved.Input.Description != "" {
+		fmt.Println("\nDescription:")
+		fmt.Println(resolved.Input.Description)
+	}
+	fmt.Println(separator)
+}
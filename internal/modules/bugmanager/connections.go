@@ -11,31 +11,28 @@ import (
 	"github.com/kkz6/devtools/internal/ui"
 )
 
-// manageConnections handles connection management between Linear and Sentry
+// manageConnections handles connection management between Sentry and issue trackers
 func (m *Module) manageConnections(cfg *config.Config) error {
 	for {
 		// Build options list
 		options := []string{"Add New Connection"}
 
 		// Add existing connections
-		for _, conn := range cfg.BugManager.Connections {
-			linearName := "Unknown"
+		for i := range cfg.BugManager.Connections {
+			conn := &cfg.BugManager.Connections[i]
 			sentryName := "Unknown"
 
-			if linear, ok := cfg.Linear.Instances[conn.LinearInstance]; ok {
-				linearName = linear.Name
-			}
 			if sentry, ok := cfg.Sentry.Instances[conn.SentryInstance]; ok {
 				sentryName = sentry.Name
 			}
 
 			options = append(options, fmt.Sprintf("%s: %s ↔ %s (%d mappings)",
-				conn.Name, sentryName, linearName, len(conn.ProjectMappings)))
+				conn.Name, sentryName, trackerDisplayName(cfg, conn), len(conn.ProjectMappings)))
 		}
 
 		options = append(options, "Back")
 
-		choice, err := ui.SelectFromList("Sentry-Linear Connections", options)
+		choice, err := ui.SelectFromList("Sentry Connections", options)
 		if err != nil {
 			return err
 		}
@@ -58,7 +55,7 @@ func (m *Module) manageConnections(cfg *config.Config) error {
 	}
 }
 
-// addConnection adds a new connection between Linear and Sentry
+// addConnection adds a new connection between Sentry and an issue tracker
 func (m *Module) addConnection(cfg *config.Config) error {
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
@@ -68,8 +65,8 @@ func (m *Module) addConnection(cfg *config.Config) error {
 	fmt.Println(titleStyle.Render("Add New Connection"))
 
 	// Check if we have instances
-	if len(cfg.Linear.Instances) == 0 {
-		ui.ShowError("No Linear instances configured. Please add a Linear instance first.")
+	if len(cfg.Linear.Instances) == 0 && cfg.GitHub.Token == "" {
+		ui.ShowError("No issue trackers configured. Please add a Linear instance or a GitHub token first.")
 		return types.ErrNavigateBack
 	}
 	if len(cfg.Sentry.Instances) == 0 {
@@ -124,31 +121,16 @@ func (m *Module) addConnection(cfg *config.Config) error {
 	}
 	selectedSentryKey := sentryKeys[sentryChoice]
 
-	// Select Linear instance
-	linearOptions := make([]string, 0, len(cfg.Linear.Instances))
-	linearKeys := make([]string, 0, len(cfg.Linear.Instances))
-	for key := range cfg.Linear.Instances {
-		linearKeys = append(linearKeys, key)
-	}
-	sort.Strings(linearKeys)
-
-	for _, key := range linearKeys {
-		instance := cfg.Linear.Instances[key]
-		linearOptions = append(linearOptions, fmt.Sprintf("%s (%s)", instance.Name, key))
-	}
-
-	linearChoice, err := ui.SelectFromList("Select Linear instance", linearOptions)
+	// Select issue tracker
+	trackerKind, selectedLinearKey, err := m.selectTracker(cfg, "Select issue tracker")
 	if err != nil {
-		if err.Error() == "cancelled" {
-			return types.ErrNavigateBack
-		}
 		return err
 	}
-	selectedLinearKey := linearKeys[linearChoice]
 
 	// Create connection
 	connection := config.BugManagerConnection{
 		Name:            name,
+		Tracker:         trackerKind,
 		LinearInstance:  selectedLinearKey,
 		SentryInstance:  selectedSentryKey,
 		ProjectMappings: []config.BugManagerProjectMapping{},
@@ -179,12 +161,8 @@ func (m *Module) editConnection(cfg *config.Config, index int) error {
 	conn := &cfg.BugManager.Connections[index]
 
 	for {
-		linearName := "Unknown"
 		sentryName := "Unknown"
 
-		if linear, ok := cfg.Linear.Instances[conn.LinearInstance]; ok {
-			linearName = linear.Name
-		}
 		if sentry, ok := cfg.Sentry.Instances[conn.SentryInstance]; ok {
 			sentryName = sentry.Name
 		}
@@ -192,7 +170,7 @@ func (m *Module) editConnection(cfg *config.Config, index int) error {
 		options := []string{
 			fmt.Sprintf("Edit Name (current: %s)", conn.Name),
 			fmt.Sprintf("Change Sentry Instance (current: %s)", sentryName),
-			fmt.Sprintf("Change Linear Instance (current: %s)", linearName),
+			fmt.Sprintf("Change Issue Tracker (current: %s)", trackerDisplayName(cfg, conn)),
 			fmt.Sprintf("Manage Project Mappings (%d)", len(conn.ProjectMappings)),
 			"Test Connection",
 			"Remove Connection",
@@ -245,31 +223,23 @@ func (m *Module) editConnection(cfg *config.Config, index int) error {
 			}
 			ui.ShowSuccess("Sentry instance updated successfully!")
 
-		case 2: // Change Linear instance
-			linearOptions := make([]string, 0, len(cfg.Linear.Instances))
-			linearKeys := make([]string, 0, len(cfg.Linear.Instances))
-			for key := range cfg.Linear.Instances {
-				linearKeys = append(linearKeys, key)
-			}
-			sort.Strings(linearKeys)
-
-			for _, key := range linearKeys {
-				instance := cfg.Linear.Instances[key]
-				linearOptions = append(linearOptions, fmt.Sprintf("%s (%s)", instance.Name, key))
-			}
-
-			linearChoice, err := ui.SelectFromList("Select Linear instance", linearOptions)
+		case 2: // Change issue tracker
+			trackerKind, linearKey, err := m.selectTracker(cfg, "Select issue tracker")
 			if err != nil {
-				if err.Error() == "cancelled" {
+				if err == types.ErrNavigateBack {
 					continue
 				}
 				return err
 			}
-			conn.LinearInstance = linearKeys[linearChoice]
+			if trackerKind != conn.Tracker && len(conn.ProjectMappings) > 0 {
+				ui.ShowWarning("Existing project mappings refer to teams of the previous tracker and need to be recreated.")
+			}
+			conn.Tracker = trackerKind
+			conn.LinearInstance = linearKey
 			if err := config.Save(cfg); err != nil {
 				return fmt.Errorf("failed to save configuration: %w", err)
 			}
-			ui.ShowSuccess("Linear instance updated successfully!")
+			ui.ShowSuccess("Issue tracker updated successfully!")
 
 		case 3: // Manage project mappings
 			if err := m.manageProjectMappings(cfg, conn); err != nil && err != types.ErrNavigateBack {
@@ -307,9 +277,9 @@ func (m *Module) editConnection(cfg *config.Config, index int) error {
 func (m *Module) manageProjectMappings(cfg *config.Config, conn *config.BugManagerConnection) error {
 	// Get instances
 	sentryInstance := cfg.Sentry.Instances[conn.SentryInstance]
-	linearInstance := cfg.Linear.Instances[conn.LinearInstance]
+	tracker, err := m.newTracker(cfg, conn)
 
-	if sentryInstance == nil || linearInstance == nil {
+	if sentryInstance == nil || err != nil {
 		ui.ShowError("Invalid instance configuration")
 		return types.ErrNavigateBack
 	}
@@ -321,7 +291,7 @@ func (m *Module) manageProjectMappings(cfg *config.Config, conn *config.BugManag
 		// Add existing mappings
 		for _, mapping := range conn.ProjectMappings {
 			options = append(options, fmt.Sprintf("%s/%s → %s",
-				mapping.SentryOrganization, mapping.SentryProject, mapping.LinearProjectName))
+				mapping.SentryOrganization, mapping.SentryProject, mapping.ProjectName()))
 		}
 
 		options = append(options, "Back")
@@ -333,7 +303,7 @@ func (m *Module) manageProjectMappings(cfg *config.Config, conn *config.BugManag
 
 		if choice == 0 {
 			// Add new mapping
-			if err := m.addProjectMapping(cfg, conn, sentryInstance, linearInstance); err != nil && err != types.ErrNavigateBack {
+			if err := m.addProjectMapping(cfg, conn, sentryInstance, tracker); err != nil && err != types.ErrNavigateBack {
 				return err
 			}
 		} else if choice < len(options)-1 {
@@ -351,7 +321,7 @@ func (m *Module) manageProjectMappings(cfg *config.Config, conn *config.BugManag
 
 // addProjectMapping adds a new project mapping
 func (m *Module) addProjectMapping(cfg *config.Config, conn *config.BugManagerConnection,
-	sentryInstance *config.SentryInstance, linearInstance *config.LinearInstance) error {
+	sentryInstance *config.SentryInstance, tracker IssueTracker) error {
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
@@ -362,7 +332,7 @@ func (m *Module) addProjectMapping(cfg *config.Config, conn *config.BugManagerCo
 
 	// Initialize clients
 	sentryClient := NewSentryClient(sentryInstance.APIKey, sentryInstance.BaseURL)
-	linearClient := NewLinearClient(linearInstance.APIKey)
+	teamTerm, projectTerm := trackerTerms(tracker)
 
 	// Fetch Sentry projects
 	ui.ShowInfo("Fetching Sentry projects...")
@@ -402,26 +372,26 @@ func (m *Module) addProjectMapping(cfg *config.Config, conn *config.BugManagerCo
 		}
 	}
 
-	// Fetch Linear teams
-	ui.ShowInfo("Fetching Linear teams...")
-	linearTeams, err := linearClient.GetTeams()
+	// Fetch tracker teams
+	ui.ShowInfo(fmt.Sprintf("Fetching %s %ss...", tracker.Name(), teamTerm))
+	trackerTeams, err := tracker.GetTeams()
 	if err != nil {
-		ui.ShowError(fmt.Sprintf("Failed to fetch Linear teams: %v", err))
+		ui.ShowError(fmt.Sprintf("Failed to fetch %s %ss: %v", tracker.Name(), teamTerm, err))
 		return types.ErrNavigateBack
 	}
 
-	if len(linearTeams) == 0 {
-		ui.ShowWarning("No Linear teams found.")
+	if len(trackerTeams) == 0 {
+		ui.ShowWarning(fmt.Sprintf("No %s %ss found.", tracker.Name(), teamTerm))
 		return types.ErrNavigateBack
 	}
 
-	// Select Linear team
-	teamOptions := make([]string, len(linearTeams))
-	for i, team := range linearTeams {
+	// Select tracker team
+	teamOptions := make([]string, len(trackerTeams))
+	for i, team := range trackerTeams {
 		teamOptions[i] = fmt.Sprintf("%s (%s)", team.Name, team.Key)
 	}
 
-	teamChoice, err := ui.SelectFromList("Select Linear team", teamOptions)
+	teamChoice, err := ui.SelectFromList(fmt.Sprintf("Select %s", teamTerm), teamOptions)
 	if err != nil {
 		if err.Error() == "cancelled" {
 			return types.ErrNavigateBack
@@ -429,26 +399,26 @@ func (m *Module) addProjectMapping(cfg *config.Config, conn *config.BugManagerCo
 		return err
 	}
 
-	selectedTeam := linearTeams[teamChoice]
+	selectedTeam := trackerTeams[teamChoice]
 
-	// Fetch Linear projects for the team
-	ui.ShowInfo("Fetching Linear projects...")
-	linearProjects, err := linearClient.GetProjects(selectedTeam.ID)
+	// Fetch tracker projects for the team
+	ui.ShowInfo(fmt.Sprintf("Fetching %ss...", projectTerm))
+	trackerProjects, err := tracker.GetProjects(selectedTeam.ID)
 	if err != nil {
-		ui.ShowError(fmt.Sprintf("Failed to fetch Linear projects: %v", err))
+		ui.ShowError(fmt.Sprintf("Failed to fetch %ss: %v", projectTerm, err))
 		return types.ErrNavigateBack
 	}
 
-	// Select Linear project (optional)
+	// Select tracker project (optional)
 	var selectedProjectID string
 	var selectedProjectName string
 
-	projectOptions := []string{"No Project (Team only)"}
-	for _, proj := range linearProjects {
+	projectOptions := []string{fmt.Sprintf("No %s (%s only)", capitalize(projectTerm), capitalize(teamTerm))}
+	for _, proj := range trackerProjects {
 		projectOptions = append(projectOptions, proj.Name)
 	}
 
-	projectChoice, err := ui.SelectFromList("Select Linear project (optional)", projectOptions)
+	projectChoice, err := ui.SelectFromList(fmt.Sprintf("Select %s (optional)", projectTerm), projectOptions)
 	if err != nil {
 		if err.Error() == "cancelled" {
 			return types.ErrNavigateBack
@@ -457,10 +427,10 @@ func (m *Module) addProjectMapping(cfg *config.Config, conn *config.BugManagerCo
 	}
 
 	if projectChoice > 0 {
-		selectedProjectID = linearProjects[projectChoice-1].ID
-		selectedProjectName = linearProjects[projectChoice-1].Name
+		selectedProjectID = trackerProjects[projectChoice-1].ID
+		selectedProjectName = trackerProjects[projectChoice-1].Name
 	} else {
-		selectedProjectName = fmt.Sprintf("%s (Team)", selectedTeam.Name)
+		selectedProjectName = fmt.Sprintf("%s (%s)", selectedTeam.Name, capitalize(teamTerm))
 	}
 
 	// Get default labels
@@ -491,11 +461,17 @@ func (m *Module) addProjectMapping(cfg *config.Config, conn *config.BugManagerCo
 	mapping := config.BugManagerProjectMapping{
 		SentryOrganization: selectedSentryProject.Organization.Slug,
 		SentryProject:      selectedSentryProject.Slug,
-		LinearTeamID:       selectedTeam.ID,
-		LinearProjectID:    selectedProjectID,
-		LinearProjectName:  selectedProjectName,
 		DefaultLabels:      labels,
 	}
+	if conn.Tracker == TrackerGitHub {
+		mapping.Repository = selectedTeam.ID
+		mapping.Milestone = selectedProjectID
+		mapping.MilestoneName = selectedProjectName
+	} else {
+		mapping.LinearTeamID = selectedTeam.ID
+		mapping.LinearProjectID = selectedProjectID
+		mapping.LinearProjectName = selectedProjectName
+	}
 
 	conn.ProjectMappings = append(conn.ProjectMappings, mapping)
 
@@ -517,6 +493,9 @@ func (m *Module) editProjectMapping(cfg *config.Config, conn *config.BugManagerC
 
 	options := []string{
 		"Edit Default Labels",
+		"Edit Issue Query",
+		"Configure Auto-Assignment",
+		fmt.Sprintf("Set Issue Template (%s)", valueOrNone(mapping.Template)),
 		"Remove Mapping",
 		"Back",
 	}
@@ -554,7 +533,32 @@ func (m *Module) editProjectMapping(cfg *config.Config, conn *config.BugManagerC
 		}
 		ui.ShowSuccess("Labels updated successfully!")
 
-	case 1: // Remove mapping
+	case 1: // Edit issue query
+		return m.editIssueQuery(cfg, mapping)
+
+	case 2: // Configure auto-assignment
+		return m.editAssignment(cfg, mapping)
+
+	case 3: // Set issue template
+		if len(cfg.BugManager.Templates) == 0 {
+			ui.ShowWarning("No issue templates configured. Add them under bug_manager.templates in the configuration.")
+			return nil
+		}
+		tmpl, err := m.selectTemplate(cfg, "Template for synced issues")
+		if err != nil {
+			return err
+		}
+		mapping.Template = ""
+		if tmpl != nil {
+			mapping.Template = tmpl.Name
+		}
+
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+		ui.ShowSuccess("Issue template updated successfully!")
+
+	case 4: // Remove mapping
 		if ui.GetConfirmation("Remove this project mapping?") {
 			conn.ProjectMappings = append(
 				conn.ProjectMappings[:index],
@@ -567,37 +571,94 @@ func (m *Module) editProjectMapping(cfg *config.Config, conn *config.BugManagerC
 			return types.ErrNavigateBack
 		}
 
-	case 2: // Back
+	case 5: // Back
 		return types.ErrNavigateBack
 	}
 
 	return nil
 }
 
-// testConnection tests a connection between Linear and Sentry
+// testConnection tests a connection between Sentry and its issue tracker
 func (m *Module) testConnection(cfg *config.Config, conn *config.BugManagerConnection) error {
 	sentryInstance := cfg.Sentry.Instances[conn.SentryInstance]
-	linearInstance := cfg.Linear.Instances[conn.LinearInstance]
-
-	if sentryInstance == nil || linearInstance == nil {
+	if sentryInstance == nil {
 		return fmt.Errorf("invalid instance configuration")
 	}
 
+	tracker, err := m.newTracker(cfg, conn)
+	if err != nil {
+		return err
+	}
+
 	ui.ShowInfo("Testing Sentry connection...")
 	sentryClient := NewSentryClient(sentryInstance.APIKey, sentryInstance.BaseURL)
-	_, err := sentryClient.GetProjects()
+	_, err = sentryClient.GetProjects()
 	if err != nil {
 		return fmt.Errorf("sentry connection failed: %w", err)
 	}
 	ui.ShowSuccess("Sentry connection successful!")
 
-	ui.ShowInfo("Testing Linear connection...")
-	linearClient := NewLinearClient(linearInstance.APIKey)
-	_, err = linearClient.GetTeams()
+	ui.ShowInfo(fmt.Sprintf("Testing %s connection...", tracker.Name()))
+	_, err = tracker.GetTeams()
 	if err != nil {
-		return fmt.Errorf("linear connection failed: %w", err)
+		return fmt.Errorf("%s connection failed: %w", tracker.Name(), err)
 	}
-	ui.ShowSuccess("Linear connection successful!")
+	ui.ShowSuccess(fmt.Sprintf("%s connection successful!", tracker.Name()))
 
 	return nil
 }
+
+// selectConnection picks a connection, choosing the only one without prompting
+func (m *Module) selectConnection(cfg *config.Config, title string) (*config.BugManagerConnection, error) {
+	if len(cfg.BugManager.Connections) == 0 {
+		ui.ShowError("No Sentry connections configured. Please add a connection first.")
+		return nil, types.ErrNavigateBack
+	}
+
+	if len(cfg.BugManager.Connections) == 1 {
+		return &cfg.BugManager.Connections[0], nil
+	}
+
+	options := make([]string, len(cfg.BugManager.Connections))
+	for i := range cfg.BugManager.Connections {
+		conn := &cfg.BugManager.Connections[i]
+		sentryName := "Unknown"
+		if sentry, ok := cfg.Sentry.Instances[conn.SentryInstance]; ok {
+			sentryName = sentry.Name
+		}
+		options[i] = fmt.Sprintf("%s: %s → %s (%d mappings)",
+			conn.Name, sentryName, trackerDisplayName(cfg, conn), len(conn.ProjectMappings))
+	}
+
+	choice, err := ui.SelectFromList(title, options)
+	if err != nil {
+		if err.Error() == "cancelled" {
+			return nil, types.ErrNavigateBack
+		}
+		return nil, err
+	}
+
+	return &cfg.BugManager.Connections[choice], nil
+}
+
+// selectProjectMapping picks a mapping of a connection, choosing the only one without prompting
+func (m *Module) selectProjectMapping(conn *config.BugManagerConnection, title string) (*config.BugManagerProjectMapping, error) {
+	if len(conn.ProjectMappings) == 1 {
+		return &conn.ProjectMappings[0], nil
+	}
+
+	options := make([]string, len(conn.ProjectMappings))
+	for i, mapping := range conn.ProjectMappings {
+		options[i] = fmt.Sprintf("%s/%s → %s", mapping.SentryOrganization, mapping.SentryProject, mapping.ProjectName())
+	}
+
+	choice, err := ui.SelectFromList(title, options)
+	if err != nil {
+		if err.Error() == "cancelled" {
+			return nil, types.ErrNavigateBack
+		}
+		return nil, err
+	}
+
+	return &conn.ProjectMappings[choice], nil
+}
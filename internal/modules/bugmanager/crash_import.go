@@ -0,0 +1,92 @@
+package bugmanager
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kkz6/devtools/internal/config"
+	"github.com/kkz6/devtools/internal/types"
+	"github.com/kkz6/devtools/internal/ui"
+)
+
+// importCrashFiles groups crashes from local files and syncs them like Sentry issues.
+// The mapping provides the tracker team, project, labels, template and assignment rules.
+func (m *Module) importCrashFiles(cfg *config.Config) error {
+	conn, err := m.selectConnection(cfg, "Select connection to file crashes with")
+	if err != nil {
+		return err
+	}
+	if len(conn.ProjectMappings) == 0 {
+		ui.ShowError("Selected connection has no project mappings. Please configure project mappings first.")
+		return nil
+	}
+	mapping, err := m.selectProjectMapping(conn, "Select the mapping to file crashes under")
+	if err != nil {
+		return err
+	}
+
+	tracker, err := m.newTracker(cfg, conn)
+	if err != nil {
+		ui.ShowError(fmt.Sprintf("Invalid issue tracker configuration in connection: %v", err))
+		return nil
+	}
+
+	path, err := ui.GetInput("Crash file or directory", "~/Downloads/crashes", false, func(s string) error {
+		if _, err := os.Stat(expandHome(strings.TrimSpace(s))); err != nil {
+			return fmt.Errorf("path not found")
+		}
+		return nil
+	})
+	if err != nil {
+		return types.ErrNavigateBack
+	}
+	packages, err := ui.GetInput("App packages (comma-separated; leave empty to detect)", "my_app, com.example.app", false, nil)
+	if err != nil && err.Error() != "cancelled" {
+		return err
+	}
+
+	source := NewFileErrorSource([]string{strings.TrimSpace(path)}, splitList(packages))
+	ui.ShowInfo("Parsing crash files...")
+	issues, warnings, err := source.Scan()
+	if err != nil {
+		ui.ShowError(err.Error())
+		return nil
+	}
+	for _, warning := range warnings {
+		ui.ShowWarning(fmt.Sprintf("Skipped %s", warning))
+	}
+	if len(issues) == 0 {
+		ui.ShowWarning("No crashes found.")
+		return nil
+	}
+
+	fmt.Println(crashGroupTable(issues).Render())
+
+	return m.syncBatch(cfg, conn, mapping, source, tracker, issues)
+}
+
+// crashGroupTable lists crash groups with their occurrences and top in-app frame
+func crashGroupTable(issues []SentryIssue) *ui.Table {
+	table := ui.NewTable(fmt.Sprintf("Crash Groups (%d)", len(issues)))
+	table.AddHeader("Signature", "Crashes", "Devices", "Platform", "Crash", "Location")
+	for _, issue := range issues {
+		devices := "-"
+		if issue.UserCount > 0 {
+			devices = fmt.Sprintf("%d", issue.UserCount)
+		}
+		table.AddRow(issue.ShortID, issue.Count, devices, issue.Platform, truncate(issue.Title, 60), truncate(issue.Culprit, 60))
+	}
+	return table
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
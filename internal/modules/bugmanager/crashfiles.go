@@ -0,0 +1,720 @@
+package bugmanager
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// crashFilesStateKey namespaces crash signatures in the sync state
+const crashFilesStateKey = "crash-files"
+
+// maxCrashFileSize skips files too large to be a single crash report or log dump
+const maxCrashFileSize = 32 << 20
+
+var (
+	// Flutter/Dart logs
+	dartLogPrefix   = regexp.MustCompile(`^[A-Z]/flutter\s*\(\s*\d+\):\s?`)
+	dartUnhandled   = regexp.MustCompile(`Unhandled Exception: (.+)$`)
+	dartThrown      = regexp.MustCompile(`The following (\S+?) (?:was|object was) thrown`)
+	dartFrame       = regexp.MustCompile(`^#(\d+)\s+(.+?) \((.+)\)$`)
+	dartLocation    = regexp.MustCompile(`^(.*?)(?::(\d+))?(?::(\d+))?$`)
+	logcatTimestamp = regexp.MustCompile(`^(\d\d-\d\d \d\d:\d\d:\d\d\.\d+)`)
+
+	// Android logcat (Java/Kotlin)
+	logcatRuntime = regexp.MustCompile(`AndroidRuntime\s*(?:\(\s*\d+\))?\s*:\s?(.*)$`)
+	logcatProcess = regexp.MustCompile(`^Process: ([\w.]+)`)
+	javaFrame     = regexp.MustCompile(`^\s*at ([\w$.<>]+)\.([\w$<>-]+)\((.*)\)$`)
+
+	// Android tombstones
+	tombstonePrefix    = regexp.MustCompile(`^(?:\d\d-\d\d \d\d:\d\d:\d\d\.\d+\s+\d+\s+\d+\s+)?[A-Z][/ ]DEBUG\s*(?:\(\s*\d+\))?\s*:\s?`)
+	tombstoneProcess   = regexp.MustCompile(`pid: \d+, tid: \d+, name: .+?\s+>>> (.+?) <<<`)
+	tombstoneSignal    = regexp.MustCompile(`signal \d+ \((\w+)\), code -?\d+ \((\w+)\), fault addr (\S+)`)
+	tombstoneAbort     = regexp.MustCompile(`Abort message: '(.*)'`)
+	tombstoneBuild     = regexp.MustCompile(`Build fingerprint: '(.+)'`)
+	tombstoneTimestamp = regexp.MustCompile(`^Timestamp: (.+)$`)
+	tombstoneFrame     = regexp.MustCompile(`^\s*#(\d+) pc ([0-9a-fA-F]+)\s+(\S+)(?:\s+\((.*?)\))?(?:\s+\(BuildId: \w*\))?\s*$`)
+)
+
+// dartFrameworkPackages are Dart packages whose frames are never in-app
+var dartFrameworkPackages = map[string]bool{
+	"flutter":             true,
+	"flutter_test":        true,
+	"flutter_web_plugins": true,
+}
+
+// javaFrameworkPrefixes are class prefixes whose frames are never in-app
+var javaFrameworkPrefixes = []string{
+	"android.", "androidx.", "com.android.", "com.google.android.", "dalvik.",
+	"java.", "javax.", "jdk.", "kotlin.", "kotlinx.", "libcore.", "sun.",
+}
+
+// nativeSystemPrefixes are library paths whose frames are never in-app
+var nativeSystemPrefixes = []string{"/system/", "/apex/", "/vendor/", "/product/", "["}
+
+// crashReport is a single crash parsed from a file. Frames are in Sentry order,
+// with the most recent call last.
+type crashReport struct {
+	Type      string
+	Message   string
+	Level     string
+	Platform  string
+	Frames    []SentryFrame
+	Timestamp time.Time
+	Release   string
+	Device    string
+	DeviceID  string
+	OS        string
+	File      string
+}
+
+// crashGroup is a set of crashes with the same signature
+type crashGroup struct {
+	Issue   SentryIssue
+	Latest  *crashReport
+	Files   []string
+	Devices map[string]bool
+}
+
+// FileErrorSource reads Flutter/Dart crash logs, Android tombstones and logcat dumps,
+// and JSON crash files, grouping crashes by exception type and top in-app frame
+type FileErrorSource struct {
+	paths       []string
+	appPackages []string
+	groups      map[string]*crashGroup
+}
+
+// NewFileErrorSource creates a crash file source for files and directories.
+// Frames of appPackages (Dart packages or Java package prefixes) are in-app;
+// without them, every non-framework frame is.
+func NewFileErrorSource(paths, appPackages []string) *FileErrorSource {
+	return &FileErrorSource{
+		paths:       paths,
+		appPackages: appPackages,
+		groups:      make(map[string]*crashGroup),
+	}
+}
+
+// Name returns the display name of the crash file source
+func (s *FileErrorSource) Name() string {
+	return "Crash"
+}
+
+// Scan parses every crash file and returns one issue per signature, most frequent first.
+// Files that cannot be read or parsed are returned as warnings.
+func (s *FileErrorSource) Scan() ([]SentryIssue, []string, error) {
+	var files, warnings []string
+	for _, path := range s.paths {
+		path = expandHome(path)
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.Walk(path, func(p string, fi os.FileInfo, err error) error {
+			if err != nil {
+				// An unreadable file or directory is skipped rather than aborting the import
+				warnings = append(warnings, fmt.Sprintf("%s: %v", p, err))
+				return nil
+			}
+			if fi.IsDir() && strings.HasPrefix(fi.Name(), ".") && p != path {
+				return filepath.SkipDir
+			}
+			if !fi.IsDir() && !strings.HasPrefix(fi.Name(), ".") {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan %s: %w", path, err)
+		}
+	}
+
+	s.groups = make(map[string]*crashGroup)
+	for _, file := range files {
+		crashes, err := s.parseFile(file)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %v", file, err))
+			continue
+		}
+		for i := range crashes {
+			s.add(&crashes[i])
+		}
+	}
+
+	issues := make([]SentryIssue, 0, len(s.groups))
+	for _, group := range s.groups {
+		issues = append(issues, group.Issue)
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		ci, _ := strconv.Atoi(issues[i].Count)
+		cj, _ := strconv.Atoi(issues[j].Count)
+		if ci != cj {
+			return ci > cj
+		}
+		return issues[i].LastSeen.After(issues[j].LastSeen)
+	})
+	return issues, warnings, nil
+}
+
+// GetIssueDetails returns a scanned crash group
+func (s *FileErrorSource) GetIssueDetails(issueID string) (*SentryIssue, error) {
+	group, ok := s.groups[issueID]
+	if !ok {
+		return nil, fmt.Errorf("crash group %s not found", issueID)
+	}
+	issue := group.Issue
+	return &issue, nil
+}
+
+// GetLatestEvent returns the most recent crash of a group as an event
+func (s *FileErrorSource) GetLatestEvent(issueID string) (*SentryEvent, error) {
+	group, ok := s.groups[issueID]
+	if !ok {
+		return nil, fmt.Errorf("crash group %s not found", issueID)
+	}
+	crash := group.Latest
+
+	event := &SentryEvent{
+		ID:       issueID,
+		EventID:  issueID,
+		Title:    group.Issue.Title,
+		Message:  crash.Message,
+		Platform: crash.Platform,
+		DateTime: crash.Timestamp,
+	}
+	event.Exception.Values = []SentryException{{Type: crash.Type, Value: crash.Message}}
+	event.Exception.Values[0].Stacktrace.Frames = crash.Frames
+
+	event.Tags = append(event.Tags, SentryTag{Key: "file", Value: crash.File})
+	for _, tag := range []SentryTag{{"device", crash.Device}, {"os", crash.OS}, {"release", crash.Release}} {
+		if tag.Value != "" {
+			event.Tags = append(event.Tags, tag)
+		}
+	}
+	if crash.Release != "" {
+		event.Release = &SentryRelease{Version: crash.Release}
+	}
+
+	files := make(map[string]interface{})
+	for i, file := range group.Files {
+		if i == 20 {
+			files["more"] = fmt.Sprintf("%d more files", len(group.Files)-i)
+			break
+		}
+		files[fmt.Sprintf("%02d", i+1)] = file
+	}
+	event.Contexts = map[string]map[string]interface{}{"crash files": files}
+
+	return event, nil
+}
+
+// add files a crash under its signature
+func (s *FileErrorSource) add(crash *crashReport) {
+	signature := crashSignature(crash)
+	sum := sha1.Sum([]byte(signature))
+	id := hex.EncodeToString(sum[:])[:12]
+
+	group, ok := s.groups[id]
+	if !ok {
+		title := crash.Type
+		if crash.Message != "" {
+			title += ": " + firstLine(crash.Message)
+		}
+		group = &crashGroup{
+			Issue: SentryIssue{
+				ID:        id,
+				ShortID:   strings.ToUpper(id[:8]),
+				Title:     truncate(title, 200),
+				Level:     crash.Level,
+				Status:    "unresolved",
+				Platform:  crash.Platform,
+				FirstSeen: crash.Timestamp,
+				Source:    s.Name(),
+				Metadata: map[string]interface{}{
+					"type":  crash.Type,
+					"value": crash.Message,
+				},
+			},
+			Devices: make(map[string]bool),
+		}
+		if frame := topCrashFrame(crash.Frames); frame != nil {
+			group.Issue.Culprit = fmt.Sprintf("%s (%s)", frame.Function, frame.Filename)
+			group.Issue.Metadata["filename"] = frame.Filename
+			group.Issue.Metadata["function"] = frame.Function
+			if frame.LineNo > 0 {
+				group.Issue.Metadata["lineNo"] = float64(frame.LineNo)
+			}
+		}
+		s.groups[id] = group
+	}
+
+	count, _ := strconv.Atoi(group.Issue.Count)
+	group.Issue.Count = strconv.Itoa(count + 1)
+	group.Files = appendUnique(group.Files, crash.File)
+	if crash.DeviceID != "" {
+		group.Devices[crash.DeviceID] = true
+		group.Issue.UserCount = len(group.Devices)
+	}
+	if crash.Timestamp.Before(group.Issue.FirstSeen) {
+		group.Issue.FirstSeen = crash.Timestamp
+	}
+	if group.Latest == nil || !crash.Timestamp.Before(group.Issue.LastSeen) {
+		group.Latest = crash
+		group.Issue.LastSeen = crash.Timestamp
+	}
+}
+
+// crashSignature identifies a crash by its exception type and top in-app frame.
+// Line numbers are left out so the signature survives unrelated edits.
+func crashSignature(crash *crashReport) string {
+	frame := topCrashFrame(crash.Frames)
+	if frame == nil {
+		return crash.Platform + "|" + crash.Type + "|" + firstLine(crash.Message)
+	}
+	return crash.Platform + "|" + crash.Type + "|" + frame.Function + "|" + frame.Filename
+}
+
+// topCrashFrame returns the most recent in-app frame, or the most recent frame without one
+func topCrashFrame(frames []SentryFrame) *SentryFrame {
+	for i := len(frames) - 1; i >= 0; i-- {
+		if frames[i].InApp {
+			return &frames[i]
+		}
+	}
+	if len(frames) > 0 {
+		return &frames[len(frames)-1]
+	}
+	return nil
+}
+
+// parseFile detects the format of a crash file and parses every crash in it
+func (s *FileErrorSource) parseFile(path string) ([]crashReport, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxCrashFileSize {
+		return nil, fmt.Errorf("file is larger than %d MB", maxCrashFileSize>>20)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var crashes []crashReport
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case strings.EqualFold(filepath.Ext(path), ".json") || (bytes.HasPrefix(trimmed, []byte("{")) || bytes.HasPrefix(trimmed, []byte("["))) && json.Valid(trimmed):
+		// Content is only sniffed as JSON when it parses; logcat -v long lines also start with "["
+		crashes, err = s.parseJSONCrashes(trimmed)
+	case bytes.Contains(data, []byte("*** *** ***")) || (bytes.Contains(data, []byte("backtrace:")) && tombstoneSignal.Match(data)):
+		crashes = s.parseTombstone(data)
+	case bytes.Contains(data, []byte("FATAL EXCEPTION")):
+		crashes = s.parseLogcat(data, info.ModTime())
+	case bytes.Contains(data, []byte("Unhandled Exception:")) || bytes.Contains(data, []byte("EXCEPTION CAUGHT BY")):
+		crashes = s.parseDartLog(data)
+	default:
+		return nil, fmt.Errorf("unrecognized crash format")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(crashes) == 0 {
+		return nil, fmt.Errorf("no crashes found")
+	}
+
+	for i := range crashes {
+		crashes[i].File = path
+		if crashes[i].Timestamp.IsZero() {
+			crashes[i].Timestamp = info.ModTime()
+		}
+		if crashes[i].Level == "" {
+			crashes[i].Level = "fatal"
+		}
+		if crashes[i].Type == "" {
+			crashes[i].Type = "Crash"
+		}
+	}
+	return crashes, nil
+}
+
+// parseDartLog parses unhandled exceptions and framework-caught exceptions from Flutter logs
+func (s *FileErrorSource) parseDartLog(data []byte) []crashReport {
+	var crashes []crashReport
+	var current *crashReport
+	awaitingMessage := false
+
+	finish := func() {
+		if current != nil && (len(current.Frames) > 0 || current.Message != "") {
+			reverseFrames(current.Frames)
+			crashes = append(crashes, *current)
+		}
+		current = nil
+	}
+
+	scanner := newLineScanner(data)
+	for scanner.Scan() {
+		line := strings.TrimRight(dartLogPrefix.ReplaceAllString(scanner.Text(), ""), " \r")
+
+		if match := dartUnhandled.FindStringSubmatch(line); match != nil {
+			finish()
+			current = &crashReport{Platform: "dart", Level: "fatal"}
+			current.Type, current.Message = splitException(match[1])
+			continue
+		}
+		if match := dartThrown.FindStringSubmatch(line); match != nil {
+			finish()
+			current = &crashReport{Platform: "dart", Level: "error", Type: match[1]}
+			awaitingMessage = true
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if match := dartFrame.FindStringSubmatch(trimmed); match != nil {
+			awaitingMessage = false
+			current.Frames = append(current.Frames, s.dartStackFrame(match[2], match[3]))
+			continue
+		}
+		if awaitingMessage && trimmed != "" {
+			current.Message = trimmed
+			awaitingMessage = false
+			continue
+		}
+		if len(current.Frames) > 0 && trimmed != "<asynchronous suspension>" && trimmed != "..." {
+			finish()
+		}
+	}
+	finish()
+	return crashes
+}
+
+// dartStackFrame converts a Dart frame such as "_HomeState._load (package:app/home.dart:30:9)"
+func (s *FileErrorSource) dartStackFrame(function, location string) SentryFrame {
+	frame := SentryFrame{Function: function, AbsPath: location}
+	if match := dartLocation.FindStringSubmatch(location); match != nil {
+		frame.Filename = match[1]
+		frame.LineNo, _ = strconv.Atoi(match[2])
+		frame.ColNo, _ = strconv.Atoi(match[3])
+	}
+
+	if !strings.HasPrefix(frame.Filename, "package:") {
+		return frame // dart: core libraries
+	}
+	pkg, rest, _ := strings.Cut(strings.TrimPrefix(frame.Filename, "package:"), "/")
+	frame.Module = pkg
+	if len(s.appPackages) > 0 {
+		frame.InApp = containsString(s.appPackages, pkg)
+	} else {
+		frame.InApp = !dartFrameworkPackages[pkg]
+	}
+	if frame.InApp {
+		// package:app/home.dart lives at lib/home.dart in the app's repository
+		frame.Filename = "lib/" + rest
+	}
+	return frame
+}
+
+// parseLogcat parses Java/Kotlin crashes reported by AndroidRuntime in a logcat dump.
+// The deepest "Caused by" exception is reported, since it is the root cause. Logcat omits
+// the year, so timestamps take the year of the file's modification time and dates after it
+// belong to the year before.
+func (s *FileErrorSource) parseLogcat(data []byte, modTime time.Time) []crashReport {
+	var crashes []crashReport
+	var current *crashReport
+	var process string
+	awaitingException := false
+
+	finish := func() {
+		if current != nil && current.Type != "" {
+			reverseFrames(current.Frames)
+			crashes = append(crashes, *current)
+		}
+		current = nil
+	}
+
+	scanner := newLineScanner(data)
+	for scanner.Scan() {
+		raw := scanner.Text()
+		match := logcatRuntime.FindStringSubmatch(raw)
+		if match == nil {
+			continue
+		}
+		line := strings.TrimRight(match[1], " \r")
+
+		if strings.HasPrefix(line, "FATAL EXCEPTION") {
+			finish()
+			current = &crashReport{Platform: "java", Level: "fatal"}
+			if ts := logcatTimestamp.FindStringSubmatch(raw); ts != nil {
+				if t, err := time.ParseInLocation("01-02 15:04:05.000", ts[1], time.Local); err == nil {
+					t = t.AddDate(modTime.Year(), 0, 0)
+					if t.After(modTime) {
+						t = t.AddDate(-1, 0, 0)
+					}
+					current.Timestamp = t
+				}
+			}
+			awaitingException = true
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if p := logcatProcess.FindStringSubmatch(line); p != nil {
+			process = p[1]
+			continue
+		}
+
+		if frame := javaFrame.FindStringSubmatch(line); frame != nil {
+			current.Frames = append(current.Frames, s.javaStackFrame(frame[1], frame[2], frame[3], process))
+			continue
+		}
+		if strings.HasPrefix(line, "Caused by: ") {
+			current.Type, current.Message = splitException(strings.TrimPrefix(line, "Caused by: "))
+			current.Frames = nil
+			continue
+		}
+		if awaitingException && strings.TrimSpace(line) != "" {
+			current.Type, current.Message = splitException(strings.TrimSpace(line))
+			awaitingException = false
+		}
+	}
+	finish()
+	return crashes
+}
+
+// javaStackFrame converts a Java frame such as "com.app.Main.onCreate(Main.java:42)"
+func (s *FileErrorSource) javaStackFrame(class, method, location, process string) SentryFrame {
+	frame := SentryFrame{Function: class + "." + method, Module: class, AbsPath: location}
+
+	file, line, _ := strings.Cut(location, ":")
+	frame.LineNo, _ = strconv.Atoi(line)
+	if file != "" && file != "Native Method" && file != "Unknown Source" {
+		// Source files live next to their package, e.g. com/app/Main.java
+		if i := strings.LastIndex(class, "."); i != -1 {
+			frame.Filename = strings.ReplaceAll(class[:i], ".", "/") + "/" + file
+		} else {
+			frame.Filename = file
+		}
+	}
+
+	switch {
+	case len(s.appPackages) > 0:
+		for _, pkg := range s.appPackages {
+			frame.InApp = frame.InApp || strings.HasPrefix(class, pkg+".")
+		}
+	case process != "" && strings.HasPrefix(class, process+"."):
+		frame.InApp = true
+	default:
+		frame.InApp = true
+		for _, prefix := range javaFrameworkPrefixes {
+			if strings.HasPrefix(class, prefix) {
+				frame.InApp = false
+				break
+			}
+		}
+	}
+	return frame
+}
+
+// parseTombstone parses a native crash tombstone, keeping the crashing thread's backtrace
+func (s *FileErrorSource) parseTombstone(data []byte) []crashReport {
+	crash := crashReport{Platform: "native", Level: "fatal"}
+	inBacktrace, done := false, false
+	var process string
+
+	scanner := newLineScanner(data)
+	for scanner.Scan() && !done {
+		line := strings.TrimRight(tombstonePrefix.ReplaceAllString(scanner.Text(), ""), " \r")
+
+		switch {
+		case tombstoneBuild.MatchString(line):
+			crash.OS = tombstoneBuild.FindStringSubmatch(line)[1]
+			if parts := strings.Split(crash.OS, "/"); len(parts) > 2 {
+				crash.Device = parts[1]
+			}
+		case tombstoneTimestamp.MatchString(line):
+			value := tombstoneTimestamp.FindStringSubmatch(line)[1]
+			if t, err := time.Parse("2006-01-02 15:04:05.999999999-0700", value); err == nil {
+				crash.Timestamp = t
+			}
+		case tombstoneProcess.MatchString(line):
+			process = tombstoneProcess.FindStringSubmatch(line)[1]
+		case tombstoneSignal.MatchString(line):
+			match := tombstoneSignal.FindStringSubmatch(line)
+			crash.Type = match[1]
+			if crash.Message == "" {
+				crash.Message = fmt.Sprintf("%s at %s", match[2], match[3])
+			}
+		case tombstoneAbort.MatchString(line):
+			crash.Message = tombstoneAbort.FindStringSubmatch(line)[1]
+		case strings.TrimSpace(line) == "backtrace:":
+			inBacktrace = true
+		case inBacktrace:
+			match := tombstoneFrame.FindStringSubmatch(line)
+			if match == nil {
+				done = len(crash.Frames) > 0
+				continue
+			}
+			crash.Frames = append(crash.Frames, nativeStackFrame(match[2], match[3], match[4], process))
+		}
+	}
+
+	if crash.Type == "" && len(crash.Frames) == 0 {
+		return nil
+	}
+	reverseFrames(crash.Frames)
+	return []crashReport{crash}
+}
+
+// nativeStackFrame converts a tombstone frame; libraries outside the system partitions are in-app
+func nativeStackFrame(pc, path, symbol, process string) SentryFrame {
+	frame := SentryFrame{Filename: filepath.Base(path), AbsPath: path, Function: "pc 0x" + pc}
+	if symbol != "" && !strings.HasPrefix(symbol, "BuildId:") {
+		if i := strings.LastIndex(symbol, "+"); i != -1 {
+			symbol = symbol[:i]
+		}
+		frame.Function = symbol
+	}
+
+	frame.InApp = process != "" && strings.Contains(path, process)
+	if !frame.InApp {
+		frame.InApp = true
+		for _, prefix := range nativeSystemPrefixes {
+			if strings.HasPrefix(path, prefix) {
+				frame.InApp = false
+				break
+			}
+		}
+	}
+	return frame
+}
+
+// jsonCrash is a crash in the JSON crash file format. Frames are listed with the most recent call first.
+type jsonCrash struct {
+	Type      string    `json:"type"`
+	Message   string    `json:"message"`
+	Level     string    `json:"level"`
+	Platform  string    `json:"platform"`
+	Timestamp time.Time `json:"timestamp"`
+	Release   string    `json:"release"`
+	Device    string    `json:"device"`
+	DeviceID  string    `json:"device_id"`
+	OS        string    `json:"os"`
+	Frames    []struct {
+		Function string `json:"function"`
+		File     string `json:"file"`
+		Line     int    `json:"line"`
+		Column   int    `json:"column"`
+		InApp    *bool  `json:"in_app"` // Defaults to true
+	} `json:"frames"`
+}
+
+// parseJSONCrashes parses a JSON crash object or an array of them
+func (s *FileErrorSource) parseJSONCrashes(data []byte) ([]crashReport, error) {
+	var items []jsonCrash
+	if bytes.HasPrefix(data, []byte("[")) {
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, fmt.Errorf("invalid JSON crash file: %w", err)
+		}
+	} else {
+		var item jsonCrash
+		if err := json.Unmarshal(data, &item); err != nil {
+			return nil, fmt.Errorf("invalid JSON crash file: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	crashes := make([]crashReport, 0, len(items))
+	for _, item := range items {
+		crash := crashReport{
+			Type:      item.Type,
+			Message:   item.Message,
+			Level:     item.Level,
+			Platform:  item.Platform,
+			Timestamp: item.Timestamp,
+			Release:   item.Release,
+			Device:    item.Device,
+			DeviceID:  item.DeviceID,
+			OS:        item.OS,
+		}
+		if crash.Platform == "" {
+			crash.Platform = "other"
+		}
+		for _, f := range item.Frames {
+			crash.Frames = append(crash.Frames, SentryFrame{
+				Function: f.Function,
+				Filename: f.File,
+				LineNo:   f.Line,
+				ColNo:    f.Column,
+				InApp:    f.InApp == nil || *f.InApp,
+			})
+		}
+		reverseFrames(crash.Frames)
+		crashes = append(crashes, crash)
+	}
+	return crashes, nil
+}
+
+// newLineScanner scans lines of up to 1 MB
+func newLineScanner(data []byte) *bufio.Scanner {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	return scanner
+}
+
+// splitException splits "Type: message" into its type and message
+func splitException(line string) (string, string) {
+	exceptionType, message, found := strings.Cut(line, ": ")
+	if !found {
+		return strings.TrimSuffix(strings.TrimSpace(line), ":"), ""
+	}
+	return strings.TrimSpace(exceptionType), strings.TrimSpace(message)
+}
+
+// reverseFrames turns a most-recent-first stack into Sentry order
+func reverseFrames(frames []SentryFrame) {
+	for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+		frames[i], frames[j] = frames[j], frames[i]
+	}
+}
+
+// firstLine returns the first line of a possibly multi-line message
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return strings.TrimSpace(line)
+}
+
+// appendUnique appends value unless the list already contains it
+func appendUnique(list []string, value string) []string {
+	if containsString(list, value) {
+		return list
+	}
+	return append(list, value)
+}
+
+// containsString reports whether list contains value
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
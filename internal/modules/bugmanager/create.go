@@ -0,0 +1,207 @@
+package bugmanager
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kkz6/devtools/internal/config"
+	"github.com/kkz6/devtools/internal/types"
+	"github.com/kkz6/devtools/internal/ui"
+)
+
+// createIssueFromEditor opens a markdown template in $EDITOR and creates the written issue
+func (m *Module) createIssueFromEditor(cfg *config.Config, tracker IssueTracker, tmpl *config.BugManagerTemplate) error {
+	ui.ShowInfo("Fetching teams...")
+	teams, err := tracker.GetTeams()
+	if err != nil {
+		ui.ShowError(fmt.Sprintf("Failed to fetch teams: %v", err))
+		return nil
+	}
+
+	draft := IssueDraft{Type: "Bug", Priority: "medium", Labels: []string{"bug"}}
+	if tmpl != nil {
+		draft = applyTemplate(IssueDraft{}, tmpl)
+	}
+	if len(teams) > 0 {
+		draft.Team = teams[0].Key
+	}
+
+	content := renderIssueMarkdown(draft, teamHint(teams))
+	for {
+		edited, err := editInEditor(content, "issue-*.md")
+		if err != nil {
+			ui.ShowError(err.Error())
+			return nil
+		}
+		content = edited
+
+		resolver := m.newDraftResolver(cfg, tracker, tmpl)
+		resolved, err := m.parseAndResolve(resolver, []byte(content))
+		if err != nil {
+			ui.ShowError(err.Error())
+			if ui.GetConfirmation("Edit the issue again?") {
+				continue
+			}
+			return types.ErrNavigateBack
+		}
+
+		return m.confirmAndCreate(resolver, resolved)
+	}
+}
+
+// createIssueFromFile creates an issue from a markdown file with YAML front matter
+func (m *Module) createIssueFromFile(cfg *config.Config, tracker IssueTracker, tmpl *config.BugManagerTemplate) error {
+	path, err := ui.GetInput("Markdown file path", "", false, func(s string) error {
+		if _, err := os.Stat(expandHome(s)); err != nil {
+			return fmt.Errorf("file not found")
+		}
+		return nil
+	})
+	if err != nil {
+		if err.Error() == "cancelled" {
+			return types.ErrNavigateBack
+		}
+		return err
+	}
+
+	data, err := os.ReadFile(expandHome(path))
+	if err != nil {
+		ui.ShowError(fmt.Sprintf("Failed to read file: %v", err))
+		return nil
+	}
+
+	resolver := m.newDraftResolver(cfg, tracker, tmpl)
+	resolved, err := m.parseAndResolve(resolver, data)
+	if err != nil {
+		ui.ShowError(err.Error())
+		return nil
+	}
+
+	return m.confirmAndCreate(resolver, resolved)
+}
+
+// bulkImportIssues creates one issue per row of a CSV or YAML file after a preview.
+// Rows without a template use tmpl.
+func (m *Module) bulkImportIssues(cfg *config.Config, tracker IssueTracker, tmpl *config.BugManagerTemplate) error {
+	path, err := ui.GetInput("CSV or YAML file path", "", false, func(s string) error {
+		if _, err := os.Stat(expandHome(s)); err != nil {
+			return fmt.Errorf("file not found")
+		}
+		return nil
+	})
+	if err != nil {
+		if err.Error() == "cancelled" {
+			return types.ErrNavigateBack
+		}
+		return err
+	}
+
+	defaultTeam, err := ui.GetInput("Default team for rows without one (key or name, optional)", "", false, nil)
+	if err != nil && err.Error() == "cancelled" {
+		return types.ErrNavigateBack
+	}
+
+	drafts, err := loadBulkDrafts(expandHome(path), strings.TrimSpace(defaultTeam))
+	if err != nil {
+		ui.ShowError(err.Error())
+		return nil
+	}
+	if len(drafts) == 0 {
+		ui.ShowWarning("The file contains no issues.")
+		return nil
+	}
+
+	ui.ShowInfo(fmt.Sprintf("Validating %d issues...", len(drafts)))
+	resolver := m.newDraftResolver(cfg, tracker, tmpl)
+	rows := m.resolveBulk(resolver, drafts)
+
+	fmt.Println()
+	m.printBulkPreview(rows)
+	fmt.Println()
+
+	valid := 0
+	for _, row := range rows {
+		if row.Err == nil {
+			valid++
+		}
+	}
+	if valid == 0 {
+		ui.ShowError("No valid rows to import.")
+		return nil
+	}
+	if valid < len(rows) {
+		ui.ShowWarning(fmt.Sprintf("%d of %d rows are invalid and will be skipped.", len(rows)-valid, len(rows)))
+	}
+
+	if !ui.GetConfirmation(fmt.Sprintf("Create %d issues in %s?", valid, tracker.Name())) {
+		return types.ErrNavigateBack
+	}
+
+	m.createBulk(resolver, rows)
+
+	fmt.Println()
+	created, failed := m.printBulkReport(rows)
+	fmt.Println()
+	if failed > 0 {
+		ui.ShowWarning(fmt.Sprintf("Created %d issues, %d rows failed or were skipped.", created, failed))
+	} else {
+		ui.ShowSuccess(fmt.Sprintf("Created %d issues.", created))
+	}
+
+	if ui.GetConfirmation("Save the report as CSV?") {
+		reportPath := strings.TrimSuffix(expandHome(path), ".csv") + ".report.csv"
+		if err := writeBulkReport(reportPath, rows); err != nil {
+			ui.ShowError(err.Error())
+		} else {
+			ui.ShowSuccess(fmt.Sprintf("Report saved to %s", reportPath))
+		}
+	}
+
+	return nil
+}
+
+// parseAndResolve parses a markdown issue and resolves it against the tracker
+func (m *Module) parseAndResolve(resolver *draftResolver, data []byte) (*resolvedDraft, error) {
+	draft, err := parseIssueMarkdown(data)
+	if err != nil {
+		return nil, err
+	}
+	return resolver.resolve(*draft)
+}
+
+// confirmAndCreate shows a resolved issue and creates it after confirmation
+func (m *Module) confirmAndCreate(resolver *draftResolver, resolved *resolvedDraft) error {
+	printResolvedDraft(m, resolver.tracker, resolved)
+
+	if !ui.GetConfirmation(fmt.Sprintf("Create this issue in %s?", resolver.tracker.Name())) {
+		return types.ErrNavigateBack
+	}
+
+	ui.ShowInfo(fmt.Sprintf("Creating issue in %s...", resolver.tracker.Name()))
+	issue, err := resolver.create(resolved)
+	if err != nil {
+		ui.ShowError(fmt.Sprintf("Failed to create issue: %v", err))
+		return nil
+	}
+
+	ui.ShowSuccess(fmt.Sprintf("Issue created successfully!\nURL: %s", issue.URL))
+	fmt.Println("\nPress Enter to continue...")
+	fmt.Scanln()
+	return nil
+}
+
+// teamHint lists the available team keys for the editor template
+func teamHint(teams []TrackerTeam) string {
+	if len(teams) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(teams))
+	for _, team := range teams {
+		keys = append(keys, team.Key)
+	}
+	if len(keys) > 10 {
+		keys = append(keys[:10], "...")
+	}
+	return "Teams: " + strings.Join(keys, ", ")
+}
@@ -0,0 +1,272 @@
+package bugmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+)
+
+// maxBreadcrumbs limits how many of the most recent breadcrumbs are rendered
+const maxBreadcrumbs = 30
+
+// sensitiveHeaders lists request headers that are never copied into issues
+var sensitiveHeaders = map[string]bool{
+	"authorization": true,
+	"cookie":        true,
+	"set-cookie":    true,
+	"x-api-key":     true,
+}
+
+// writeCollapsible writes a collapsible section using Linear's +++ syntax. Trackers
+// without it convert the sections when filing the issue, see htmlCollapsibles.
+func writeCollapsible(b *strings.Builder, title, body string) {
+	b.WriteString(fmt.Sprintf("+++ %s\n\n", title))
+	b.WriteString(strings.TrimRight(body, "\n"))
+	b.WriteString("\n\n+++\n\n")
+}
+
+// htmlCollapsibles turns Linear's "+++ title" ... "+++" sections into HTML <details>
+// blocks, which GitHub renders as collapsible sections. Markers inside code blocks and
+// unterminated sections are left alone.
+func htmlCollapsibles(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+	result := make([]string, 0, len(lines))
+	open := -1 // Index in result of the pending section's opening marker
+	inCode := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			inCode = !inCode
+		case inCode:
+		case open == -1 && strings.HasPrefix(trimmed, "+++ "):
+			open = len(result)
+		case open != -1 && trimmed == "+++":
+			title := strings.TrimSpace(strings.TrimPrefix(result[open], "+++"))
+			result[open] = fmt.Sprintf("<details>\n<summary>%s</summary>", html.EscapeString(title))
+			result = append(result, "</details>")
+			open = -1
+			continue
+		}
+		result = append(result, line)
+	}
+	return strings.Join(result, "\n")
+}
+
+// writeSourceContext writes the source lines around each in-app frame
+func (m *Module) writeSourceContext(b *strings.Builder, exception SentryException) {
+	frames := exception.Stacktrace.Frames
+	for i := len(frames) - 1; i >= 0; i-- {
+		frame := frames[i]
+		if !frame.InApp || len(frame.Context) == 0 {
+			continue
+		}
+
+		var code strings.Builder
+		code.WriteString("```\n")
+		for _, line := range frame.Context {
+			if len(line) < 2 {
+				continue
+			}
+			lineNo, _ := line[0].(float64)
+			text, _ := line[1].(string)
+			marker := "  "
+			if int(lineNo) == frame.LineNo {
+				marker = "→ "
+			}
+			code.WriteString(fmt.Sprintf("%s%5d | %s\n", marker, int(lineNo), text))
+		}
+		code.WriteString("```")
+
+		writeCollapsible(b, fmt.Sprintf("%s:%d in %s", frame.Filename, frame.LineNo, frame.Function), code.String())
+	}
+}
+
+// writeEventContext writes tags, contexts, user, request and breadcrumbs as collapsible sections
+func (m *Module) writeEventContext(b *strings.Builder, event *SentryEvent) {
+	if len(event.Tags) > 0 {
+		var body strings.Builder
+		body.WriteString("| Tag | Value |\n|---|---|\n")
+		for _, tag := range event.Tags {
+			body.WriteString(fmt.Sprintf("| %s | %s |\n", escapeTableCell(tag.Key), escapeTableCell(tag.Value)))
+		}
+		writeCollapsible(b, "Tags", body.String())
+	}
+
+	// Render the contexts engineers usually triage by first, then the rest alphabetically
+	contextNames := make([]string, 0, len(event.Contexts))
+	for name := range event.Contexts {
+		contextNames = append(contextNames, name)
+	}
+	sort.Slice(contextNames, func(i, j int) bool {
+		pi, pj := contextPriority(contextNames[i]), contextPriority(contextNames[j])
+		if pi != pj {
+			return pi < pj
+		}
+		return contextNames[i] < contextNames[j]
+	})
+
+	if len(contextNames) > 0 {
+		var body strings.Builder
+		for _, name := range contextNames {
+			if name == "" {
+				continue
+			}
+			values := event.Contexts[name]
+			body.WriteString(fmt.Sprintf("**%s**\n", capitalize(name)))
+
+			keys := make([]string, 0, len(values))
+			for key := range values {
+				if key != "type" {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				body.WriteString(fmt.Sprintf("- %s: `%v`\n", key, values[key]))
+			}
+			body.WriteString("\n")
+		}
+		writeCollapsible(b, "Contexts (OS, Device, Runtime)", body.String())
+	}
+
+	if event.User != nil {
+		var body strings.Builder
+		if event.User.ID != "" {
+			body.WriteString(fmt.Sprintf("- ID: `%s`\n", event.User.ID))
+		}
+		if event.User.Username != "" {
+			body.WriteString(fmt.Sprintf("- Username: `%s`\n", event.User.Username))
+		}
+		if event.User.Name != "" {
+			body.WriteString(fmt.Sprintf("- Name: %s\n", event.User.Name))
+		}
+		if event.User.Email != "" {
+			body.WriteString(fmt.Sprintf("- Email: %s\n", event.User.Email))
+		}
+		if event.User.IPAddress != "" {
+			body.WriteString(fmt.Sprintf("- IP Address: `%s`\n", event.User.IPAddress))
+		}
+		if body.Len() > 0 {
+			writeCollapsible(b, "User", body.String())
+		}
+	}
+
+	if event.Request != nil && event.Request.URL != "" {
+		var body strings.Builder
+		body.WriteString(fmt.Sprintf("**%s** `%s`\n\n", event.Request.Method, event.Request.URL))
+
+		if query := rawToString(event.Request.Query); query != "" {
+			body.WriteString(fmt.Sprintf("**Query:** `%s`\n\n", query))
+		}
+
+		if len(event.Request.Headers) > 0 {
+			body.WriteString("| Header | Value |\n|---|---|\n")
+			for _, header := range event.Request.Headers {
+				if len(header) < 2 {
+					continue
+				}
+				name, _ := header[0].(string)
+				value := fmt.Sprintf("%v", header[1])
+				if sensitiveHeaders[strings.ToLower(name)] {
+					value = "[Filtered]"
+				}
+				body.WriteString(fmt.Sprintf("| %s | %s |\n", escapeTableCell(name), escapeTableCell(value)))
+			}
+			body.WriteString("\n")
+		}
+
+		if data := rawToString(event.Request.Data); data != "" {
+			body.WriteString(fmt.Sprintf("**Body:**\n```\n%s\n```\n", data))
+		}
+
+		writeCollapsible(b, "Request", body.String())
+	}
+
+	if len(event.Breadcrumbs) > 0 {
+		crumbs := event.Breadcrumbs
+		if len(crumbs) > maxBreadcrumbs {
+			crumbs = crumbs[len(crumbs)-maxBreadcrumbs:]
+		}
+
+		var body strings.Builder
+		body.WriteString("| Time | Category | Level | Message |\n|---|---|---|---|\n")
+		for _, crumb := range crumbs {
+			message := crumb.Message
+			if message == "" && len(crumb.Data) > 0 {
+				if data, err := json.Marshal(crumb.Data); err == nil {
+					message = string(data)
+				}
+			}
+			body.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+				escapeTableCell(crumb.Timestamp),
+				escapeTableCell(crumb.Category),
+				escapeTableCell(crumb.Level),
+				escapeTableCell(message)))
+		}
+		writeCollapsible(b, fmt.Sprintf("Breadcrumbs (last %d)", len(crumbs)), body.String())
+	}
+}
+
+// contextPriority orders the most useful contexts first
+func contextPriority(name string) int {
+	switch name {
+	case "os":
+		return 0
+	case "device":
+		return 1
+	case "runtime":
+		return 2
+	case "browser":
+		return 3
+	case "app":
+		return 4
+	default:
+		return 5
+	}
+}
+
+// capitalize upper-cases the first letter of s
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// rawToString renders a raw JSON value (string, pairs list or object) as plain text
+func rawToString(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		return str
+	}
+
+	var pairs [][]interface{}
+	if err := json.Unmarshal(raw, &pairs); err == nil {
+		parts := make([]string, 0, len(pairs))
+		for _, pair := range pairs {
+			if len(pair) == 2 {
+				parts = append(parts, fmt.Sprintf("%v=%v", pair[0], pair[1]))
+			}
+		}
+		return strings.Join(parts, "&")
+	}
+
+	return string(raw)
+}
+
+// escapeTableCell makes a value safe to use inside a markdown table cell
+func escapeTableCell(value string) string {
+	value = strings.ReplaceAll(value, "\n", " ")
+	value = strings.ReplaceAll(value, "|", "\\|")
+	if len(value) > 200 {
+		value = value[:200] + "..."
+	}
+	return value
+}
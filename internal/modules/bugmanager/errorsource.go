@@ -0,0 +1,25 @@
+package bugmanager
+
+import "github.com/kkz6/devtools/internal/config"
+
+// ErrorSource provides grouped errors to turn into tracker issues. Issues and events
+// use the Sentry model so every source goes through the same creation and dedup flow.
+type ErrorSource interface {
+	Name() string
+	GetIssueDetails(issueID string) (*SentryIssue, error)
+	GetLatestEvent(issueID string) (*SentryEvent, error)
+}
+
+// Name returns the display name of the Sentry error source
+func (c *SentryClient) Name() string {
+	return "Sentry"
+}
+
+// sourceStateKey returns the sync state namespace of a source's issues:
+// the connection's Sentry instance, or a fixed key for crash files
+func sourceStateKey(source ErrorSource, conn *config.BugManagerConnection) string {
+	if _, ok := source.(*FileErrorSource); ok {
+		return crashFilesStateKey
+	}
+	return conn.SentryInstance
+}
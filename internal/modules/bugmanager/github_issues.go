@@ -0,0 +1,521 @@
+package bugmanager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const githubAPIURL = "https://api.github.com"
+
+// githubNextLink matches the next page URL of a GitHub Link header
+var githubNextLink = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// githubPriorityLabels maps Linear-style priorities to the labels used on GitHub,
+// which has no native issue priority
+var githubPriorityLabels = map[int]string{
+	1: "priority: urgent",
+	2: "priority: high",
+	3: "priority: medium",
+	4: "priority: low",
+}
+
+// GitHubIssuesClient files issues in GitHub repositories.
+// Teams are repositories ("owner/repo") and projects are milestones.
+type GitHubIssuesClient struct {
+	token  string
+	client *http.Client
+}
+
+// NewGitHubIssuesClient creates a new GitHub Issues client
+func NewGitHubIssuesClient(token string) *GitHubIssuesClient {
+	return &GitHubIssuesClient{
+		token:  token,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// githubIssue is an issue as returned by the GitHub REST API
+type githubIssue struct {
+	ID      int64  `json:"id"`
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	State   string `json:"state"`
+	HTMLURL string `json:"html_url"`
+	Labels  []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+// do sends a request to the GitHub API and decodes the response into out.
+// It returns the URL of the next page, if any.
+func (c *GitHubIssuesClient) do(method, rawURL string, body, out interface{}) (string, error) {
+	header, err := c.doHeader(method, rawURL, body, out)
+	if err != nil {
+		return "", err
+	}
+
+	var next string
+	if match := githubNextLink.FindStringSubmatch(header.Get("Link")); match != nil {
+		next = match[1]
+	}
+	return next, nil
+}
+
+// doHeader sends a request to the GitHub API, decodes the response into out
+// and returns the response headers
+func (c *GitHubIssuesClient) doHeader(method, rawURL string, body, out interface{}) (http.Header, error) {
+	if !strings.HasPrefix(rawURL, "http") {
+		rawURL = githubAPIURL + rawURL
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, rawURL, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "token "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, &githubAPIError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+
+	return resp.Header, nil
+}
+
+// githubAPIError is a non-2xx response from the GitHub API
+type githubAPIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *githubAPIError) Error() string {
+	return fmt.Sprintf("GitHub API error (status %d): %s", e.StatusCode, e.Body)
+}
+
+// Name returns the display name of the tracker
+func (c *GitHubIssuesClient) Name() string {
+	return "GitHub Issues"
+}
+
+// GetTeams lists the repositories with issues enabled that the token can access
+func (c *GitHubIssuesClient) GetTeams() ([]TrackerTeam, error) {
+	var teams []TrackerTeam
+	next := "/user/repos?per_page=100&sort=updated"
+
+	for next != "" {
+		var repos []struct {
+			FullName  string `json:"full_name"`
+			Name      string `json:"name"`
+			HasIssues bool   `json:"has_issues"`
+			Archived  bool   `json:"archived"`
+		}
+
+		var err error
+		next, err = c.do("GET", next, nil, &repos)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, repo := range repos {
+			if !repo.HasIssues || repo.Archived {
+				continue
+			}
+			teams = append(teams, TrackerTeam{ID: repo.FullName, Name: repo.FullName, Key: repo.Name})
+		}
+	}
+
+	return teams, nil
+}
+
+// GetProjects lists the open milestones of a repository
+func (c *GitHubIssuesClient) GetProjects(repo string) ([]TrackerProject, error) {
+	var projects []TrackerProject
+	next := fmt.Sprintf("/repos/%s/milestones?state=open&per_page=100", repo)
+
+	for next != "" {
+		var milestones []struct {
+			Number      int    `json:"number"`
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			State       string `json:"state"`
+		}
+
+		var err error
+		next, err = c.do("GET", next, nil, &milestones)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, milestone := range milestones {
+			projects = append(projects, TrackerProject{
+				ID:          strconv.Itoa(milestone.Number),
+				Name:        milestone.Title,
+				Description: milestone.Description,
+				State:       milestone.State,
+			})
+		}
+	}
+	return projects, nil
+}
+
+// GetWorkflowStates returns the two states GitHub issues can be in
+func (c *GitHubIssuesClient) GetWorkflowStates(repo string) ([]TrackerState, error) {
+	return []TrackerState{
+		{ID: "open", Name: "Open", Type: "unstarted"},
+		{ID: "closed", Name: "Closed", Type: "completed"},
+	}, nil
+}
+
+// GetUsers lists the users issues in a repository can be assigned to
+func (c *GitHubIssuesClient) GetUsers(repo string) ([]TrackerUser, error) {
+	var users []TrackerUser
+	next := fmt.Sprintf("/repos/%s/assignees?per_page=100", repo)
+
+	for next != "" {
+		var assignees []struct {
+			Login string `json:"login"`
+		}
+
+		var err error
+		next, err = c.do("GET", next, nil, &assignees)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, assignee := range assignees {
+			users = append(users, TrackerUser{
+				ID:          assignee.Login,
+				Name:        assignee.Login,
+				DisplayName: assignee.Login,
+				Active:      true,
+			})
+		}
+	}
+
+	return users, nil
+}
+
+// GetOrCreateLabel ensures a label exists in the repository.
+// GitHub references labels by name, so the name is returned as the ID.
+func (c *GitHubIssuesClient) GetOrCreateLabel(repo, name, color string) (string, error) {
+	_, err := c.do("GET", fmt.Sprintf("/repos/%s/labels/%s", repo, url.PathEscape(name)), nil, nil)
+	if err == nil {
+		return name, nil
+	}
+	if apiErr, ok := err.(*githubAPIError); !ok || apiErr.StatusCode != http.StatusNotFound {
+		return "", err
+	}
+
+	payload := map[string]string{
+		"name":  name,
+		"color": strings.TrimPrefix(color, "#"),
+	}
+	if _, err := c.do("POST", fmt.Sprintf("/repos/%s/labels", repo), payload, nil); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// CreateIssue opens a new issue. The priority is applied as a label.
+func (c *GitHubIssuesClient) CreateIssue(input TrackerIssueInput) (*TrackerIssue, error) {
+	labels := append([]string{}, input.LabelIDs...)
+	if label, ok := githubPriorityLabels[input.Priority]; ok {
+		labels = append(labels, label)
+	}
+
+	payload := map[string]interface{}{
+		"title":  input.Title,
+		"body":   htmlCollapsibles(input.Description),
+		"labels": labels,
+	}
+	if input.ProjectID != "" {
+		milestone, err := strconv.Atoi(input.ProjectID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid milestone number '%s'", input.ProjectID)
+		}
+		payload["milestone"] = milestone
+	}
+	if input.AssigneeID != "" {
+		payload["assignees"] = []string{input.AssigneeID}
+	}
+
+	var issue githubIssue
+	if _, err := c.do("POST", fmt.Sprintf("/repos/%s/issues", input.TeamID), payload, &issue); err != nil {
+		return nil, err
+	}
+
+	// Attach the new issue to its parent through the sub-issues API
+	if input.ParentID != "" {
+		parentRepo, parentNumber, err := parseGitHubIssueID(input.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		subIssue := map[string]interface{}{"sub_issue_id": issue.ID}
+		if _, err := c.do("POST", fmt.Sprintf("/repos/%s/issues/%d/sub_issues", parentRepo, parentNumber), subIssue, nil); err != nil {
+			return nil, fmt.Errorf("created #%d but failed to attach it to %s: %w", issue.Number, input.ParentID, err)
+		}
+	}
+
+	// New issues are always open; close it if another state was requested
+	if input.StateID == "closed" {
+		closed := "closed"
+		return c.UpdateIssue(githubIssueID(input.TeamID, issue.Number), TrackerIssueUpdateInput{StateID: &closed})
+	}
+
+	return issue.toTrackerIssue(input.TeamID), nil
+}
+
+// UpdateIssue changes an existing issue identified by "owner/repo#number"
+func (c *GitHubIssuesClient) UpdateIssue(issueID string, input TrackerIssueUpdateInput) (*TrackerIssue, error) {
+	repo, number, err := parseGitHubIssueID(issueID)
+	if err != nil {
+		return nil, err
+	}
+	issuePath := fmt.Sprintf("/repos/%s/issues/%d", repo, number)
+
+	payload := map[string]interface{}{}
+	if input.Title != nil {
+		payload["title"] = *input.Title
+	}
+	if input.Description != nil {
+		payload["body"] = htmlCollapsibles(*input.Description)
+	}
+	if input.StateID != nil {
+		payload["state"] = *input.StateID
+	}
+	if input.AssigneeID != nil {
+		// An empty assignee unassigns the issue
+		if *input.AssigneeID == "" {
+			payload["assignees"] = []string{}
+		} else {
+			payload["assignees"] = []string{*input.AssigneeID}
+		}
+	}
+
+	if input.LabelIDs != nil || input.Priority != nil {
+		labels := input.LabelIDs
+		if labels == nil {
+			// Keep the current labels when only the priority changes
+			var current githubIssue
+			if _, err := c.do("GET", issuePath, nil, &current); err != nil {
+				return nil, err
+			}
+			for _, label := range current.Labels {
+				labels = append(labels, label.Name)
+			}
+		}
+
+		var updated []string
+		for _, label := range labels {
+			if input.Priority == nil || !strings.HasPrefix(label, "priority: ") {
+				updated = append(updated, label)
+			}
+		}
+		if input.Priority != nil {
+			if label, ok := githubPriorityLabels[*input.Priority]; ok {
+				updated = append(updated, label)
+			}
+		}
+		if updated == nil {
+			updated = []string{}
+		}
+		payload["labels"] = updated
+	}
+
+	var issue githubIssue
+	if _, err := c.do("PATCH", issuePath, payload, &issue); err != nil {
+		return nil, err
+	}
+
+	return issue.toTrackerIssue(repo), nil
+}
+
+// SearchIssues finds issues in a repository matching a GitHub search query
+func (c *GitHubIssuesClient) SearchIssues(repo, query string) ([]TrackerIssue, error) {
+	q := url.QueryEscape(fmt.Sprintf("repo:%s is:issue %s", repo, query))
+
+	// The search API returns at most 1000 results across its pages
+	var issues []TrackerIssue
+	next := "/search/issues?per_page=100&q=" + q
+	for next != "" {
+		var result struct {
+			Items []githubIssue `json:"items"`
+		}
+
+		var err error
+		next, err = c.do("GET", next, nil, &result)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range result.Items {
+			issues = append(issues, *item.toTrackerIssue(repo))
+		}
+	}
+	return issues, nil
+}
+
+// CreateComment adds a comment to an issue identified by "owner/repo#number"
+func (c *GitHubIssuesClient) CreateComment(issueID, body string) error {
+	repo, number, err := parseGitHubIssueID(issueID)
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]string{"body": htmlCollapsibles(body)}
+	_, err = c.do("POST", fmt.Sprintf("/repos/%s/issues/%d/comments", repo, number), payload, nil)
+	return err
+}
+
+// toTrackerIssue converts a GitHub issue to the tracker-neutral form
+func (i *githubIssue) toTrackerIssue(repo string) *TrackerIssue {
+	issue := &TrackerIssue{
+		ID:         githubIssueID(repo, i.Number),
+		Identifier: githubIssueID(repo, i.Number),
+		Title:      i.Title,
+		State:      i.State,
+		URL:        i.HTMLURL,
+	}
+	for _, label := range i.Labels {
+		issue.Labels = append(issue.Labels, label.Name)
+		for priority, name := range githubPriorityLabels {
+			if label.Name == name {
+				issue.Priority = priority
+			}
+		}
+	}
+	return issue
+}
+
+// githubIssueID builds the "owner/repo#number" ID used for GitHub issues
+func githubIssueID(repo string, number int) string {
+	return fmt.Sprintf("%s#%d", repo, number)
+}
+
+// parseGitHubIssueID splits an "owner/repo#number" issue ID
+func parseGitHubIssueID(issueID string) (string, int, error) {
+	idx := strings.LastIndex(issueID, "#")
+	if idx <= 0 {
+		return "", 0, fmt.Errorf("invalid GitHub issue ID '%s'", issueID)
+	}
+	number, err := strconv.Atoi(issueID[idx+1:])
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid GitHub issue ID '%s'", issueID)
+	}
+	return issueID[:idx], number, nil
+}
+
+// GitHubTokenInfo describes the user a token authenticates as and what it may do
+type GitHubTokenInfo struct {
+	Login     string
+	Name      string
+	Scopes    []string   // Nil when the token does not expose scopes (fine-grained tokens)
+	ExpiresAt *time.Time // Nil when the token does not expire or the expiry is not exposed
+}
+
+// GetTokenInfo fetches the authenticated user with the token's scopes and expiry
+func (c *GitHubIssuesClient) GetTokenInfo() (*GitHubTokenInfo, error) {
+	var user struct {
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	}
+	header, err := c.doHeader("GET", "/user", nil, &user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch authenticated user: %w", err)
+	}
+
+	info := &GitHubTokenInfo{Login: user.Login, Name: user.Name}
+	if values, ok := header["X-Oauth-Scopes"]; ok {
+		info.Scopes = []string{}
+		for _, scope := range strings.Split(strings.Join(values, ","), ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				info.Scopes = append(info.Scopes, scope)
+			}
+		}
+	}
+	if expiry := header.Get("Github-Authentication-Token-Expiration"); expiry != "" {
+		if t, err := time.Parse("2006-01-02 15:04:05 MST", expiry); err == nil {
+			info.ExpiresAt = &t
+		}
+	}
+	return info, nil
+}
+
+// GitHubCommit is a commit with its author's GitHub account, if linked
+type GitHubCommit struct {
+	SHA     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Author  *struct {
+		Login string `json:"login"`
+	} `json:"author"` // Nil when the commit email is not linked to an account
+	Commit struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		} `json:"author"`
+	} `json:"commit"`
+}
+
+// GitHubPullRequest is a pull request containing a commit
+type GitHubPullRequest struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	HTMLURL string `json:"html_url"`
+	User    struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	MergedAt *time.Time `json:"merged_at"`
+}
+
+// GetCommit fetches a commit of a repository
+func (c *GitHubIssuesClient) GetCommit(repo, sha string) (*GitHubCommit, error) {
+	var commit GitHubCommit
+	if _, err := c.do("GET", fmt.Sprintf("/repos/%s/commits/%s", repo, sha), nil, &commit); err != nil {
+		return nil, fmt.Errorf("failed to fetch commit %s: %w", shortSHA(sha), err)
+	}
+	return &commit, nil
+}
+
+// GetCommitPullRequests lists the pull requests a commit belongs to
+func (c *GitHubIssuesClient) GetCommitPullRequests(repo, sha string) ([]GitHubPullRequest, error) {
+	var pulls []GitHubPullRequest
+	if _, err := c.do("GET", fmt.Sprintf("/repos/%s/commits/%s/pulls", repo, sha), nil, &pulls); err != nil {
+		return nil, fmt.Errorf("failed to fetch pull requests of %s: %w", shortSHA(sha), err)
+	}
+	return pulls, nil
+}
@@ -0,0 +1,460 @@
+package bugmanager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kkz6/devtools/internal/config"
+	"github.com/kkz6/devtools/internal/ui"
+)
+
+// requiredScope is a token scope a feature depends on
+type requiredScope struct {
+	Scope   string
+	Purpose string
+}
+
+// sentryRequiredScopes are the Sentry scopes the issue manager uses
+var sentryRequiredScopes = []requiredScope{
+	{"project:read", "list projects"},
+	{"event:read", "list issues and events"},
+	{"event:write", "resolve, ignore, assign, bookmark, note and merge issues"},
+	{"org:read", "list members and teams, analytics reports"},
+}
+
+// githubRequiredScopes are the classic token scopes used across devtools
+var githubRequiredScopes = []requiredScope{
+	{"repo", "GitHub Issues tracker, workflow runs and deployments"},
+	{"admin:ssh_signing_key", "manage SSH signing keys"},
+	{"admin:gpg_key", "manage GPG keys"},
+}
+
+// scopeLevels ranks access levels; a higher level implies the lower ones
+var scopeLevels = map[string]int{"read": 1, "write": 2, "admin": 3}
+
+// healthResult is the outcome of checking one instance or token
+type healthResult struct {
+	Service       string // "Sentry", "Linear" or "GitHub"
+	Name          string
+	Latency       time.Duration
+	Identity      string
+	Scopes        []string
+	ScopesHidden  bool // The service does not expose the token's scopes
+	ScopeNote     string
+	Expires       string
+	MissingScopes []requiredScope
+	Stale         []staleMapping
+	Err           error
+}
+
+// staleMapping is a project mapping whose target no longer exists or cannot be reached
+type staleMapping struct {
+	Connection string
+	Mapping    string
+	Problem    string
+}
+
+// connectionHealth checks every instance and token in parallel and shows the dashboard
+func (m *Module) connectionHealth(cfg *config.Config) error {
+	ui.ShowInfo("Checking connections...")
+	results := m.checkConnectionHealth(cfg)
+	if len(results) == 0 {
+		ui.ShowWarning("No instances or GitHub token configured.")
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Print(renderHealthReport(results))
+
+	if problems := countHealthProblems(results); problems > 0 {
+		ui.ShowWarning(fmt.Sprintf("%d problems found", problems))
+	} else {
+		ui.ShowSuccess("All connections are healthy")
+	}
+
+	fmt.Println("\nPress Enter to continue...")
+	fmt.Scanln()
+	return nil
+}
+
+// checkConnectionHealth checks every Sentry and Linear instance and the GitHub token concurrently.
+// Each check also verifies the mappings of the connections that use it.
+func (m *Module) checkConnectionHealth(cfg *config.Config) []*healthResult {
+	var checks []func() *healthResult
+	for key, instance := range cfg.Sentry.Instances {
+		key, instance := key, instance
+		checks = append(checks, func() *healthResult { return checkSentryHealth(cfg, key, instance) })
+	}
+	for key, instance := range cfg.Linear.Instances {
+		key, instance := key, instance
+		checks = append(checks, func() *healthResult { return checkLinearHealth(cfg, key, instance) })
+	}
+	if cfg.GitHub.Token != "" {
+		checks = append(checks, func() *healthResult { return checkGitHubHealth(cfg) })
+	}
+
+	results := make([]*healthResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check func() *healthResult) {
+			defer wg.Done()
+			results[i] = check()
+		}(i, check)
+	}
+	wg.Wait()
+
+	// Connections pointing at instances that are not configured at all
+	missing := &healthResult{Service: "Config", Name: "connections"}
+	for _, conn := range cfg.BugManager.Connections {
+		if _, ok := cfg.Sentry.Instances[conn.SentryInstance]; !ok {
+			missing.Stale = append(missing.Stale, staleMapping{conn.Name, "*",
+				fmt.Sprintf("Sentry instance '%s' is not configured", conn.SentryInstance)})
+		}
+		if conn.Tracker == TrackerGitHub {
+			if cfg.GitHub.Token == "" {
+				missing.Stale = append(missing.Stale, staleMapping{conn.Name, "*", "GitHub token is not configured"})
+			}
+		} else if _, ok := cfg.Linear.Instances[conn.LinearInstance]; !ok {
+			missing.Stale = append(missing.Stale, staleMapping{conn.Name, "*",
+				fmt.Sprintf("Linear instance '%s' is not configured", conn.LinearInstance)})
+		}
+	}
+	if len(missing.Stale) > 0 {
+		results = append(results, missing)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Service != results[j].Service {
+			return results[i].Service > results[j].Service
+		}
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+// checkSentryHealth checks a Sentry token and the Sentry side of every mapping using the instance
+func checkSentryHealth(cfg *config.Config, key string, instance *config.SentryInstance) *healthResult {
+	result := &healthResult{Service: "Sentry", Name: instance.Name, Expires: "-"}
+	client := NewSentryClient(instance.APIKey, instance.BaseURL)
+
+	start := time.Now()
+	info, err := client.GetAuthInfo()
+	result.Latency = time.Since(start)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.Identity = "organization token"
+	if info.User != nil {
+		result.Identity = info.User.Email
+		if result.Identity == "" {
+			result.Identity = info.User.Username
+		}
+	}
+	result.Scopes = info.Scopes
+	result.MissingScopes = missingScopes(info.Scopes, sentryRequiredScopes)
+
+	projects, err := client.GetProjects()
+	if err != nil {
+		result.Err = fmt.Errorf("failed to list projects: %w", err)
+		return result
+	}
+	known := make(map[string]bool)
+	for _, project := range projects {
+		known[project.OrganizationSlug+"/"+project.Slug] = true
+	}
+
+	for _, conn := range cfg.BugManager.Connections {
+		if conn.SentryInstance != key {
+			continue
+		}
+		for _, mapping := range conn.ProjectMappings {
+			name := mapping.SentryOrganization + "/" + mapping.SentryProject
+			if !known[name] {
+				result.Stale = append(result.Stale, staleMapping{conn.Name, name,
+					"Sentry project not found or not accessible with this token"})
+			}
+		}
+	}
+	return result
+}
+
+// checkLinearHealth checks a Linear API key and the teams and projects mapped to the instance
+func checkLinearHealth(cfg *config.Config, key string, instance *config.LinearInstance) *healthResult {
+	result := &healthResult{Service: "Linear", Name: instance.Name, Expires: "-"}
+	client := NewLinearClient(instance.APIKey)
+
+	start := time.Now()
+	viewer, err := client.GetViewer()
+	result.Latency = time.Since(start)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.Identity = viewer.Email
+	if viewer.Organization != "" {
+		result.Identity = fmt.Sprintf("%s (%s)", viewer.Email, viewer.Organization)
+	}
+	result.ScopesHidden = true
+	if strings.HasPrefix(instance.APIKey, "lin_api_") {
+		result.ScopeNote = "personal API key (full access)"
+	} else {
+		result.ScopeNote = "OAuth token (scopes not exposed)"
+	}
+
+	var conns []config.BugManagerConnection
+	for _, conn := range cfg.BugManager.Connections {
+		if (conn.Tracker == "" || conn.Tracker == TrackerLinear) && conn.LinearInstance == key {
+			conns = append(conns, conn)
+		}
+	}
+	if len(conns) == 0 {
+		return result
+	}
+
+	teams, err := client.GetTeams()
+	if err != nil {
+		result.Err = fmt.Errorf("failed to list teams: %w", err)
+		return result
+	}
+	teamNames := make(map[string]string)
+	for _, team := range teams {
+		teamNames[team.ID] = team.Name
+	}
+
+	projectCache := make(map[string]map[string]bool)
+	for _, conn := range conns {
+		for _, mapping := range conn.ProjectMappings {
+			name := mappingTarget(mapping)
+			teamName, ok := teamNames[mapping.TeamID()]
+			if !ok {
+				result.Stale = append(result.Stale, staleMapping{conn.Name, name,
+					fmt.Sprintf("Linear team %s no longer exists", mapping.TeamID())})
+				continue
+			}
+			if mapping.ProjectID() == "" {
+				continue
+			}
+
+			projects, ok := projectCache[mapping.TeamID()]
+			if !ok {
+				list, err := client.GetProjects(mapping.TeamID())
+				if err != nil {
+					result.Stale = append(result.Stale, staleMapping{conn.Name, name,
+						fmt.Sprintf("could not list projects of %s: %v", teamName, err)})
+					continue
+				}
+				projects = make(map[string]bool)
+				for _, project := range list {
+					projects[project.ID] = true
+				}
+				projectCache[mapping.TeamID()] = projects
+			}
+			if !projects[mapping.ProjectID()] {
+				result.Stale = append(result.Stale, staleMapping{conn.Name, name,
+					fmt.Sprintf("Linear project '%s' no longer exists in %s", mapping.ProjectName(), teamName)})
+			}
+		}
+	}
+	return result
+}
+
+// checkGitHubHealth checks the GitHub token and the repositories and milestones of GitHub Issues connections
+func checkGitHubHealth(cfg *config.Config) *healthResult {
+	result := &healthResult{Service: "GitHub", Name: "token", Expires: "never"}
+	client := NewGitHubIssuesClient(cfg.GitHub.Token)
+
+	start := time.Now()
+	info, err := client.GetTokenInfo()
+	result.Latency = time.Since(start)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.Identity = info.Login
+	if info.Scopes == nil {
+		result.ScopesHidden = true
+		result.ScopeNote = "fine-grained token (scopes not exposed)"
+	} else {
+		result.Scopes = info.Scopes
+		result.MissingScopes = missingScopes(info.Scopes, githubRequiredScopes)
+	}
+	if info.ExpiresAt != nil {
+		result.Expires = formatExpiry(*info.ExpiresAt)
+	}
+
+	for _, conn := range cfg.BugManager.Connections {
+		if conn.Tracker != TrackerGitHub {
+			continue
+		}
+		for _, mapping := range conn.ProjectMappings {
+			milestones, err := client.GetProjects(mapping.TeamID())
+			if err != nil {
+				problem := fmt.Sprintf("repository %s not accessible: %v", mapping.TeamID(), err)
+				if apiErr, ok := err.(*githubAPIError); ok && apiErr.StatusCode == 404 {
+					problem = fmt.Sprintf("repository %s no longer exists or is not accessible", mapping.TeamID())
+				}
+				result.Stale = append(result.Stale, staleMapping{conn.Name, mappingTarget(mapping), problem})
+				continue
+			}
+			if mapping.ProjectID() == "" {
+				continue
+			}
+			found := false
+			for _, milestone := range milestones {
+				found = found || milestone.ID == mapping.ProjectID()
+			}
+			if !found {
+				result.Stale = append(result.Stale, staleMapping{conn.Name, mappingTarget(mapping),
+					fmt.Sprintf("milestone '%s' is closed or no longer exists", mapping.ProjectName())})
+			}
+		}
+	}
+	return result
+}
+
+// mappingTarget describes a mapping as "org/project → team"
+func mappingTarget(mapping config.BugManagerProjectMapping) string {
+	target := mapping.TeamID()
+	if mapping.ProjectName() != "" {
+		target += " / " + mapping.ProjectName()
+	}
+	return fmt.Sprintf("%s/%s → %s", mapping.SentryOrganization, mapping.SentryProject, target)
+}
+
+// missingScopes returns the required scopes the granted scopes do not cover
+func missingScopes(granted []string, required []requiredScope) []requiredScope {
+	var missing []requiredScope
+	for _, req := range required {
+		covered := false
+		for _, scope := range granted {
+			covered = covered || scopeCovers(scope, req.Scope)
+		}
+		if !covered {
+			missing = append(missing, req)
+		}
+	}
+	return missing
+}
+
+// scopeCovers reports whether a granted scope includes the required one. Both Sentry's
+// "resource:level" and GitHub's "level:resource" forms are understood, with
+// admin implying write and write implying read.
+func scopeCovers(granted, required string) bool {
+	if granted == required {
+		return true
+	}
+	grantedResource, grantedLevel := splitScope(granted)
+	requiredResource, requiredLevel := splitScope(required)
+	if grantedLevel == 0 || requiredLevel == 0 || grantedResource != requiredResource {
+		return false
+	}
+	return grantedLevel >= requiredLevel
+}
+
+// splitScope splits a scope into its resource and access level, or a zero level when it has none
+func splitScope(scope string) (string, int) {
+	parts := strings.SplitN(scope, ":", 2)
+	if len(parts) != 2 {
+		return scope, 0
+	}
+	if level, ok := scopeLevels[parts[1]]; ok {
+		return parts[0], level
+	}
+	if level, ok := scopeLevels[parts[0]]; ok {
+		return parts[1], level
+	}
+	return scope, 0
+}
+
+// formatExpiry formats a token expiry with the time left
+func formatExpiry(t time.Time) string {
+	left := time.Until(t)
+	switch {
+	case left <= 0:
+		return t.Format("2006-01-02") + " (expired)"
+	case left < 24*time.Hour:
+		return t.Format("2006-01-02") + " (today)"
+	default:
+		return fmt.Sprintf("%s (%dd)", t.Format("2006-01-02"), int(left.Hours()/24))
+	}
+}
+
+// countHealthProblems counts failed checks, missing scopes, expired tokens and stale mappings
+func countHealthProblems(results []*healthResult) int {
+	problems := 0
+	for _, result := range results {
+		if result.Err != nil {
+			problems++
+		}
+		if strings.HasSuffix(result.Expires, "(expired)") {
+			problems++
+		}
+		problems += len(result.MissingScopes) + len(result.Stale)
+	}
+	return problems
+}
+
+// renderHealthReport renders the status table followed by scope warnings and stale mappings
+func renderHealthReport(results []*healthResult) string {
+	var b strings.Builder
+
+	table := ui.NewTable("Connection Health")
+	table.AddHeader("Service", "Name", "Status", "Latency", "Identity", "Scopes", "Expires")
+	for _, result := range results {
+		if result.Service == "Config" {
+			continue
+		}
+		status := "✓ ok"
+		switch {
+		case result.Err != nil:
+			status = "✗ error"
+		case len(result.MissingScopes) > 0 || len(result.Stale) > 0:
+			status = "! warnings"
+		}
+
+		scopes := result.ScopeNote
+		if !result.ScopesHidden && result.Err == nil {
+			scopes = strings.Join(result.Scopes, ", ")
+		}
+		table.AddRow(result.Service, result.Name, status,
+			result.Latency.Round(time.Millisecond).String(), result.Identity, scopes, result.Expires)
+	}
+	b.WriteString(table.Render())
+
+	var errors, scopes []string
+	var stale []staleMapping
+	for _, result := range results {
+		if result.Err != nil {
+			errors = append(errors, fmt.Sprintf("  %s %s: %v", result.Service, result.Name, result.Err))
+		}
+		for _, scope := range result.MissingScopes {
+			scopes = append(scopes, fmt.Sprintf("  %s %s: missing %s (needed to %s)",
+				result.Service, result.Name, scope.Scope, scope.Purpose))
+		}
+		stale = append(stale, result.Stale...)
+	}
+
+	if len(errors) > 0 {
+		b.WriteString("\nErrors:\n" + strings.Join(errors, "\n") + "\n")
+	}
+	if len(scopes) > 0 {
+		b.WriteString("\nMissing scopes:\n" + strings.Join(scopes, "\n") + "\n")
+	}
+	if len(stale) > 0 {
+		staleTable := ui.NewTable("Stale Mappings")
+		staleTable.AddHeader("Connection", "Mapping", "Problem")
+		for _, s := range stale {
+			staleTable.AddRow(s.Connection, s.Mapping, s.Problem)
+		}
+		b.WriteString("\n" + staleTable.Render())
+	}
+	return b.String()
+}
@@ -0,0 +1,161 @@
+package bugmanager
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kkz6/devtools/internal/config"
+	"github.com/kkz6/devtools/internal/ui"
+)
+
+// syncOptions controls an unattended sync of one project mapping
+type syncOptions struct {
+	Incremental bool          // Only issues first seen or regressed since the last completed run
+	Lookback    time.Duration // How far back the first incremental run of a mapping looks
+	Limit       int           // Maximum issues considered per run; the rest is left for the next run
+	DryRun      bool          // Report what would be created without creating anything
+}
+
+// syncRun is the outcome of syncing one project mapping
+type syncRun struct {
+	Items   []*batchItem
+	Since   time.Time // Zero for full syncs
+	Created int
+	Failed  int
+	Left    bool // The limit was reached before every page was read
+}
+
+// sentryTimeFormat is the timestamp format of Sentry search queries
+const sentryTimeFormat = "2006-01-02T15:04:05"
+
+// syncMapping creates tracker issues for the mapping's matching Sentry issues without prompting.
+// Incremental runs resume from the mapping's sync state. The page cursor always advances so
+// that a failing issue cannot hold back later pages, but a completed run only moves the
+// start of the next one forward when every issue on every page synced, so failed issues
+// are retried.
+func (m *Module) syncMapping(cfg *config.Config, conn *config.BugManagerConnection, mapping *config.BugManagerProjectMapping,
+	client *SentryClient, tracker IssueTracker, state *SyncState, opts syncOptions) (*syncRun, error) {
+
+	runStart := time.Now()
+	run := &syncRun{}
+
+	query := m.buildIssueQuery(mapping.IssueQuery)
+	query.StatsPeriod = ""
+
+	var mappingState *MappingSyncState
+	cursorFailed := 0
+	if opts.Incremental {
+		mappingState = state.MappingState(conn, mapping)
+		run.Since = mappingState.LastSyncedAt
+		if run.Since.IsZero() {
+			run.Since = runStart.Add(-opts.Lookback)
+		}
+		if mappingState.Cursor != "" {
+			// Continue the unfinished run; the query must match the one the cursor came from
+			query.Cursor = mappingState.Cursor
+			runStart = mappingState.CursorSince
+			if !mappingState.CursorFrom.IsZero() {
+				run.Since = mappingState.CursorFrom
+			}
+			cursorFailed = mappingState.CursorFailed
+		}
+		query.Query += " lastSeen:>" + run.Since.UTC().Format(sentryTimeFormat)
+		query.Sort = "new"
+	}
+
+	var issues []SentryIssue
+	next := ""
+	for {
+		page, cursor, err := client.GetIssues(mapping.SentryOrganization, mapping.SentryProject, query)
+		if err != nil {
+			if mappingState != nil {
+				mappingState.LastRunAt = time.Now()
+				mappingState.LastError = err.Error()
+			}
+			return nil, err
+		}
+		issues = append(issues, page...)
+		next = cursor
+		if next == "" || (opts.Limit > 0 && len(issues) >= opts.Limit) {
+			break
+		}
+		query.Cursor = next
+	}
+	run.Left = next != ""
+
+	for _, issue := range issues {
+		item := &batchItem{Issue: issue}
+		if opts.Incremental {
+			item.Skipped = incrementalSkipReason(issue, run.Since)
+		}
+		run.Items = append(run.Items, item)
+	}
+	pending := skipSyncedItems(state, conn.SentryInstance, run.Items)
+
+	if opts.DryRun {
+		for _, item := range pending {
+			item.Skipped = "would be created (dry run)"
+		}
+		return run, nil
+	}
+
+	if len(pending) > 0 {
+		tmpl := findTemplate(cfg, mapping.Template)
+		stateID, err := templateStateID(tracker, mapping.TeamID(), tmpl)
+		if err != nil {
+			ui.ShowWarning(err.Error())
+		}
+		m.createBatch(cfg, mapping, client, conn.SentryInstance, newCachedTracker(tracker), tmpl, stateID, state, pending, false)
+	}
+
+	for _, item := range pending {
+		if item.Err != nil {
+			run.Failed++
+		} else {
+			run.Created++
+		}
+	}
+
+	if mappingState != nil {
+		// Failures on earlier pages of a resumed run count against the whole run
+		cursorFailed += run.Failed
+		mappingState.LastRunAt = time.Now()
+		mappingState.LastRunCreated = run.Created
+		mappingState.LastError = ""
+		if run.Left {
+			mappingState.Cursor = next
+			mappingState.CursorSince = runStart
+			mappingState.CursorFrom = run.Since
+			mappingState.CursorFailed = cursorFailed
+			mappingState.LastError = fmt.Sprintf("stopped after %d issues, the next run continues", len(issues))
+		} else {
+			// Without failures the next run starts here; otherwise it reads this range again
+			if cursorFailed == 0 {
+				mappingState.LastSyncedAt = runStart
+			}
+			mappingState.Cursor = ""
+			mappingState.CursorSince = time.Time{}
+			mappingState.CursorFrom = time.Time{}
+			mappingState.CursorFailed = 0
+		}
+		if cursorFailed > 0 {
+			mappingState.LastError = fmt.Sprintf("%d issues failed to sync", cursorFailed)
+		}
+	}
+
+	return run, nil
+}
+
+// incrementalSkipReason explains why an issue seen since the last run is not synced by an
+// incremental run, or returns "" for issues first seen or regressed since then
+func incrementalSkipReason(issue SentryIssue, since time.Time) string {
+	switch {
+	case issue.FirstSeen.After(since):
+		return ""
+	case issue.Substatus == "regressed":
+		return ""
+	default:
+		return fmt.Sprintf("ongoing since %s, not new or regressed since %s",
+			issue.FirstSeen.Local().Format("2006-01-02 15:04"), since.Local().Format("2006-01-02 15:04"))
+	}
+}
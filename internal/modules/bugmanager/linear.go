@@ -48,6 +48,7 @@ type LinearLabel struct {
 // LinearIssue represents a Linear issue
 type LinearIssue struct {
 	ID          string `json:"id"`
+	Identifier  string `json:"identifier"`
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	Priority    int    `json:"priority"`
@@ -58,7 +59,62 @@ type LinearIssue struct {
 	Labels struct {
 		Nodes []LinearLabel `json:"nodes"`
 	} `json:"labels"`
-	URL string `json:"url"`
+	URL       string      `json:"url"`
+	Assignee  *LinearUser `json:"assignee"`
+	UpdatedAt string      `json:"updatedAt"`
+}
+
+// LinearComment represents a comment on a Linear issue
+type LinearComment struct {
+	ID        string `json:"id"`
+	Body      string `json:"body"`
+	CreatedAt string `json:"createdAt"`
+	User      *struct {
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+	} `json:"user"`
+}
+
+// LinearIssueDetail is an issue with its team, project and comments
+type LinearIssueDetail struct {
+	LinearIssue
+	Team    LinearTeam `json:"team"`
+	Project *struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"project"`
+	CreatedAt string `json:"createdAt"`
+	Comments  struct {
+		Nodes []LinearComment `json:"nodes"`
+	} `json:"comments"`
+}
+
+// LinearIssueFilter narrows the issues returned by ListIssues.
+// Empty fields are not filtered on.
+type LinearIssueFilter struct {
+	TeamID     string
+	ProjectID  string
+	StateID    string
+	LabelID    string
+	AssigneeID string // A user ID, or "unassigned"
+	Priority   *int
+	Search     string // Matched against title and description
+}
+
+// LinearUser represents a Linear workspace member
+type LinearUser struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Email       string `json:"email"`
+	Active      bool   `json:"active"`
+	URL         string `json:"url"` // Profile URL; Linear turns it into a mention in markdown
+}
+
+// LinearPageInfo holds cursor pagination details of a connection
+type LinearPageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
 }
 
 // LinearWorkflowState represents a Linear workflow state
@@ -291,22 +347,28 @@ func (c *LinearClient) GetOrCreateLabel(teamID, name, color string) (string, err
 	return createResult.IssueLabelCreate.IssueLabel.ID, nil
 }
 
+// LinearIssueInput holds the fields used to create an issue
+type LinearIssueInput struct {
+	TeamID      string
+	ProjectID   string
+	Title       string
+	Description string
+	LabelIDs    []string
+	Priority    int
+	StateID     string
+	AssigneeID  string
+	ParentID    string
+}
+
 // CreateIssue creates a new issue in Linear
-func (c *LinearClient) CreateIssue(teamID, projectID, title, description string, labelIDs []string, priority int, stateID string) (*LinearIssue, error) {
+func (c *LinearClient) CreateIssue(input LinearIssueInput) (*LinearIssue, error) {
 	query := `
-		mutation CreateIssue($teamId: String!, $projectId: String!, $title: String!, $description: String!, $labelIds: [String!], $priority: Int!, $stateId: String) {
-			issueCreate(input: {
-				teamId: $teamId
-				projectId: $projectId
-				title: $title
-				description: $description
-				labelIds: $labelIds
-				priority: $priority
-				stateId: $stateId
-			}) {
+		mutation CreateIssue($input: IssueCreateInput!) {
+			issueCreate(input: $input) {
 				success
 				issue {
 					id
+					identifier
 					title
 					description
 					priority
@@ -327,18 +389,30 @@ func (c *LinearClient) CreateIssue(teamID, projectID, title, description string,
 		}
 	`
 
-	variables := map[string]interface{}{
-		"teamId":      teamID,
-		"projectId":   projectID,
-		"title":       title,
-		"description": description,
-		"labelIds":    labelIDs,
-		"priority":    priority,
+	issueInput := map[string]interface{}{
+		"teamId":      input.TeamID,
+		"title":       input.Title,
+		"description": input.Description,
+		"labelIds":    input.LabelIDs,
+		"priority":    input.Priority,
 	}
 
-	// Only add stateId if provided
-	if stateID != "" {
-		variables["stateId"] = stateID
+	// Only add optional fields if provided
+	if input.ProjectID != "" {
+		issueInput["projectId"] = input.ProjectID
+	}
+	if input.StateID != "" {
+		issueInput["stateId"] = input.StateID
+	}
+	if input.AssigneeID != "" {
+		issueInput["assigneeId"] = input.AssigneeID
+	}
+	if input.ParentID != "" {
+		issueInput["parentId"] = input.ParentID
+	}
+
+	variables := map[string]interface{}{
+		"input": issueInput,
 	}
 
 	data, err := c.executeGraphQL(query, variables)
@@ -364,6 +438,189 @@ func (c *LinearClient) CreateIssue(teamID, projectID, title, description string,
 	return &result.IssueCreate.Issue, nil
 }
 
+// LinearIssueUpdateInput holds the fields that can be changed on an issue.
+// Nil fields are left untouched.
+type LinearIssueUpdateInput struct {
+	Title       *string
+	Description *string
+	Priority    *int
+	StateID     *string
+	AssigneeID  *string
+	LabelIDs    []string
+}
+
+// UpdateIssue updates an existing issue in Linear
+func (c *LinearClient) UpdateIssue(issueID string, input LinearIssueUpdateInput) (*LinearIssue, error) {
+	query := `
+		mutation UpdateIssue($id: String!, $input: IssueUpdateInput!) {
+			issueUpdate(id: $id, input: $input) {
+				success
+				issue {
+					id
+					identifier
+					title
+					description
+					priority
+					url
+					state {
+						id
+						name
+					}
+					labels {
+						nodes {
+							id
+							name
+							color
+						}
+					}
+				}
+			}
+		}
+	`
+
+	issueInput := map[string]interface{}{}
+	if input.Title != nil {
+		issueInput["title"] = *input.Title
+	}
+	if input.Description != nil {
+		issueInput["description"] = *input.Description
+	}
+	if input.Priority != nil {
+		issueInput["priority"] = *input.Priority
+	}
+	if input.StateID != nil {
+		issueInput["stateId"] = *input.StateID
+	}
+	if input.AssigneeID != nil {
+		// An empty assignee unassigns the issue
+		if *input.AssigneeID == "" {
+			issueInput["assigneeId"] = nil
+		} else {
+			issueInput["assigneeId"] = *input.AssigneeID
+		}
+	}
+	if input.LabelIDs != nil {
+		issueInput["labelIds"] = input.LabelIDs
+	}
+
+	variables := map[string]interface{}{
+		"id":    issueID,
+		"input": issueInput,
+	}
+
+	data, err := c.executeGraphQL(query, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		IssueUpdate struct {
+			Success bool        `json:"success"`
+			Issue   LinearIssue `json:"issue"`
+		} `json:"issueUpdate"`
+	}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal issue: %w", err)
+	}
+
+	if !result.IssueUpdate.Success {
+		return nil, fmt.Errorf("failed to update issue")
+	}
+
+	return &result.IssueUpdate.Issue, nil
+}
+
+// CreateComment adds a comment to an issue
+func (c *LinearClient) CreateComment(issueID, body string) error {
+	query := `
+		mutation CreateComment($issueId: String!, $body: String!) {
+			commentCreate(input: {
+				issueId: $issueId
+				body: $body
+			}) {
+				success
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"issueId": issueID,
+		"body":    body,
+	}
+
+	data, err := c.executeGraphQL(query, variables)
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		CommentCreate struct {
+			Success bool `json:"success"`
+		} `json:"commentCreate"`
+	}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("failed to unmarshal comment result: %w", err)
+	}
+
+	if !result.CommentCreate.Success {
+		return fmt.Errorf("failed to create comment")
+	}
+
+	return nil
+}
+
+// GetUsers fetches all users of the workspace
+func (c *LinearClient) GetUsers() ([]LinearUser, error) {
+	query := `
+		query GetUsers($after: String) {
+			users(first: 100, after: $after) {
+				nodes {
+					id
+					name
+					displayName
+					email
+					active
+					url
+				}
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+			}
+		}
+	`
+
+	var users []LinearUser
+	variables := map[string]interface{}{}
+
+	for {
+		data, err := c.executeGraphQL(query, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Users struct {
+				Nodes    []LinearUser   `json:"nodes"`
+				PageInfo LinearPageInfo `json:"pageInfo"`
+			} `json:"users"`
+		}
+
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal users: %w", err)
+		}
+
+		users = append(users, result.Users.Nodes...)
+
+		if !result.Users.PageInfo.HasNextPage {
+			return users, nil
+		}
+		variables["after"] = result.Users.PageInfo.EndCursor
+	}
+}
+
 // GetWorkflowStates fetches workflow states for a team
 func (c *LinearClient) GetWorkflowStates(teamID string) ([]LinearWorkflowState, error) {
 	query := `
@@ -404,3 +661,314 @@ func (c *LinearClient) GetWorkflowStates(teamID string) ([]LinearWorkflowState,
 
 	return result.Team.States.Nodes, nil
 }
+
+// SearchIssues finds issues of a team whose title contains the query
+func (c *LinearClient) SearchIssues(teamID, term string) ([]LinearIssue, error) {
+	query := `
+		query SearchIssues($teamId: ID!, $term: String!) {
+			issues(
+				first: 50
+				filter: {
+					team: { id: { eq: $teamId } }
+					title: { containsIgnoreCase: $term }
+				}
+				orderBy: updatedAt
+			) {
+				nodes {
+					id
+					identifier
+					title
+					description
+					priority
+					url
+					state {
+						id
+						name
+					}
+					labels {
+						nodes {
+							id
+							name
+							color
+						}
+					}
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"teamId": teamID,
+		"term":   term,
+	}
+
+	data, err := c.executeGraphQL(query, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Issues struct {
+			Nodes []LinearIssue `json:"nodes"`
+		} `json:"issues"`
+	}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal issues: %w", err)
+	}
+
+	return result.Issues.Nodes, nil
+}
+
+// linearIssueListFields are the issue fields requested by list queries
+const linearIssueListFields = `
+	id
+	identifier
+	title
+	priority
+	url
+	updatedAt
+	state {
+		id
+		name
+	}
+	assignee {
+		id
+		name
+		displayName
+		email
+		active
+	}
+	labels {
+		nodes {
+			id
+			name
+			color
+		}
+	}
+`
+
+// ListIssues fetches a page of issues matching the filter, most recently updated first.
+// Pass the returned EndCursor as after to fetch the next page.
+func (c *LinearClient) ListIssues(filter LinearIssueFilter, first int, after string) ([]LinearIssue, LinearPageInfo, error) {
+	query := `
+		query ListIssues($filter: IssueFilter, $first: Int!, $after: String) {
+			issues(filter: $filter, first: $first, after: $after, orderBy: updatedAt) {
+				nodes {` + linearIssueListFields + `}
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+			}
+		}
+	`
+
+	issueFilter := map[string]interface{}{}
+	if filter.TeamID != "" {
+		issueFilter["team"] = map[string]interface{}{"id": map[string]string{"eq": filter.TeamID}}
+	}
+	if filter.ProjectID != "" {
+		issueFilter["project"] = map[string]interface{}{"id": map[string]string{"eq": filter.ProjectID}}
+	}
+	if filter.StateID != "" {
+		issueFilter["state"] = map[string]interface{}{"id": map[string]string{"eq": filter.StateID}}
+	}
+	if filter.LabelID != "" {
+		issueFilter["labels"] = map[string]interface{}{"id": map[string]string{"eq": filter.LabelID}}
+	}
+	if filter.AssigneeID == "unassigned" {
+		issueFilter["assignee"] = map[string]interface{}{"null": true}
+	} else if filter.AssigneeID != "" {
+		issueFilter["assignee"] = map[string]interface{}{"id": map[string]string{"eq": filter.AssigneeID}}
+	}
+	if filter.Priority != nil {
+		issueFilter["priority"] = map[string]int{"eq": *filter.Priority}
+	}
+	if filter.Search != "" {
+		issueFilter["or"] = []map[string]interface{}{
+			{"title": map[string]string{"containsIgnoreCase": filter.Search}},
+			{"description": map[string]string{"containsIgnoreCase": filter.Search}},
+		}
+	}
+
+	variables := map[string]interface{}{
+		"filter": issueFilter,
+		"first":  first,
+	}
+	if after != "" {
+		variables["after"] = after
+	}
+
+	data, err := c.executeGraphQL(query, variables)
+	if err != nil {
+		return nil, LinearPageInfo{}, err
+	}
+
+	var result struct {
+		Issues struct {
+			Nodes    []LinearIssue  `json:"nodes"`
+			PageInfo LinearPageInfo `json:"pageInfo"`
+		} `json:"issues"`
+	}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, LinearPageInfo{}, fmt.Errorf("failed to unmarshal issues: %w", err)
+	}
+
+	return result.Issues.Nodes, result.Issues.PageInfo, nil
+}
+
+// GetIssue fetches a single issue with its description and latest comments
+func (c *LinearClient) GetIssue(issueID string) (*LinearIssueDetail, error) {
+	query := `
+		query GetIssue($id: String!) {
+			issue(id: $id) {` + linearIssueListFields + `
+				description
+				createdAt
+				team {
+					id
+					name
+					key
+				}
+				project {
+					id
+					name
+				}
+				comments(first: 20) {
+					nodes {
+						id
+						body
+						createdAt
+						user {
+							name
+							displayName
+						}
+					}
+				}
+			}
+		}
+	`
+
+	variables := map[string]interface{}{
+		"id": issueID,
+	}
+
+	data, err := c.executeGraphQL(query, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Issue LinearIssueDetail `json:"issue"`
+	}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal issue: %w", err)
+	}
+
+	return &result.Issue, nil
+}
+
+// GetLabels fetches all labels available to a team, including workspace labels
+func (c *LinearClient) GetLabels(teamID string) ([]LinearLabel, error) {
+	query := `
+		query GetLabels($teamId: ID, $after: String) {
+			issueLabels(
+				first: 100
+				after: $after
+				filter: { or: [{ team: { id: { eq: $teamId } } }, { team: { null: true } }] }
+			) {
+				nodes {
+					id
+					name
+					color
+				}
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+			}
+		}
+	`
+
+	var labels []LinearLabel
+	variables := map[string]interface{}{
+		"teamId": teamID,
+	}
+
+	for {
+		data, err := c.executeGraphQL(query, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			IssueLabels struct {
+				Nodes    []LinearLabel  `json:"nodes"`
+				PageInfo LinearPageInfo `json:"pageInfo"`
+			} `json:"issueLabels"`
+		}
+
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal labels: %w", err)
+		}
+
+		labels = append(labels, result.IssueLabels.Nodes...)
+
+		if !result.IssueLabels.PageInfo.HasNextPage {
+			return labels, nil
+		}
+		variables["after"] = result.IssueLabels.PageInfo.EndCursor
+	}
+}
+
+// LinearViewer is the user and organization an API key belongs to
+type LinearViewer struct {
+	ID           string
+	Name         string
+	Email        string
+	Organization string
+}
+
+// GetViewer fetches the user and organization the API key authenticates as
+func (c *LinearClient) GetViewer() (*LinearViewer, error) {
+	query := `
+		query {
+			viewer {
+				id
+				name
+				email
+			}
+			organization {
+				name
+			}
+		}
+	`
+
+	data, err := c.executeGraphQL(query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Viewer struct {
+			ID    string `json:"id"`
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		} `json:"viewer"`
+		Organization struct {
+			Name string `json:"name"`
+		} `json:"organization"`
+	}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal viewer: %w", err)
+	}
+
+	return &LinearViewer{
+		ID:           result.Viewer.ID,
+		Name:         result.Viewer.Name,
+		Email:        result.Viewer.Email,
+		Organization: result.Organization.Name,
+	}, nil
+}
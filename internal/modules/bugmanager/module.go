@@ -4,7 +4,6 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"sort"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -25,8 +24,8 @@ func New() *Module {
 func (m *Module) Info() types.ModuleInfo {
 	return types.ModuleInfo{
 		ID:          "bugmanager",
-		Name:        "Issue Manager (Sentry/Linear)",
-		Description: "Sync bugs from Sentry to Linear & Create issues manually",
+		Name:        "Issue Manager (Sentry/Linear/GitHub)",
+		Description: "Sync bugs from Sentry to Linear or GitHub Issues & Create issues manually",
 	}
 }
 
@@ -41,7 +40,11 @@ func (m *Module) showMainMenu(cfg *config.Config) error {
 	for {
 		options := []string{
 			"Sync Bugs from Sentry",
+			"Import Crash Files",
 			"Create Manual Issue",
+			"Triage Linear Issues",
+			"Sentry Analytics Report",
+			"Connection Health",
 			"Manage Instances",
 			"Manage Connections",
 			"Back",
@@ -57,83 +60,95 @@ func (m *Module) showMainMenu(cfg *config.Config) error {
 			if err := m.syncBugs(cfg); err != nil && err != types.ErrNavigateBack {
 				return err
 			}
-		case 1: // Create manual issue
+		case 1: // Import crash files
+			if err := m.importCrashFiles(cfg); err != nil && err != types.ErrNavigateBack {
+				return err
+			}
+		case 2: // Create manual issue
 			if err := m.createManualIssue(cfg); err != nil && err != types.ErrNavigateBack {
 				return err
 			}
-		case 2: // Manage instances
+		case 3: // Triage issues
+			if err := m.triageIssues(cfg); err != nil && err != types.ErrNavigateBack {
+				return err
+			}
+		case 4: // Analytics report
+			if err := m.sentryAnalytics(cfg); err != nil && err != types.ErrNavigateBack {
+				return err
+			}
+		case 5: // Connection health
+			if err := m.connectionHealth(cfg); err != nil && err != types.ErrNavigateBack {
+				return err
+			}
+		case 6: // Manage instances
 			if err := m.manageInstances(cfg); err != nil && err != types.ErrNavigateBack {
 				return err
 			}
-		case 3: // Manage connections
+		case 7: // Manage connections
 			if err := m.manageConnections(cfg); err != nil && err != types.ErrNavigateBack {
 				return err
 			}
-		case 4: // Back
+		case 8: // Back
 			return types.ErrNavigateBack
 		}
 	}
 }
 
-// createManualIssue handles manual issue creation in Linear
+// createManualIssue handles manual issue creation in Linear or GitHub Issues
 func (m *Module) createManualIssue(cfg *config.Config) error {
-	// Check if we have any Linear instances
-	if len(cfg.Linear.Instances) == 0 {
-		ui.ShowError("No Linear instances configured. Please add a Linear instance first.")
-		return nil
+	// Select the tracker to create the issue in
+	trackerKind, linearKey, err := m.selectTracker(cfg, "Select issue tracker")
+	if err != nil {
+		return err
 	}
 
-	// Select Linear instance
-	var selectedInstance *config.LinearInstance
-	var selectedInstanceKey string
-
-	if len(cfg.Linear.Instances) == 1 {
-		// If only one instance, use it automatically
-		for key, instance := range cfg.Linear.Instances {
-			selectedInstance = instance
-			selectedInstanceKey = key
-			break
-		}
-	} else {
-		// Multiple instances, let user choose
-		instanceOptions := make([]string, 0, len(cfg.Linear.Instances))
-		instanceKeys := make([]string, 0, len(cfg.Linear.Instances))
-
-		for key := range cfg.Linear.Instances {
-			instanceKeys = append(instanceKeys, key)
-		}
-		sort.Strings(instanceKeys)
+	tracker, err := m.newTracker(cfg, &config.BugManagerConnection{Tracker: trackerKind, LinearInstance: linearKey})
+	if err != nil {
+		ui.ShowError(err.Error())
+		return nil
+	}
+	teamTerm, projectTerm := trackerTerms(tracker)
 
-		for _, key := range instanceKeys {
-			instance := cfg.Linear.Instances[key]
-			instanceOptions = append(instanceOptions, fmt.Sprintf("%s (%s)", instance.Name, key))
-		}
+	// Start from a configured template, if any
+	tmpl, err := m.selectTemplate(cfg, "Select issue template")
+	if err != nil {
+		return err
+	}
 
-		choice, err := ui.SelectFromList("Select Linear instance", instanceOptions)
-		if err != nil {
-			if err.Error() == "cancelled" {
-				return types.ErrNavigateBack
-			}
-			return err
+	// Choose how to write the issue
+	modes := []string{
+		"Guided (step by step)",
+		"Write in Editor (markdown with front matter)",
+		"From Markdown File",
+		"Bulk Import (CSV/YAML)",
+	}
+	mode, err := ui.SelectFromList("How do you want to create the issue?", modes)
+	if err != nil {
+		if err.Error() == "cancelled" {
+			return types.ErrNavigateBack
 		}
-
-		selectedInstanceKey = instanceKeys[choice]
-		selectedInstance = cfg.Linear.Instances[selectedInstanceKey]
+		return err
 	}
 
-	// Initialize Linear client
-	linearClient := NewLinearClient(selectedInstance.APIKey)
+	switch mode {
+	case 1: // Editor
+		return m.createIssueFromEditor(cfg, tracker, tmpl)
+	case 2: // Markdown file
+		return m.createIssueFromFile(cfg, tracker, tmpl)
+	case 3: // Bulk import
+		return m.bulkImportIssues(cfg, tracker, tmpl)
+	}
 
 	// Fetch teams
-	ui.ShowInfo("Fetching Linear teams...")
-	teams, err := linearClient.GetTeams()
+	ui.ShowInfo(fmt.Sprintf("Fetching %s %ss...", tracker.Name(), teamTerm))
+	teams, err := tracker.GetTeams()
 	if err != nil {
-		ui.ShowError(fmt.Sprintf("Failed to fetch teams: %v", err))
+		ui.ShowError(fmt.Sprintf("Failed to fetch %ss: %v", teamTerm, err))
 		return nil
 	}
 
 	if len(teams) == 0 {
-		ui.ShowWarning("No teams found in Linear.")
+		ui.ShowWarning(fmt.Sprintf("No %ss found in %s.", teamTerm, tracker.Name()))
 		return nil
 	}
 
@@ -143,7 +158,7 @@ func (m *Module) createManualIssue(cfg *config.Config) error {
 		teamOptions[i] = fmt.Sprintf("%s (%s)", team.Name, team.Key)
 	}
 
-	teamChoice, err := ui.SelectFromList("Select team", teamOptions)
+	teamChoice, err := ui.SelectFromList(fmt.Sprintf("Select %s", teamTerm), teamOptions)
 	if err != nil {
 		if err.Error() == "cancelled" {
 			return types.ErrNavigateBack
@@ -154,22 +169,22 @@ func (m *Module) createManualIssue(cfg *config.Config) error {
 	selectedTeam := teams[teamChoice]
 
 	// Fetch projects for the team
-	ui.ShowInfo("Fetching projects...")
-	projects, err := linearClient.GetProjects(selectedTeam.ID)
+	ui.ShowInfo(fmt.Sprintf("Fetching %ss...", projectTerm))
+	projects, err := tracker.GetProjects(selectedTeam.ID)
 	if err != nil {
-		ui.ShowError(fmt.Sprintf("Failed to fetch projects: %v", err))
+		ui.ShowError(fmt.Sprintf("Failed to fetch %ss: %v", projectTerm, err))
 		return nil
 	}
 
 	// Select project (optional)
 	var selectedProjectID string
 	if len(projects) > 0 {
-		projectOptions := []string{"No Project (Team only)"}
+		projectOptions := []string{fmt.Sprintf("No %s", capitalize(projectTerm))}
 		for _, proj := range projects {
 			projectOptions = append(projectOptions, proj.Name)
 		}
 
-		projectChoice, err := ui.SelectFromList("Select project (optional)", projectOptions)
+		projectChoice, err := ui.SelectFromList(fmt.Sprintf("Select %s (optional)", projectTerm), projectOptions)
 		if err != nil {
 			if err.Error() == "cancelled" {
 				return types.ErrNavigateBack
@@ -191,15 +206,20 @@ func (m *Module) createManualIssue(cfg *config.Config) error {
 	fmt.Println(titleStyle.Render("Create New Issue"))
 
 	// Issue type
-	issueTypes := []string{"Bug", "Feature", "Task", "Improvement", "Story"}
-	typeChoice, err := ui.SelectFromList("Select issue type", issueTypes)
-	if err != nil {
-		if err.Error() == "cancelled" {
-			return types.ErrNavigateBack
+	var issueType string
+	if tmpl != nil && tmpl.Type != "" {
+		issueType = tmpl.Type
+	} else {
+		issueTypes := []string{"Bug", "Feature", "Task", "Improvement", "Story"}
+		typeChoice, err := ui.SelectFromList("Select issue type", issueTypes)
+		if err != nil {
+			if err.Error() == "cancelled" {
+				return types.ErrNavigateBack
+			}
+			return err
 		}
-		return err
+		issueType = issueTypes[typeChoice]
 	}
-	issueType := issueTypes[typeChoice]
 
 	// Title
 	title, err := ui.GetInput(
@@ -220,10 +240,8 @@ func (m *Module) createManualIssue(cfg *config.Config) error {
 		return err
 	}
 
-	// Add issue type prefix if not already present
-	if !strings.HasPrefix(strings.ToLower(title), strings.ToLower(issueType)) {
-		title = fmt.Sprintf("[%s] %s", issueType, title)
-	}
+	// Add the template or issue type prefix if not already present
+	title = templateTitle(title, issueType, tmpl)
 
 	// Description
 	descStyle := lipgloss.NewStyle().
@@ -232,38 +250,34 @@ func (m *Module) createManualIssue(cfg *config.Config) error {
 
 	fmt.Println()
 	fmt.Println(descStyle.Render("Issue Description"))
-	fmt.Println("Enter description line by line. Press Enter on empty line twice to finish.")
-	fmt.Println()
 
-	var descLines []string
-	emptyCount := 0
-	scanner := bufio.NewScanner(os.Stdin)
+	var skeleton string
+	if tmpl != nil {
+		skeleton = strings.TrimSpace(tmpl.Description)
+	}
 
-	for {
-		fmt.Printf("▸ ")
-		if scanner.Scan() {
-			line := scanner.Text()
-			if line == "" {
-				emptyCount++
-				if emptyCount >= 2 {
-					// Remove the last empty line since it was just to signal end
-					if len(descLines) > 0 && descLines[len(descLines)-1] == "" {
-						descLines = descLines[:len(descLines)-1]
-					}
-					break
-				}
-				// Add empty line to description
-				descLines = append(descLines, "")
-			} else {
-				emptyCount = 0
-				descLines = append(descLines, line)
-			}
-		} else {
-			// Handle scanner error or EOF
-			break
+	var description string
+	if editorCommand() != nil {
+		// Write longer descriptions in the user's editor
+		fmt.Println("Opening your editor. Save and close it to continue.")
+		description, err = editInEditor(skeleton, "issue-description-*.md")
+		if err != nil {
+			ui.ShowError(err.Error())
+			return nil
+		}
+		description = strings.TrimSpace(description)
+	} else {
+		fmt.Println("Enter description line by line. Press Enter on empty line twice to finish.")
+		fmt.Println("Tip: set $EDITOR to write the description in your editor instead.")
+		if skeleton != "" {
+			fmt.Println("Leave it empty to use the template's description.")
+		}
+		fmt.Println()
+		description = readDescriptionLines()
+		if description == "" {
+			description = skeleton
 		}
 	}
-	description := strings.Join(descLines, "\n")
 
 	// Priority
 	fmt.Println()
@@ -274,16 +288,28 @@ func (m *Module) createManualIssue(cfg *config.Config) error {
 		"Medium",
 		"Low",
 	}
-	priorityChoice, err := ui.SelectFromList("Select priority", priorities)
-	if err != nil {
-		if err.Error() == "cancelled" {
-			return types.ErrNavigateBack
+	var priorityChoice int
+	if tmpl != nil && tmpl.Priority != "" {
+		priorityChoice, err = parsePriority(tmpl.Priority)
+		if err != nil {
+			ui.ShowError(fmt.Sprintf("Invalid priority in template '%s': %v", tmpl.Name, err))
+			return nil
+		}
+	} else {
+		priorityChoice, err = ui.SelectFromList("Select priority", priorities)
+		if err != nil {
+			if err.Error() == "cancelled" {
+				return types.ErrNavigateBack
+			}
+			return err
 		}
-		return err
 	}
 
 	// Labels
 	fmt.Println()
+	if tmpl != nil && len(tmpl.Labels) > 0 {
+		fmt.Printf("Template labels: %s\n", strings.Join(tmpl.Labels, ", "))
+	}
 	labelsInput, err := ui.GetInput(
 		"Labels (comma-separated, press Enter to skip)",
 		strings.ToLower(issueType),
@@ -307,18 +333,26 @@ func (m *Module) createManualIssue(cfg *config.Config) error {
 			}
 		}
 	}
+	if tmpl != nil {
+		labels = mergeLabels(labels, tmpl.Labels)
+	}
 
 	// Fetch workflow states
 	ui.ShowInfo("Fetching workflow states...")
-	states, err := linearClient.GetWorkflowStates(selectedTeam.ID)
+	states, err := tracker.GetWorkflowStates(selectedTeam.ID)
 	if err != nil {
 		ui.ShowWarning(fmt.Sprintf("Could not fetch workflow states: %v", err))
-		states = []LinearWorkflowState{}
+		states = []TrackerState{}
 	}
 
-	// Select state
+	// Use the template's state, or select one
 	var selectedStateID string
-	if len(states) > 0 {
+	if tmpl != nil && tmpl.State != "" {
+		if selectedStateID = findStateID(states, tmpl.State); selectedStateID == "" {
+			ui.ShowWarning(fmt.Sprintf("State '%s' of template '%s' not found.", tmpl.State, tmpl.Name))
+		}
+	}
+	if selectedStateID == "" && len(states) > 0 {
 		stateOptions := make([]string, len(states))
 		for i, state := range states {
 			stateType := ""
@@ -355,106 +389,103 @@ func (m *Module) createManualIssue(cfg *config.Config) error {
 	fmt.Println(lipgloss.NewStyle().Bold(true).Render("Issue Summary:"))
 	fmt.Println(separator)
 
-	fmt.Printf("Linear Instance: %s\n", selectedInstance.Name)
+	fmt.Printf("Tracker: %s\n", tracker.Name())
 	fmt.Printf("Type: %s\n", issueType)
 	fmt.Printf("Title: %s\n", title)
-	fmt.Printf("Team: %s\n", selectedTeam.Name)
+	fmt.Printf("%s: %s\n", capitalize(teamTerm), selectedTeam.Name)
 	if selectedProjectID != "" {
 		for _, p := range projects {
 			if p.ID == selectedProjectID {
-				fmt.Printf("Project: %s\n", p.Name)
+				fmt.Printf("%s: %s\n", capitalize(projectTerm), p.Name)
 				break
 			}
 		}
 	}
 	fmt.Printf("Priority: %s\n", priorities[priorityChoice])
 	fmt.Printf("Labels: %s\n", strings.Join(labels, ", "))
+	if tmpl != nil && len(tmpl.SubIssues) > 0 {
+		fmt.Printf("Sub-issues (%s):\n", tmpl.Name)
+		for _, sub := range tmpl.SubIssues {
+			fmt.Printf("  - %s\n", sub.Title)
+		}
+	}
 	if description != "" {
 		fmt.Println("\nDescription:")
 		fmt.Println(description)
 	}
 	fmt.Println(separator)
 
-	if !ui.GetConfirmation("Create this issue in Linear?") {
+	if !ui.GetConfirmation(fmt.Sprintf("Create this issue in %s?", tracker.Name())) {
 		return types.ErrNavigateBack
 	}
 
 	// Create labels
 	ui.ShowInfo("Creating labels...")
-	var labelIDs []string
-	for _, label := range labels {
-		labelID, err := linearClient.GetOrCreateLabel(selectedTeam.ID, label, m.getLabelColor(label))
-		if err != nil {
-			ui.ShowWarning(fmt.Sprintf("Failed to create label '%s': %v", label, err))
-			continue
-		}
-		labelIDs = append(labelIDs, labelID)
-	}
+	labelIDs := m.ensureLabels(tracker, selectedTeam.ID, labels)
 
 	// Create issue
-	ui.ShowInfo("Creating issue in Linear...")
-	issue, err := linearClient.CreateIssue(
-		selectedTeam.ID,
-		selectedProjectID,
-		title,
-		description,
-		labelIDs,
-		priorityChoice,
-		selectedStateID,
-	)
+	ui.ShowInfo(fmt.Sprintf("Creating issue in %s...", tracker.Name()))
+	input := TrackerIssueInput{
+		TeamID:      selectedTeam.ID,
+		ProjectID:   selectedProjectID,
+		Title:       title,
+		Description: description,
+		LabelIDs:    labelIDs,
+		Priority:    priorityChoice,
+		StateID:     selectedStateID,
+	}
+	issue, err := tracker.CreateIssue(input)
 	if err != nil {
 		ui.ShowError(fmt.Sprintf("Failed to create issue: %v", err))
 		return nil
 	}
 
+	m.reportSubIssues(tracker, issue, input, tmpl)
+
 	ui.ShowSuccess(fmt.Sprintf("Issue created successfully!\nURL: %s", issue.URL))
 	fmt.Println("\nPress Enter to continue...")
 	fmt.Scanln()
 	return nil
 }
 
-// syncBugs handles the bug syncing process
-func (m *Module) syncBugs(cfg *config.Config) error {
-	// Check if we have any connections
-	if len(cfg.BugManager.Connections) == 0 {
-		ui.ShowError("No Sentry-Linear connections configured. Please add a connection first.")
-		return nil
-	}
-
-	// Select connection
-	var selectedConnection *config.BugManagerConnection
-
-	if len(cfg.BugManager.Connections) == 1 {
-		// If only one connection, use it automatically
-		selectedConnection = &cfg.BugManager.Connections[0]
-	} else {
-		// Multiple connections, let user choose
-		connectionOptions := make([]string, len(cfg.BugManager.Connections))
-
-		for i, conn := range cfg.BugManager.Connections {
-			linearName := "Unknown"
-			sentryName := "Unknown"
-
-			if linear, ok := cfg.Linear.Instances[conn.LinearInstance]; ok {
-				linearName = linear.Name
-			}
-			if sentry, ok := cfg.Sentry.Instances[conn.SentryInstance]; ok {
-				sentryName = sentry.Name
-			}
-
-			connectionOptions[i] = fmt.Sprintf("%s: %s → %s (%d mappings)",
-				conn.Name, sentryName, linearName, len(conn.ProjectMappings))
-		}
+// readDescriptionLines reads a description from stdin until two consecutive empty lines
+func readDescriptionLines() string {
+	var descLines []string
+	emptyCount := 0
+	scanner := bufio.NewScanner(os.Stdin)
 
-		choice, err := ui.SelectFromList("Select connection to sync", connectionOptions)
-		if err != nil {
-			if err.Error() == "cancelled" {
-				return types.ErrNavigateBack
+	for {
+		fmt.Printf("▸ ")
+		if scanner.Scan() {
+			line := scanner.Text()
+			if line == "" {
+				emptyCount++
+				if emptyCount >= 2 {
+					// Remove the last empty line since it was just to signal end
+					if len(descLines) > 0 && descLines[len(descLines)-1] == "" {
+						descLines = descLines[:len(descLines)-1]
+					}
+					break
+				}
+				// Add empty line to description
+				descLines = append(descLines, "")
+			} else {
+				emptyCount = 0
+				descLines = append(descLines, line)
 			}
-			return err
+		} else {
+			// Handle scanner error or EOF
+			break
 		}
+	}
+	return strings.Join(descLines, "\n")
+}
 
-		selectedConnection = &cfg.BugManager.Connections[choice]
+// syncBugs handles the bug syncing process
+func (m *Module) syncBugs(cfg *config.Config) error {
+	selectedConnection, err := m.selectConnection(cfg, "Select connection to sync")
+	if err != nil {
+		return err
 	}
 
 	// Check if connection has project mappings
@@ -465,16 +496,18 @@ func (m *Module) syncBugs(cfg *config.Config) error {
 
 	// Get instances
 	sentryInstance := cfg.Sentry.Instances[selectedConnection.SentryInstance]
-	linearInstance := cfg.Linear.Instances[selectedConnection.LinearInstance]
-
-	if sentryInstance == nil || linearInstance == nil {
+	if sentryInstance == nil {
 		ui.ShowError("Invalid instance configuration in connection.")
 		return nil
 	}
 
 	// Initialize clients
 	sentryClient := NewSentryClient(sentryInstance.APIKey, sentryInstance.BaseURL)
-	linearClient := NewLinearClient(linearInstance.APIKey)
+	tracker, err := m.newTracker(cfg, selectedConnection)
+	if err != nil {
+		ui.ShowError(fmt.Sprintf("Invalid issue tracker configuration in connection: %v", err))
+		return nil
+	}
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
@@ -484,36 +517,20 @@ func (m *Module) syncBugs(cfg *config.Config) error {
 	fmt.Println(titleStyle.Render(fmt.Sprintf("Sync Bugs: %s", selectedConnection.Name)))
 
 	// Select project mapping if multiple exist
-	var selectedMapping *config.BugManagerProjectMapping
-
-	if len(selectedConnection.ProjectMappings) == 1 {
-		selectedMapping = &selectedConnection.ProjectMappings[0]
-	} else {
-		mappingOptions := make([]string, len(selectedConnection.ProjectMappings))
-		for i, mapping := range selectedConnection.ProjectMappings {
-			mappingOptions[i] = fmt.Sprintf("%s/%s → %s",
-				mapping.SentryOrganization, mapping.SentryProject, mapping.LinearProjectName)
-		}
-
-		choice, err := ui.SelectFromList("Select project to sync from", mappingOptions)
-		if err != nil {
-			if err.Error() == "cancelled" {
-				return types.ErrNavigateBack
-			}
-			return err
-		}
-
-		selectedMapping = &selectedConnection.ProjectMappings[choice]
+	selectedMapping, err := m.selectProjectMapping(selectedConnection, "Select project to sync from")
+	if err != nil {
+		return err
 	}
 
-	// Fetch unresolved issues from Sentry
-	ui.ShowInfo(fmt.Sprintf("Fetching unresolved issues from %s/%s...",
-		selectedMapping.SentryOrganization, selectedMapping.SentryProject))
+	// Fetch issues from Sentry using the mapping's query
+	query := m.buildIssueQuery(selectedMapping.IssueQuery)
+	ui.ShowInfo(fmt.Sprintf("Fetching issues from %s/%s (%s)...",
+		selectedMapping.SentryOrganization, selectedMapping.SentryProject, query.Query))
 
-	issues, err := sentryClient.GetUnresolvedIssues(
+	issues, nextCursor, err := sentryClient.GetIssues(
 		selectedMapping.SentryOrganization,
 		selectedMapping.SentryProject,
-		20, // Fetch up to 20 issues
+		query,
 	)
 	if err != nil {
 		ui.ShowError(fmt.Sprintf("Failed to fetch issues: %v", err))
@@ -521,29 +538,79 @@ func (m *Module) syncBugs(cfg *config.Config) error {
 	}
 
 	if len(issues) == 0 {
-		ui.ShowSuccess("No unresolved issues found in Sentry!")
+		ui.ShowSuccess("No matching issues found in Sentry!")
 		return nil
 	}
 
-	// Display issues for selection
-	fmt.Println(fmt.Sprintf("\nFound %d unresolved issues:", len(issues)))
-	issueOptions := make([]string, len(issues))
-	for i, issue := range issues {
-		issueOptions[i] = fmt.Sprintf("[%s] %s (Level: %s, Count: %s, Users: %d)",
-			issue.ShortID, issue.Title, issue.Level, issue.Count, issue.UserCount)
+	// Display issues for selection, loading further pages on demand
+	var issueChoice int
+	for {
+		fmt.Println(fmt.Sprintf("\nFound %d matching issues:", len(issues)))
+		issueOptions := make([]string, len(issues))
+		for i, issue := range issues {
+			issueOptions[i] = formatSentryIssueOption(issue)
+		}
+		if nextCursor != "" {
+			issueOptions = append(issueOptions, "Load more issues...")
+		}
+		batchChoice := len(issueOptions)
+		issueOptions = append(issueOptions, fmt.Sprintf("Sync All %d Listed Issues", len(issues)))
+
+		// Select issue to sync or act on
+		issueChoice, err = ui.SelectFromList(fmt.Sprintf("Select issue to sync to %s or triage", tracker.Name()), issueOptions)
+		if err != nil {
+			if err.Error() == "cancelled" {
+				return types.ErrNavigateBack
+			}
+			return err
+		}
+
+		if issueChoice < len(issues) {
+			err := m.sentryIssueActions(sentryClient, selectedMapping.SentryOrganization, tracker.Name(), &issues, issueChoice)
+			if err == errSyncIssue {
+				break
+			}
+			if err != nil && err != types.ErrNavigateBack {
+				return err
+			}
+			if len(issues) == 0 {
+				return nil
+			}
+			continue
+		}
+		if issueChoice == batchChoice {
+			return m.syncBatch(cfg, selectedConnection, selectedMapping, sentryClient, tracker, issues)
+		}
+
+		ui.ShowInfo("Fetching next page...")
+		query.Cursor = nextCursor
+		page, next, err := sentryClient.GetIssues(
+			selectedMapping.SentryOrganization,
+			selectedMapping.SentryProject,
+			query,
+		)
+		if err != nil {
+			ui.ShowError(fmt.Sprintf("Failed to fetch more issues: %v", err))
+			nextCursor = ""
+			continue
+		}
+		issues = append(issues, page...)
+		nextCursor = next
 	}
 
-	// Select issue to sync
-	issueChoice, err := ui.SelectFromList("Select issue to sync to Linear", issueOptions)
+	selectedIssue := issues[issueChoice]
+
+	// Warn if this issue was already synced
+	state, err := LoadSyncState()
 	if err != nil {
-		if err.Error() == "cancelled" {
+		ui.ShowWarning(fmt.Sprintf("Could not load sync state: %v", err))
+	} else if synced := state.Lookup(selectedConnection.SentryInstance, selectedIssue.ID); synced != nil {
+		ui.ShowWarning(fmt.Sprintf("%s was already synced: %s", selectedIssue.ShortID, synced.LinearURL))
+		if !ui.GetConfirmation("Create another issue anyway?") {
 			return types.ErrNavigateBack
 		}
-		return err
 	}
 
-	selectedIssue := issues[issueChoice]
-
 	// Get issue details
 	ui.ShowInfo("Fetching issue details...")
 	issueDetails, err := sentryClient.GetIssueDetails(selectedIssue.ID)
@@ -561,19 +628,55 @@ func (m *Module) syncBugs(cfg *config.Config) error {
 		event = nil
 	}
 
-	// Prepare bug details
-	bugDetails := m.prepareBugDetails(*issueDetails, event)
+	// Prepare bug details and apply the mapping's template
+	tmpl := findTemplate(cfg, selectedMapping.Template)
+	if selectedMapping.Template != "" && tmpl == nil {
+		ui.ShowWarning(fmt.Sprintf("Template '%s' of this mapping is not configured.", selectedMapping.Template))
+	}
+	bugDetails := m.applyTemplateToBug(m.prepareBugDetails(*issueDetails, event), tmpl)
+
+	// Resolve the assignee from path owners / CODEOWNERS
+	var assigneeID string
+	assignee, err := m.resolveAssignee(tracker, selectedMapping, event, state)
+	if err != nil {
+		ui.ShowWarning(fmt.Sprintf("Could not determine assignee: %v", err))
+	}
+
+	// Describe who likely introduced the bug
+	if suspects := m.newSuspectResolver(cfg, selectedMapping, sentryClient); suspects != nil {
+		ui.ShowInfo("Looking up suspect commits...")
+		suspect, err := suspects.find(*issueDetails, event)
+		if err != nil {
+			ui.ShowWarning(fmt.Sprintf("Could not determine suspect commit: %v", err))
+		}
+		bugDetails, assignee = m.applySuspect(tracker, selectedMapping, suspect, bugDetails, assignee)
+	}
+	if assignee != nil {
+		assigneeID = assignee.User.ID
+	}
 
 	// Show bug preview
 	separator := strings.Repeat("─", 60)
 	fmt.Println("\n" + separator)
-	fmt.Println(lipgloss.NewStyle().Bold(true).Render("Bug Details to be Created in Linear:"))
+	fmt.Println(lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("Bug Details to be Created in %s:", tracker.Name())))
 	fmt.Println(separator)
 
 	fmt.Printf("Title: %s\n", bugDetails.Title)
 	fmt.Printf("Priority: %s\n", m.getPriorityName(bugDetails.Priority))
-	fmt.Printf("Target: %s\n", selectedMapping.LinearProjectName)
-	fmt.Printf("Labels: %s\n", strings.Join(append(selectedMapping.DefaultLabels, m.getSentryLabels(*issueDetails)...), ", "))
+	fmt.Printf("Target: %s\n", selectedMapping.ProjectName())
+	if assignee != nil {
+		fmt.Printf("Assignee: %s (%s)\n", assignee.User.DisplayName, assignee.Reason)
+	} else {
+		fmt.Println("Assignee: Unassigned")
+	}
+	fmt.Printf("Labels: %s\n", strings.Join(m.syncLabels(selectedMapping, tmpl, *issueDetails), ", "))
+	if tmpl != nil {
+		fmt.Printf("Template: %s", tmpl.Name)
+		if len(tmpl.SubIssues) > 0 {
+			fmt.Printf(" (%d sub-issues)", len(tmpl.SubIssues))
+		}
+		fmt.Println()
+	}
 	fmt.Println("\nDescription Preview:")
 	// Show first 500 chars of description
 	descPreview := bugDetails.Description
@@ -584,21 +687,26 @@ func (m *Module) syncBugs(cfg *config.Config) error {
 
 	fmt.Println(separator)
 
-	if !ui.GetConfirmation("Create this issue in Linear?") {
+	if !ui.GetConfirmation(fmt.Sprintf("Create this issue in %s?", tracker.Name())) {
 		return types.ErrNavigateBack
 	}
 
 	// Fetch workflow states
 	ui.ShowInfo("Fetching workflow states...")
-	states, err := linearClient.GetWorkflowStates(selectedMapping.LinearTeamID)
+	states, err := tracker.GetWorkflowStates(selectedMapping.TeamID())
 	if err != nil {
 		ui.ShowWarning(fmt.Sprintf("Could not fetch workflow states: %v", err))
-		states = []LinearWorkflowState{}
+		states = []TrackerState{}
 	}
 
-	// Select initial state
+	// Use the template's initial state, or select one
 	var selectedStateID string
-	if len(states) > 0 {
+	if tmpl != nil && tmpl.State != "" {
+		if selectedStateID = findStateID(states, tmpl.State); selectedStateID == "" {
+			ui.ShowWarning(fmt.Sprintf("State '%s' of template '%s' not found.", tmpl.State, tmpl.Name))
+		}
+	}
+	if selectedStateID == "" && len(states) > 0 {
 		stateOptions := make([]string, len(states))
 		for i, state := range states {
 			stateType := ""
@@ -633,41 +741,30 @@ func (m *Module) syncBugs(cfg *config.Config) error {
 		}
 	}
 
-	// Create labels
-	ui.ShowInfo("Creating labels in Linear...")
-	var labelIDs []string
-	allLabels := append(selectedMapping.DefaultLabels, m.getSentryLabels(*issueDetails)...)
-
-	for _, label := range allLabels {
-		labelID, err := linearClient.GetOrCreateLabel(
-			selectedMapping.LinearTeamID,
-			label,
-			m.getLabelColor(label),
-		)
-		if err != nil {
-			ui.ShowWarning(fmt.Sprintf("Failed to create label '%s': %v", label, err))
-			continue
+	// Create labels and the tracker issue
+	ui.ShowInfo(fmt.Sprintf("Creating issue in %s...", tracker.Name()))
+	trackerIssue, err := m.createTrackerIssue(tracker, selectedMapping, tmpl, *issueDetails, bugDetails, selectedStateID, assigneeID)
+	if err != nil {
+		ui.ShowError(err.Error())
+		return nil
+	}
+
+	if tmpl != nil && len(tmpl.SubIssues) > 0 {
+		ui.ShowInfo(fmt.Sprintf("Creating %d sub-issues...", len(tmpl.SubIssues)))
+		if _, err := m.createSyncSubIssues(tracker, selectedMapping, tmpl, trackerIssue, bugDetails); err != nil {
+			ui.ShowWarning(err.Error())
 		}
-		labelIDs = append(labelIDs, labelID)
 	}
 
-	// Create Linear issue
-	ui.ShowInfo("Creating issue in Linear...")
-	linearIssue, err := linearClient.CreateIssue(
-		selectedMapping.LinearTeamID,
-		selectedMapping.LinearProjectID,
-		bugDetails.Title,
-		bugDetails.Description,
-		labelIDs,
-		bugDetails.Priority,
-		selectedStateID,
-	)
-	if err != nil {
-		ui.ShowError(fmt.Sprintf("Failed to create Linear issue: %v", err))
-		return nil
+	// Remember the tracker issue so webhooks and later syncs update it instead of duplicating
+	if state != nil {
+		state.Record(selectedConnection.SentryInstance, *issueDetails, trackerIssue)
+		if err := state.Save(); err != nil {
+			ui.ShowWarning(fmt.Sprintf("Could not save sync state: %v", err))
+		}
 	}
 
-	ui.ShowSuccess(fmt.Sprintf("Issue created successfully!\nURL: %s", linearIssue.URL))
+	ui.ShowSuccess(fmt.Sprintf("Issue created successfully!\nURL: %s", trackerIssue.URL))
 
 	// Ask if user wants to resolve in Sentry
 	if ui.GetConfirmation("\nMark this issue as resolved in Sentry?") {
@@ -699,16 +796,29 @@ func (m *Module) prepareBugDetails(issue SentryIssue, event *SentryEvent) BugDet
 	// Build description with all relevant information
 	var description strings.Builder
 
-	description.WriteString("## Sentry Bug Report\n\n")
+	sourceName := "Sentry"
+	if issue.Source != "" {
+		sourceName = issue.Source
+	}
+
+	description.WriteString(fmt.Sprintf("## %s Bug Report\n\n", sourceName))
 
 	// Basic information
-	description.WriteString(fmt.Sprintf("**Sentry Issue:** [%s](%s)\n", issue.ShortID, issue.Permalink))
+	if issue.Permalink != "" {
+		description.WriteString(fmt.Sprintf("**%s Issue:** [%s](%s)\n", sourceName, issue.ShortID, issue.Permalink))
+	} else {
+		description.WriteString(fmt.Sprintf("**%s Issue:** %s\n", sourceName, issue.ShortID))
+	}
 	description.WriteString(fmt.Sprintf("**Level:** %s\n", issue.Level))
 	description.WriteString(fmt.Sprintf("**Platform:** %s\n", issue.Platform))
 	description.WriteString(fmt.Sprintf("**First Seen:** %s\n", issue.FirstSeen.Format("2006-01-02 15:04:05")))
 	description.WriteString(fmt.Sprintf("**Last Seen:** %s\n", issue.LastSeen.Format("2006-01-02 15:04:05")))
 	description.WriteString(fmt.Sprintf("**Occurrences:** %s\n", issue.Count))
-	description.WriteString(fmt.Sprintf("**Users Affected:** %d\n\n", issue.UserCount))
+	description.WriteString(fmt.Sprintf("**Users Affected:** %d\n", issue.UserCount))
+	if event != nil && event.Release != nil && event.Release.Version != "" {
+		description.WriteString(fmt.Sprintf("**Release:** `%s`\n", event.Release.Version))
+	}
+	description.WriteString("\n")
 
 	// Error details
 	description.WriteString("## Error Details\n\n")
@@ -762,13 +872,12 @@ func (m *Module) prepareBugDetails(issue SentryIssue, event *SentryEvent) BugDet
 							frame.LineNo,
 							frame.ColNo))
 
-						// Add code context if available
-						if len(frame.Context) > 0 && frame.LineNo > 0 {
-							description.WriteString(fmt.Sprintf("     %s\n", frame.AbsPath))
-						}
 					}
 				}
 				description.WriteString("```\n\n")
+
+				// Add source context for each in-app frame
+				m.writeSourceContext(&description, exception)
 			}
 		}
 
@@ -788,14 +897,22 @@ func (m *Module) prepareBugDetails(issue SentryIssue, event *SentryEvent) BugDet
 		}
 	}
 
+	// Add breadcrumbs, tags, request and user context
+	if event != nil {
+		description.WriteString("\n## Event Context\n\n")
+		m.writeEventContext(&description, event)
+	}
+
 	// Link back to Sentry
-	description.WriteString(fmt.Sprintf("\n---\n\n[View in Sentry](%s)", issue.Permalink))
+	if issue.Permalink != "" {
+		description.WriteString(fmt.Sprintf("\n---\n\n[View in %s](%s)", sourceName, issue.Permalink))
+	}
 
 	// Determine priority based on level and impact
 	priority := m.calculatePriority(issue)
 
 	return BugDetails{
-		Title:       fmt.Sprintf("[Sentry %s] %s", issue.ShortID, issue.Title),
+		Title:       fmt.Sprintf("[%s %s] %s", sourceName, issue.ShortID, issue.Title),
 		Description: description.String(),
 		Priority:    priority,
 	}
@@ -0,0 +1,67 @@
+package bugmanager
+
+import (
+	"fmt"
+
+	"github.com/kkz6/devtools/internal/config"
+	"github.com/kkz6/devtools/internal/ui"
+)
+
+// syncLabels returns the labels applied to an issue synced through a mapping and its template
+func (m *Module) syncLabels(mapping *config.BugManagerProjectMapping, tmpl *config.BugManagerTemplate, issue SentryIssue) []string {
+	labels := append([]string{}, mapping.DefaultLabels...)
+	if tmpl != nil {
+		labels = mergeLabels(labels, tmpl.Labels)
+	}
+	return mergeLabels(labels, m.getSentryLabels(issue))
+}
+
+// ensureLabels gets or creates each label in the team and returns their IDs.
+// Labels that cannot be created are reported and skipped.
+func (m *Module) ensureLabels(tracker IssueTracker, teamID string, labels []string) []string {
+	var labelIDs []string
+	for _, label := range labels {
+		labelID, err := tracker.GetOrCreateLabel(teamID, label, m.getLabelColor(label))
+		if err != nil {
+			ui.ShowWarning(fmt.Sprintf("Failed to create label '%s': %v", label, err))
+			continue
+		}
+		labelIDs = append(labelIDs, labelID)
+	}
+	return labelIDs
+}
+
+// createTrackerIssue creates the tracker issue for a prepared Sentry bug without prompting.
+// Sub-issues of the mapping's template are created separately with createSyncSubIssues.
+func (m *Module) createTrackerIssue(tracker IssueTracker, mapping *config.BugManagerProjectMapping, tmpl *config.BugManagerTemplate,
+	issue SentryIssue, details BugDetails, stateID, assigneeID string) (*TrackerIssue, error) {
+
+	labelIDs := m.ensureLabels(tracker, mapping.TeamID(), m.syncLabels(mapping, tmpl, issue))
+
+	trackerIssue, err := tracker.CreateIssue(TrackerIssueInput{
+		TeamID:      mapping.TeamID(),
+		ProjectID:   mapping.ProjectID(),
+		Title:       details.Title,
+		Description: details.Description,
+		LabelIDs:    labelIDs,
+		Priority:    details.Priority,
+		StateID:     stateID,
+		AssigneeID:  assigneeID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create issue in %s: %w", tracker.Name(), err)
+	}
+
+	return trackerIssue, nil
+}
+
+// createSyncSubIssues creates the template's sub-issues under an issue synced through a mapping
+func (m *Module) createSyncSubIssues(tracker IssueTracker, mapping *config.BugManagerProjectMapping, tmpl *config.BugManagerTemplate,
+	parent *TrackerIssue, details BugDetails) ([]*TrackerIssue, error) {
+
+	return m.createSubIssues(tracker, parent, TrackerIssueInput{
+		TeamID:    mapping.TeamID(),
+		ProjectID: mapping.ProjectID(),
+		Priority:  details.Priority,
+	}, tmpl)
+}
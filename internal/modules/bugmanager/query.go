@@ -0,0 +1,195 @@
+package bugmanager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kkz6/devtools/internal/config"
+	"github.com/kkz6/devtools/internal/types"
+	"github.com/kkz6/devtools/internal/ui"
+)
+
+// sortOptions lists the issue sort orders supported by Sentry
+var sortOptions = []struct {
+	Value string
+	Label string
+}{
+	{"date", "Last seen"},
+	{"new", "First seen"},
+	{"freq", "Events"},
+	{"user", "Users"},
+	{"priority", "Priority"},
+}
+
+// statsPeriodOptions lists the stats periods supported by Sentry
+var statsPeriodOptions = []string{"24h", "14d", "none"}
+
+// buildIssueQuery converts a mapping's query configuration into Sentry search parameters
+func (m *Module) buildIssueQuery(q config.BugManagerIssueQuery) IssueQuery {
+	query := DefaultIssueQuery()
+
+	terms := []string{"is:unresolved"}
+	if q.Environment != "" {
+		terms = append(terms, fmt.Sprintf("environment:%s", q.Environment))
+	}
+	if q.Release != "" {
+		terms = append(terms, fmt.Sprintf("release:%s", q.Release))
+	}
+	switch q.Assigned {
+	case "":
+	case "none":
+		terms = append(terms, "is:unassigned")
+	default:
+		terms = append(terms, fmt.Sprintf("assigned:%s", q.Assigned))
+	}
+	if q.Regressed {
+		terms = append(terms, "is:regressed")
+	}
+	if q.Custom != "" {
+		terms = append(terms, q.Custom)
+	}
+	query.Query = strings.Join(terms, " ")
+
+	if q.Sort != "" {
+		query.Sort = q.Sort
+	}
+	switch q.StatsPeriod {
+	case "":
+	case "none":
+		query.StatsPeriod = ""
+	default:
+		query.StatsPeriod = q.StatsPeriod
+	}
+
+	return query
+}
+
+// describeIssueQuery returns a short human-readable summary of a mapping's query
+func (m *Module) describeIssueQuery(q config.BugManagerIssueQuery) string {
+	query := m.buildIssueQuery(q)
+	period := query.StatsPeriod
+	if period == "" {
+		period = "none"
+	}
+	return fmt.Sprintf("%s (sort: %s, period: %s)", query.Query, query.Sort, period)
+}
+
+// editIssueQuery lets the user customize the Sentry search query of a mapping
+func (m *Module) editIssueQuery(cfg *config.Config, mapping *config.BugManagerProjectMapping) error {
+	for {
+		q := &mapping.IssueQuery
+
+		sortLabel := "Last seen"
+		for _, opt := range sortOptions {
+			if opt.Value == q.Sort {
+				sortLabel = opt.Label
+			}
+		}
+		statsPeriod := q.StatsPeriod
+		if statsPeriod == "" {
+			statsPeriod = "24h"
+		}
+		regressed := "No"
+		if q.Regressed {
+			regressed = "Yes"
+		}
+
+		fmt.Println()
+		ui.ShowInfo(fmt.Sprintf("Current query: %s", m.describeIssueQuery(*q)))
+
+		options := []string{
+			fmt.Sprintf("Environment (current: %s)", valueOrAny(q.Environment)),
+			fmt.Sprintf("Release (current: %s)", valueOrAny(q.Release)),
+			fmt.Sprintf("Assigned (current: %s)", valueOrAny(q.Assigned)),
+			fmt.Sprintf("Only Regressed Issues (current: %s)", regressed),
+			fmt.Sprintf("Custom Query (current: %s)", valueOrAny(q.Custom)),
+			fmt.Sprintf("Sort Order (current: %s)", sortLabel),
+			fmt.Sprintf("Stats Period (current: %s)", statsPeriod),
+			"Reset to Defaults",
+			"Back",
+		}
+
+		choice, err := ui.SelectFromList("Edit Issue Query", options)
+		if err != nil {
+			if err.Error() == "cancelled" {
+				return types.ErrNavigateBack
+			}
+			return err
+		}
+
+		switch choice {
+		case 0: // Environment
+			value, err := ui.GetInput("Environment (empty for any)", q.Environment, false, nil)
+			if err != nil {
+				continue
+			}
+			q.Environment = strings.TrimSpace(value)
+		case 1: // Release
+			value, err := ui.GetInput("Release version (empty for any)", q.Release, false, nil)
+			if err != nil {
+				continue
+			}
+			q.Release = strings.TrimSpace(value)
+		case 2: // Assigned
+			assignOptions := []string{"Anyone", "Assigned to me", "Unassigned", "Specific user or #team"}
+			assignChoice, err := ui.SelectFromList("Filter by assignee", assignOptions)
+			if err != nil {
+				continue
+			}
+			switch assignChoice {
+			case 0:
+				q.Assigned = ""
+			case 1:
+				q.Assigned = "me"
+			case 2:
+				q.Assigned = "none"
+			case 3:
+				value, err := ui.GetInput("Username, email or #team", q.Assigned, false, nil)
+				if err != nil {
+					continue
+				}
+				q.Assigned = strings.TrimSpace(value)
+			}
+		case 3: // Regressed
+			q.Regressed = !q.Regressed
+		case 4: // Custom query
+			value, err := ui.GetInput("Custom Sentry search terms (e.g. level:error)", q.Custom, false, nil)
+			if err != nil {
+				continue
+			}
+			q.Custom = strings.TrimSpace(value)
+		case 5: // Sort
+			labels := make([]string, len(sortOptions))
+			for i, opt := range sortOptions {
+				labels[i] = opt.Label
+			}
+			sortChoice, err := ui.SelectFromList("Sort issues by", labels)
+			if err != nil {
+				continue
+			}
+			q.Sort = sortOptions[sortChoice].Value
+		case 6: // Stats period
+			periodChoice, err := ui.SelectFromList("Stats period", statsPeriodOptions)
+			if err != nil {
+				continue
+			}
+			q.StatsPeriod = statsPeriodOptions[periodChoice]
+		case 7: // Reset
+			mapping.IssueQuery = config.BugManagerIssueQuery{}
+		case 8: // Back
+			return types.ErrNavigateBack
+		}
+
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+	}
+}
+
+// valueOrAny returns the value or "any" when it is empty
+func valueOrAny(value string) string {
+	if value == "" {
+		return "any"
+	}
+	return value
+}
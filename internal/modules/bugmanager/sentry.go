@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/getsentry/sentry-go"
@@ -61,8 +62,11 @@ type SentryIssue struct {
 	LastSeen      time.Time              `json:"lastSeen"`
 	Level         string                 `json:"level"`
 	Status        string                 `json:"status"`
+	Substatus     string                 `json:"substatus"` // e.g. "new", "ongoing", "regressed" or "escalating"
 	StatusDetails map[string]interface{} `json:"statusDetails"`
 	IsPublic      bool                   `json:"isPublic"`
+	IsBookmarked  bool                   `json:"isBookmarked"`
+	AssignedTo    *SentryActor           `json:"assignedTo"`
 	Platform      string                 `json:"platform"`
 	Project       struct {
 		ID   string `json:"id"`
@@ -71,6 +75,17 @@ type SentryIssue struct {
 	} `json:"project"`
 	Type     string                 `json:"type"`
 	Metadata map[string]interface{} `json:"metadata"`
+
+	// Source names the ErrorSource of issues that do not come from Sentry
+	Source string `json:"-"`
+}
+
+// SentryActor is the user or team an issue is assigned to
+type SentryActor struct {
+	Type  string `json:"type"` // "user" or "team"
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
 }
 
 // SentryEvent represents a Sentry event with stack trace
@@ -86,28 +101,149 @@ type SentryEvent struct {
 		Data json.RawMessage `json:"data"`
 	} `json:"entries"`
 	Exception struct {
-		Values []struct {
-			Type       string `json:"type"`
-			Value      string `json:"value"`
-			Stacktrace struct {
-				Frames []struct {
-					Filename string          `json:"filename"`
-					Function string          `json:"function"`
-					Module   string          `json:"module"`
-					LineNo   int             `json:"lineNo"`
-					ColNo    int             `json:"colNo"`
-					AbsPath  string          `json:"absPath"`
-					Context  [][]interface{} `json:"context"`
-					InApp    bool            `json:"inApp"`
-				} `json:"frames"`
-			} `json:"stacktrace"`
-		} `json:"values"`
+		Values []SentryException `json:"values"`
 	} `json:"exception"`
+	Tags     []SentryTag                       `json:"tags"`
+	Contexts map[string]map[string]interface{} `json:"contexts"`
+	User     *SentryUser                       `json:"user"`
+	Release  *SentryRelease                    `json:"release"`
+
+	// Populated from Entries by parseEntries
+	Breadcrumbs []SentryBreadcrumb `json:"-"`
+	Request     *SentryRequest     `json:"-"`
 }
 
-// GetProjects fetches all projects from Sentry
-func (c *SentryClient) GetProjects() ([]SentryProject, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/projects/", c.baseURL), nil)
+// SentryException represents a single exception within an event
+type SentryException struct {
+	Type       string `json:"type"`
+	Value      string `json:"value"`
+	Module     string `json:"module"`
+	Stacktrace struct {
+		Frames []SentryFrame `json:"frames"`
+	} `json:"stacktrace"`
+}
+
+// SentryFrame represents a single stack frame
+type SentryFrame struct {
+	Filename string          `json:"filename"`
+	Function string          `json:"function"`
+	Module   string          `json:"module"`
+	LineNo   int             `json:"lineNo"`
+	ColNo    int             `json:"colNo"`
+	AbsPath  string          `json:"absPath"`
+	Context  [][]interface{} `json:"context"`
+	InApp    bool            `json:"inApp"`
+}
+
+// SentryTag represents a key/value tag attached to an event
+type SentryTag struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// SentryUser represents the user context of an event
+type SentryUser struct {
+	ID        string `json:"id"`
+	Email     string `json:"email"`
+	Username  string `json:"username"`
+	Name      string `json:"name"`
+	IPAddress string `json:"ip_address"`
+}
+
+// SentryRelease represents the release an event was reported from
+type SentryRelease struct {
+	Version      string    `json:"version"`
+	ShortVersion string    `json:"shortVersion"`
+	DateCreated  time.Time `json:"dateCreated"`
+}
+
+// SentryBreadcrumb represents a single breadcrumb recorded before an event
+type SentryBreadcrumb struct {
+	Timestamp string                 `json:"timestamp"`
+	Type      string                 `json:"type"`
+	Category  string                 `json:"category"`
+	Level     string                 `json:"level"`
+	Message   string                 `json:"message"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// SentryRequest represents the HTTP request entry of an event
+type SentryRequest struct {
+	URL     string          `json:"url"`
+	Method  string          `json:"method"`
+	Query   json.RawMessage `json:"query"`
+	Headers [][]interface{} `json:"headers"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// parseEntries decodes the typed entries of an event into their own fields
+func (e *SentryEvent) parseEntries() error {
+	for _, entry := range e.Entries {
+		switch entry.Type {
+		case "breadcrumbs":
+			var data struct {
+				Values []SentryBreadcrumb `json:"values"`
+			}
+			if err := json.Unmarshal(entry.Data, &data); err != nil {
+				return fmt.Errorf("failed to decode breadcrumbs: %w", err)
+			}
+			e.Breadcrumbs = data.Values
+		case "request":
+			var request SentryRequest
+			if err := json.Unmarshal(entry.Data, &request); err != nil {
+				return fmt.Errorf("failed to decode request: %w", err)
+			}
+			e.Request = &request
+		case "exception":
+			// The events API nests exceptions in entries rather than at the top level
+			if len(e.Exception.Values) > 0 {
+				continue
+			}
+			var data struct {
+				Values []SentryException `json:"values"`
+			}
+			if err := json.Unmarshal(entry.Data, &data); err != nil {
+				return fmt.Errorf("failed to decode exception: %w", err)
+			}
+			e.Exception.Values = data.Values
+		}
+	}
+	return nil
+}
+
+// IssueQuery holds the search parameters for listing Sentry issues
+type IssueQuery struct {
+	Query       string // Sentry search query, e.g. "is:unresolved environment:production"
+	Sort        string // date, new, freq, user or priority
+	StatsPeriod string // 24h, 14d or empty to disable stats
+	Limit       int    // Issues per page
+	Cursor      string // Cursor returned by a previous page
+}
+
+// DefaultIssueQuery returns the query used when a mapping does not customize it
+func DefaultIssueQuery() IssueQuery {
+	return IssueQuery{
+		Query:       "is:unresolved",
+		Sort:        "date",
+		StatsPeriod: "24h",
+		Limit:       25,
+	}
+}
+
+// doGet performs an authenticated GET request, decodes the JSON body into out
+// and returns the cursor of the next page if Sentry reports more results
+func (c *SentryClient) doGet(rawURL string, out interface{}) (string, error) {
+	header, err := c.doGetHeader(rawURL, out)
+	if err != nil {
+		return "", err
+	}
+	return parseNextCursor(header.Get("Link")), nil
+}
+
+// doGetHeader performs an authenticated GET request, decodes the JSON body into out
+// and returns the response headers
+func (c *SentryClient) doGetHeader(rawURL string, out interface{}) (http.Header, error) {
+	req, err := http.NewRequest("GET", rawURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -117,7 +253,7 @@ func (c *SentryClient) GetProjects() ([]SentryProject, error) {
 
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch projects: %w", err)
+		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -126,11 +262,57 @@ func (c *SentryClient) GetProjects() ([]SentryProject, error) {
 		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
 	}
 
-	var projects []SentryProject
-	if err := json.NewDecoder(resp.Body).Decode(&projects); err != nil {
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
+	return resp.Header, nil
+}
+
+// parseNextCursor extracts the next page cursor from a Sentry Link header.
+// Sentry always returns a next link; results="false" marks the last page.
+func parseNextCursor(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		if !strings.Contains(part, `rel="next"`) || !strings.Contains(part, `results="true"`) {
+			continue
+		}
+		const marker = `cursor="`
+		idx := strings.Index(part, marker)
+		if idx == -1 {
+			continue
+		}
+		cursor := part[idx+len(marker):]
+		if end := strings.Index(cursor, `"`); end != -1 {
+			return cursor[:end]
+		}
+	}
+	return ""
+}
+
+// GetProjects fetches all projects from Sentry, following every page
+func (c *SentryClient) GetProjects() ([]SentryProject, error) {
+	var projects []SentryProject
+	cursor := ""
+
+	for {
+		params := url.Values{}
+		if cursor != "" {
+			params.Set("cursor", cursor)
+		}
+
+		var page []SentryProject
+		next, err := c.doGet(fmt.Sprintf("%s/projects/?%s", c.baseURL, params.Encode()), &page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch projects: %w", err)
+		}
+		projects = append(projects, page...)
+
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
 	// Ensure organization slug is populated from nested structure
 	for i := range projects {
 		if projects[i].OrganizationSlug == "" && projects[i].Organization.Slug != "" {
@@ -141,43 +323,60 @@ func (c *SentryClient) GetProjects() ([]SentryProject, error) {
 	return projects, nil
 }
 
-// GetUnresolvedIssues fetches unresolved issues for a specific project
-func (c *SentryClient) GetUnresolvedIssues(organizationSlug, projectSlug string, limit int) ([]SentryIssue, error) {
-	// Build query parameters
+// GetIssues fetches a single page of issues matching the query and returns
+// the cursor of the next page, or an empty string on the last page
+func (c *SentryClient) GetIssues(organizationSlug, projectSlug string, query IssueQuery) ([]SentryIssue, string, error) {
 	params := url.Values{}
-	params.Set("query", "is:unresolved")
-	params.Set("limit", fmt.Sprintf("%d", limit))
-	params.Set("sort", "date")
-	params.Set("statsPeriod", "24h")
+	params.Set("query", query.Query)
+	if query.Limit > 0 {
+		params.Set("limit", fmt.Sprintf("%d", query.Limit))
+	}
+	if query.Sort != "" {
+		params.Set("sort", query.Sort)
+	}
+	// An empty statsPeriod disables stats, which makes the request cheaper
+	params.Set("statsPeriod", query.StatsPeriod)
+	if query.Cursor != "" {
+		params.Set("cursor", query.Cursor)
+	}
 
-	url := fmt.Sprintf("%s/projects/%s/%s/issues/?%s",
+	issuesURL := fmt.Sprintf("%s/projects/%s/%s/issues/?%s",
 		c.baseURL, organizationSlug, projectSlug, params.Encode())
 
-	req, err := http.NewRequest("GET", url, nil)
+	var issues []SentryIssue
+	next, err := c.doGet(issuesURL, &issues)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, "", fmt.Errorf("failed to fetch issues: %w", err)
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
-	req.Header.Set("Content-Type", "application/json")
+	return issues, next, nil
+}
 
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch issues: %w", err)
-	}
-	defer resp.Body.Close()
+// GetAllIssues fetches issues matching the query across pages until
+// maxIssues is reached; a maxIssues of 0 fetches every page
+func (c *SentryClient) GetAllIssues(organizationSlug, projectSlug string, query IssueQuery, maxIssues int) ([]SentryIssue, error) {
+	var issues []SentryIssue
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
-	}
+	for {
+		page, next, err := c.GetIssues(organizationSlug, projectSlug, query)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, page...)
 
-	var issues []SentryIssue
-	if err := json.NewDecoder(resp.Body).Decode(&issues); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		if maxIssues > 0 && len(issues) >= maxIssues {
+			return issues[:maxIssues], nil
+		}
+		if next == "" {
+			return issues, nil
+		}
+		query.Cursor = next
 	}
+}
 
-	return issues, nil
+// GetUnresolvedIssues fetches unresolved issues for a specific project
+func (c *SentryClient) GetUnresolvedIssues(organizationSlug, projectSlug string, limit int) ([]SentryIssue, error) {
+	return c.GetAllIssues(organizationSlug, projectSlug, DefaultIssueQuery(), limit)
 }
 
 // GetIssueDetails fetches detailed information about a specific issue
@@ -239,6 +438,10 @@ func (c *SentryClient) GetLatestEvent(issueID string) (*SentryEvent, error) {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
+	if err := event.parseEntries(); err != nil {
+		return nil, err
+	}
+
 	return &event, nil
 }
 
@@ -277,3 +480,68 @@ func (c *SentryClient) ResolveIssue(issueID string) error {
 
 	return nil
 }
+
+// SentryAuthInfo describes the authenticated user and the scopes granted to an API token
+type SentryAuthInfo struct {
+	Scopes []string
+	User   *SentryUser
+}
+
+// GetAuthInfo fetches the identity and scopes of the API token from the API root
+func (c *SentryClient) GetAuthInfo() (*SentryAuthInfo, error) {
+	var result struct {
+		Auth *struct {
+			Scopes []string `json:"scopes"`
+		} `json:"auth"`
+		User *SentryUser `json:"user"`
+	}
+	if _, err := c.doGet(strings.TrimRight(c.baseURL, "/")+"/", &result); err != nil {
+		return nil, fmt.Errorf("failed to fetch token info: %w", err)
+	}
+	if result.Auth == nil {
+		return nil, fmt.Errorf("API token was not accepted")
+	}
+	return &SentryAuthInfo{Scopes: result.Auth.Scopes, User: result.User}, nil
+}
+
+// SentryCommitter is an author with the commits Sentry suspects of causing an event
+type SentryCommitter struct {
+	Author struct {
+		Name     string `json:"name"`
+		Email    string `json:"email"`
+		Username string `json:"username"`
+	} `json:"author"`
+	Commits []SentryCommit `json:"commits"`
+}
+
+// SentryCommit is a commit known to Sentry through a repository integration
+type SentryCommit struct {
+	ID          string    `json:"id"` // Commit SHA
+	Message     string    `json:"message"`
+	DateCreated time.Time `json:"dateCreated"`
+	Repository  struct {
+		Name string `json:"name"` // e.g. "owner/repo" for GitHub repositories
+		URL  string `json:"url"`
+	} `json:"repository"`
+	PullRequest *struct {
+		ID          string `json:"id"`
+		Title       string `json:"title"`
+		ExternalURL string `json:"externalUrl"`
+	} `json:"pullRequest"`
+}
+
+// GetCommitters fetches the suspect commits of an event, most likely first.
+// Events without suspect commits (e.g. no release or repository integration) return none.
+func (c *SentryClient) GetCommitters(organizationSlug, projectSlug, eventID string) ([]SentryCommitter, error) {
+	var result struct {
+		Committers []SentryCommitter `json:"committers"`
+	}
+	if _, err := c.doGet(fmt.Sprintf("%s/projects/%s/%s/events/%s/committers/",
+		c.baseURL, organizationSlug, projectSlug, eventID), &result); err != nil {
+		if strings.Contains(err.Error(), "status 404") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch suspect commits: %w", err)
+	}
+	return result.Committers, nil
+}
@@ -0,0 +1,214 @@
+package bugmanager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// SentryIgnoreOptions sets when an ignored issue comes back.
+// Zero values are left out; with no conditions the issue is ignored forever.
+type SentryIgnoreOptions struct {
+	Minutes    int // Ignore for this many minutes
+	Count      int // Until it occurs this many more times...
+	Window     int // ...within this many minutes
+	UserCount  int // Until it affects this many more users...
+	UserWindow int // ...within this many minutes
+}
+
+// SentryMember is a member of a Sentry organization
+type SentryMember struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+	Name  string `json:"name"`
+	User  *struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+		Name     string `json:"name"`
+	} `json:"user"` // Nil for pending invitations
+}
+
+// SentryTeam is a Sentry team
+type SentryTeam struct {
+	ID   string `json:"id"`
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// doSend performs an authenticated request with a JSON body and decodes the response into out
+func (c *SentryClient) doSend(method, rawURL string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest(method, rawURL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+// updateIssue applies changes to a single issue
+func (c *SentryClient) updateIssue(issueID string, changes map[string]interface{}) error {
+	return c.doSend("PUT", fmt.Sprintf("%s/issues/%s/", c.baseURL, issueID), changes, nil)
+}
+
+// IgnoreIssue ignores an issue until the given conditions are met
+func (c *SentryClient) IgnoreIssue(issueID string, options SentryIgnoreOptions) error {
+	details := map[string]interface{}{}
+	if options.Minutes > 0 {
+		details["ignoreDuration"] = options.Minutes
+	}
+	if options.Count > 0 {
+		details["ignoreCount"] = options.Count
+		if options.Window > 0 {
+			details["ignoreWindow"] = options.Window
+		}
+	}
+	if options.UserCount > 0 {
+		details["ignoreUserCount"] = options.UserCount
+		if options.UserWindow > 0 {
+			details["ignoreUserWindow"] = options.UserWindow
+		}
+	}
+
+	if err := c.updateIssue(issueID, map[string]interface{}{
+		"status":        "ignored",
+		"statusDetails": details,
+	}); err != nil {
+		return fmt.Errorf("failed to ignore issue: %w", err)
+	}
+	return nil
+}
+
+// ResolveInNextRelease resolves an issue once the next release is deployed
+func (c *SentryClient) ResolveInNextRelease(issueID string) error {
+	if err := c.updateIssue(issueID, map[string]interface{}{
+		"status":        "resolved",
+		"statusDetails": map[string]interface{}{"inNextRelease": true},
+	}); err != nil {
+		return fmt.Errorf("failed to resolve issue in next release: %w", err)
+	}
+	return nil
+}
+
+// AssignIssue assigns an issue to an actor such as "user:123", "team:45", a username
+// or an email address. An empty assignee clears the assignment.
+func (c *SentryClient) AssignIssue(issueID, assignee string) error {
+	if err := c.updateIssue(issueID, map[string]interface{}{"assignedTo": assignee}); err != nil {
+		return fmt.Errorf("failed to assign issue: %w", err)
+	}
+	return nil
+}
+
+// SetBookmark bookmarks or unbookmarks an issue for the API token's user
+func (c *SentryClient) SetBookmark(issueID string, bookmarked bool) error {
+	if err := c.updateIssue(issueID, map[string]interface{}{"isBookmarked": bookmarked}); err != nil {
+		return fmt.Errorf("failed to update bookmark: %w", err)
+	}
+	return nil
+}
+
+// AddNote adds a note (comment) to an issue
+func (c *SentryClient) AddNote(issueID, text string) error {
+	if err := c.doSend("POST", fmt.Sprintf("%s/issues/%s/comments/", c.baseURL, issueID),
+		map[string]interface{}{"text": text}, nil); err != nil {
+		return fmt.Errorf("failed to add note: %w", err)
+	}
+	return nil
+}
+
+// MergeIssues merges issues of an organization into one and returns the ID of the
+// issue the others were merged into
+func (c *SentryClient) MergeIssues(organizationSlug string, issueIDs []string) (string, error) {
+	if len(issueIDs) < 2 {
+		return "", fmt.Errorf("at least two issues are needed to merge")
+	}
+
+	params := url.Values{}
+	for _, id := range issueIDs {
+		params.Add("id", id)
+	}
+
+	var result struct {
+		Merge struct {
+			Parent   string   `json:"parent"`
+			Children []string `json:"children"`
+		} `json:"merge"`
+	}
+	if err := c.doSend("PUT", fmt.Sprintf("%s/organizations/%s/issues/?%s", c.baseURL, organizationSlug, params.Encode()),
+		map[string]interface{}{"merge": 1}, &result); err != nil {
+		return "", fmt.Errorf("failed to merge issues: %w", err)
+	}
+	return result.Merge.Parent, nil
+}
+
+// ResolveShortID looks up the numeric ID of an issue from its short ID, e.g. "BACKEND-1A"
+func (c *SentryClient) ResolveShortID(organizationSlug, shortID string) (string, error) {
+	var result struct {
+		GroupID string `json:"groupId"`
+	}
+	if _, err := c.doGet(fmt.Sprintf("%s/organizations/%s/shortids/%s/",
+		c.baseURL, organizationSlug, url.PathEscape(shortID)), &result); err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", shortID, err)
+	}
+	return result.GroupID, nil
+}
+
+// GetMembers fetches the members of an organization, following every page
+func (c *SentryClient) GetMembers(organizationSlug string) ([]SentryMember, error) {
+	var members []SentryMember
+	cursor := ""
+
+	for {
+		params := url.Values{}
+		if cursor != "" {
+			params.Set("cursor", cursor)
+		}
+
+		var page []SentryMember
+		next, err := c.doGet(fmt.Sprintf("%s/organizations/%s/members/?%s", c.baseURL, organizationSlug, params.Encode()), &page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch members: %w", err)
+		}
+		members = append(members, page...)
+
+		if next == "" {
+			return members, nil
+		}
+		cursor = next
+	}
+}
+
+// GetTeams fetches the teams of an organization
+func (c *SentryClient) GetTeams(organizationSlug string) ([]SentryTeam, error) {
+	var teams []SentryTeam
+	if _, err := c.doGet(fmt.Sprintf("%s/organizations/%s/teams/", c.baseURL, organizationSlug), &teams); err != nil {
+		return nil, fmt.Errorf("failed to fetch teams: %w", err)
+	}
+	return teams, nil
+}
@@ -0,0 +1,129 @@
+package bugmanager
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SentryReleaseSummary is a release as listed by the organization releases endpoint
+type SentryReleaseSummary struct {
+	Version      string     `json:"version"`
+	ShortVersion string     `json:"shortVersion"`
+	DateCreated  time.Time  `json:"dateCreated"`
+	DateReleased *time.Time `json:"dateReleased"`
+	NewGroups    int        `json:"newGroups"` // Issues first seen in this release
+}
+
+// SentryEventStats holds error event totals from the stats_v2 endpoint
+type SentryEventStats struct {
+	Intervals []time.Time      // Start of each interval
+	Accepted  []int64          // Accepted events per interval
+	Totals    map[string]int64 // Total events per outcome, e.g. "accepted", "filtered", "rate_limited"
+}
+
+// Dropped returns the events that were not accepted
+func (s *SentryEventStats) Dropped() int64 {
+	var dropped int64
+	for outcome, total := range s.Totals {
+		if outcome != "accepted" {
+			dropped += total
+		}
+	}
+	return dropped
+}
+
+// SearchOrganizationIssues fetches one page of a project's issues through the organization
+// issues endpoint. Unlike the project endpoint, query.StatsPeriod sets the time range the
+// issues and their counts cover (e.g. "7d"). The total number of matches is returned as well.
+func (c *SentryClient) SearchOrganizationIssues(organizationSlug, projectID string, query IssueQuery) ([]SentryIssue, int, error) {
+	params := url.Values{}
+	params.Set("project", projectID)
+	params.Set("query", query.Query)
+	if query.Sort != "" {
+		params.Set("sort", query.Sort)
+	}
+	if query.StatsPeriod != "" {
+		params.Set("statsPeriod", query.StatsPeriod)
+	}
+	if query.Limit > 0 {
+		params.Set("limit", fmt.Sprintf("%d", query.Limit))
+	}
+	if query.Cursor != "" {
+		params.Set("cursor", query.Cursor)
+	}
+
+	var issues []SentryIssue
+	header, err := c.doGetHeader(fmt.Sprintf("%s/organizations/%s/issues/?%s",
+		c.baseURL, organizationSlug, params.Encode()), &issues)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search issues: %w", err)
+	}
+
+	// X-Hits carries the total number of matching issues across all pages
+	hits, err := strconv.Atoi(header.Get("X-Hits"))
+	if err != nil {
+		hits = len(issues)
+	}
+
+	return issues, hits, nil
+}
+
+// GetReleases fetches the most recent releases of a project
+func (c *SentryClient) GetReleases(organizationSlug, projectID string, limit int) ([]SentryReleaseSummary, error) {
+	params := url.Values{}
+	params.Set("project", projectID)
+	params.Set("per_page", fmt.Sprintf("%d", limit))
+
+	var releases []SentryReleaseSummary
+	if _, err := c.doGet(fmt.Sprintf("%s/organizations/%s/releases/?%s",
+		c.baseURL, organizationSlug, params.Encode()), &releases); err != nil {
+		return nil, fmt.Errorf("failed to fetch releases: %w", err)
+	}
+
+	if len(releases) > limit {
+		releases = releases[:limit]
+	}
+	return releases, nil
+}
+
+// GetEventStats fetches daily error event counts of a project grouped by outcome
+func (c *SentryClient) GetEventStats(organizationSlug, projectID, statsPeriod string) (*SentryEventStats, error) {
+	params := url.Values{}
+	params.Set("project", projectID)
+	params.Set("statsPeriod", statsPeriod)
+	params.Set("interval", "1d")
+	params.Set("category", "error")
+	params.Set("field", "sum(quantity)")
+	params.Set("groupBy", "outcome")
+
+	var response struct {
+		Intervals []time.Time `json:"intervals"`
+		Groups    []struct {
+			By struct {
+				Outcome string `json:"outcome"`
+			} `json:"by"`
+			Totals map[string]int64   `json:"totals"`
+			Series map[string][]int64 `json:"series"`
+		} `json:"groups"`
+	}
+	if _, err := c.doGet(fmt.Sprintf("%s/organizations/%s/stats_v2/?%s",
+		c.baseURL, organizationSlug, params.Encode()), &response); err != nil {
+		return nil, fmt.Errorf("failed to fetch event stats: %w", err)
+	}
+
+	stats := &SentryEventStats{
+		Intervals: response.Intervals,
+		Accepted:  make([]int64, len(response.Intervals)),
+		Totals:    make(map[string]int64),
+	}
+	for _, group := range response.Groups {
+		stats.Totals[group.By.Outcome] += group.Totals["sum(quantity)"]
+		if group.By.Outcome == "accepted" {
+			copy(stats.Accepted, group.Series["sum(quantity)"])
+		}
+	}
+
+	return stats, nil
+}
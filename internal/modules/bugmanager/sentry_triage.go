@@ -0,0 +1,355 @@
+package bugmanager
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kkz6/devtools/internal/types"
+	"github.com/kkz6/devtools/internal/ui"
+)
+
+// errSyncIssue is returned by sentryIssueActions when the user chose to sync the issue
+var errSyncIssue = fmt.Errorf("sync issue")
+
+// formatSentryIssueOption formats a Sentry issue for the issue list, marking
+// issues that are no longer unresolved, bookmarked or assigned
+func formatSentryIssueOption(issue SentryIssue) string {
+	option := fmt.Sprintf("[%s] %s (Level: %s, Count: %s, Users: %d)",
+		issue.ShortID, issue.Title, issue.Level, issue.Count, issue.UserCount)
+
+	var marks []string
+	if issue.Status != "" && issue.Status != "unresolved" {
+		marks = append(marks, issue.Status)
+	}
+	if issue.IsBookmarked {
+		marks = append(marks, "bookmarked")
+	}
+	if issue.AssignedTo != nil {
+		marks = append(marks, "→ "+issue.AssignedTo.Name)
+	}
+	if len(marks) > 0 {
+		option += " {" + strings.Join(marks, ", ") + "}"
+	}
+	return option
+}
+
+// sentryIssueActions shows the actions for a listed Sentry issue and applies the chosen one.
+// Issues merged into the selected one are removed from the list.
+// It returns errSyncIssue when the user chose to create a tracker issue.
+func (m *Module) sentryIssueActions(client *SentryClient, organization, trackerName string, issues *[]SentryIssue, index int) error {
+	issue := &(*issues)[index]
+
+	bookmark := "Bookmark"
+	if issue.IsBookmarked {
+		bookmark = "Remove Bookmark"
+	}
+	options := []string{
+		fmt.Sprintf("Create Issue in %s", trackerName),
+		"Ignore...",
+		"Resolve",
+		"Resolve in Next Release",
+		"Assign...",
+		bookmark,
+		"Add Note",
+		"Merge Duplicates into This Issue...",
+		"Back to List",
+	}
+
+	choice, err := ui.SelectFromList(fmt.Sprintf("[%s] %s", issue.ShortID, truncate(issue.Title, 60)), options)
+	if err != nil {
+		return types.ErrNavigateBack
+	}
+
+	switch choice {
+	case 0: // Sync
+		return errSyncIssue
+
+	case 1: // Ignore
+		ignore, label, err := selectIgnoreOptions()
+		if err != nil {
+			return err
+		}
+		if err := client.IgnoreIssue(issue.ID, *ignore); err != nil {
+			ui.ShowError(err.Error())
+			return nil
+		}
+		issue.Status = "ignored"
+		ui.ShowSuccess(fmt.Sprintf("%s ignored %s", issue.ShortID, label))
+
+	case 2: // Resolve
+		if err := client.ResolveIssue(issue.ID); err != nil {
+			ui.ShowError(err.Error())
+			return nil
+		}
+		issue.Status = "resolved"
+		ui.ShowSuccess(fmt.Sprintf("%s resolved", issue.ShortID))
+
+	case 3: // Resolve in next release
+		if err := client.ResolveInNextRelease(issue.ID); err != nil {
+			ui.ShowError(err.Error())
+			return nil
+		}
+		issue.Status = "resolved"
+		ui.ShowSuccess(fmt.Sprintf("%s will be resolved in the next release", issue.ShortID))
+
+	case 4: // Assign
+		actor, name, err := m.selectSentryAssignee(client, organization)
+		if err != nil {
+			if err != types.ErrNavigateBack {
+				ui.ShowError(err.Error())
+			}
+			return nil
+		}
+		if err := client.AssignIssue(issue.ID, actor); err != nil {
+			ui.ShowError(err.Error())
+			return nil
+		}
+		issue.AssignedTo = nil
+		if actor != "" {
+			issue.AssignedTo = &SentryActor{Name: name}
+		}
+		ui.ShowSuccess(fmt.Sprintf("%s assigned to %s", issue.ShortID, name))
+
+	case 5: // Bookmark
+		if err := client.SetBookmark(issue.ID, !issue.IsBookmarked); err != nil {
+			ui.ShowError(err.Error())
+			return nil
+		}
+		issue.IsBookmarked = !issue.IsBookmarked
+		ui.ShowSuccess(fmt.Sprintf("Bookmark updated for %s", issue.ShortID))
+
+	case 6: // Note
+		note, err := m.readSentryNote()
+		if err != nil || note == "" {
+			return nil
+		}
+		if err := client.AddNote(issue.ID, note); err != nil {
+			ui.ShowError(err.Error())
+			return nil
+		}
+		ui.ShowSuccess(fmt.Sprintf("Note added to %s", issue.ShortID))
+
+	case 7: // Merge
+		duplicates, err := selectMergeDuplicates(*issues, index)
+		if err != nil || len(duplicates) == 0 {
+			return nil
+		}
+		if !ui.GetConfirmation(fmt.Sprintf("Merge %d issues into %s?", len(duplicates), issue.ShortID)) {
+			return nil
+		}
+
+		// Sentry keeps the issue with the most events as the parent
+		ids := []string{issue.ID}
+		for _, i := range duplicates {
+			ids = append(ids, (*issues)[i].ID)
+		}
+		parent, err := client.MergeIssues(organization, ids)
+		if err != nil {
+			ui.ShowError(err.Error())
+			return nil
+		}
+
+		var remaining []SentryIssue
+		for _, listed := range *issues {
+			merged := false
+			for _, id := range ids {
+				merged = merged || (listed.ID == id && id != parent)
+			}
+			if !merged {
+				remaining = append(remaining, listed)
+			}
+		}
+		*issues = remaining
+		ui.ShowSuccess(fmt.Sprintf("Merged %d issues", len(ids)))
+
+	case 8: // Back
+		return nil
+	}
+
+	return nil
+}
+
+// selectIgnoreOptions asks how long an issue should be ignored and returns a description of the choice
+func selectIgnoreOptions() (*SentryIgnoreOptions, string, error) {
+	options := []string{
+		"For 30 minutes",
+		"For 2 hours",
+		"For 24 hours",
+		"For 1 week",
+		"Until it occurs N more times...",
+		"Until it affects N more users...",
+		"Forever",
+	}
+	choice, err := ui.SelectFromList("Ignore issue", options)
+	if err != nil {
+		return nil, "", types.ErrNavigateBack
+	}
+
+	durations := []int{30, 120, 24 * 60, 7 * 24 * 60}
+	if choice < len(durations) {
+		return &SentryIgnoreOptions{Minutes: durations[choice]}, strings.ToLower(options[choice]), nil
+	}
+	if choice == 6 {
+		return &SentryIgnoreOptions{}, "forever", nil
+	}
+
+	count, err := ui.GetInput("Number of occurrences", "100", false, validatePositiveInt)
+	if err != nil {
+		return nil, "", types.ErrNavigateBack
+	}
+	window, err := ui.GetInput("Within (e.g. 1h, 1d; leave empty for no window)", "1h", false, func(s string) error {
+		_, err := parseMinutes(s)
+		return err
+	})
+	if err != nil && err.Error() != "cancelled" {
+		return nil, "", err
+	}
+
+	n, _ := strconv.Atoi(count)
+	minutes, _ := parseMinutes(window)
+	ignore := &SentryIgnoreOptions{}
+	label := fmt.Sprintf("until it occurs %d more times", n)
+	if choice == 5 {
+		ignore.UserCount, ignore.UserWindow = n, minutes
+		label = fmt.Sprintf("until it affects %d more users", n)
+	} else {
+		ignore.Count, ignore.Window = n, minutes
+	}
+	if minutes > 0 {
+		label += " within " + window
+	}
+	return ignore, label, nil
+}
+
+// selectSentryAssignee lets the user pick an organization member or team.
+// It returns the actor to assign ("" to unassign) and its display name.
+func (m *Module) selectSentryAssignee(client *SentryClient, organization string) (string, string, error) {
+	ui.ShowInfo("Fetching members and teams...")
+	members, err := client.GetMembers(organization)
+	if err != nil {
+		return "", "", err
+	}
+	teams, err := client.GetTeams(organization)
+	if err != nil {
+		return "", "", err
+	}
+
+	type assignee struct{ actor, name string }
+	choices := []assignee{{"", "nobody"}}
+	options := []string{"Unassign"}
+	for _, team := range teams {
+		choices = append(choices, assignee{"team:" + team.ID, "#" + team.Slug})
+		options = append(options, fmt.Sprintf("Team: #%s", team.Slug))
+	}
+	for _, member := range members {
+		if member.User == nil {
+			continue // Pending invitations cannot be assigned
+		}
+		name := member.Name
+		if name == "" {
+			name = member.Email
+		}
+		choices = append(choices, assignee{"user:" + member.User.ID, name})
+		options = append(options, fmt.Sprintf("%s (%s)", name, member.Email))
+	}
+
+	choice, err := ui.SelectFromList("Assign to", options)
+	if err != nil {
+		return "", "", types.ErrNavigateBack
+	}
+	return choices[choice].actor, choices[choice].name, nil
+}
+
+// readSentryNote reads a note in the user's editor, or line by line without one
+func (m *Module) readSentryNote() (string, error) {
+	if editorCommand() != nil {
+		note, err := editInEditor("", "sentry-note-*.md")
+		if err != nil {
+			ui.ShowError(err.Error())
+			return "", err
+		}
+		return strings.TrimSpace(note), nil
+	}
+
+	fmt.Println("Enter the note line by line. Press Enter on empty line twice to finish.")
+	return readDescriptionLines(), nil
+}
+
+// selectMergeDuplicates lets the user toggle the listed issues to merge into the issue at index
+func selectMergeDuplicates(issues []SentryIssue, index int) ([]int, error) {
+	selected := make(map[int]bool)
+
+	for {
+		options := []string{"Merge Selected Issues"}
+		var candidates []int
+		for i, issue := range issues {
+			if i == index {
+				continue
+			}
+			mark := "[ ]"
+			if selected[i] {
+				mark = "[x]"
+			}
+			options = append(options, fmt.Sprintf("%s [%s] %s", mark, issue.ShortID, truncate(issue.Title, 60)))
+			candidates = append(candidates, i)
+		}
+
+		choice, err := ui.SelectFromList(fmt.Sprintf("Select duplicates of %s", issues[index].ShortID), options)
+		if err != nil {
+			return nil, types.ErrNavigateBack
+		}
+
+		if choice == 0 {
+			var duplicates []int
+			for _, i := range candidates {
+				if selected[i] {
+					duplicates = append(duplicates, i)
+				}
+			}
+			return duplicates, nil
+		}
+
+		i := candidates[choice-1]
+		selected[i] = !selected[i]
+	}
+}
+
+// parseMinutes parses a duration such as "30m", "2h", "1d" or "1w" into minutes.
+// An empty value is zero.
+func parseMinutes(value string) (int, error) {
+	value = strings.TrimSpace(strings.ToLower(value))
+	if value == "" {
+		return 0, nil
+	}
+
+	multiplier := 0
+	switch {
+	case strings.HasSuffix(value, "d"):
+		multiplier = 24 * 60
+	case strings.HasSuffix(value, "w"):
+		multiplier = 7 * 24 * 60
+	}
+	if multiplier > 0 {
+		n, err := strconv.Atoi(value[:len(value)-1])
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid duration '%s'", value)
+		}
+		return n * multiplier, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < time.Minute {
+		return 0, fmt.Errorf("invalid duration '%s' (use e.g. 30m, 2h, 1d or 1w)", value)
+	}
+	return int(duration.Minutes()), nil
+}
+
+// validatePositiveInt accepts whole numbers greater than zero
+func validatePositiveInt(s string) error {
+	if n, err := strconv.Atoi(strings.TrimSpace(s)); err != nil || n <= 0 {
+		return fmt.Errorf("enter a number greater than zero")
+	}
+	return nil
+}
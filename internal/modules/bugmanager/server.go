@@ -0,0 +1,418 @@
+package bugmanager
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/kkz6/devtools/internal/config"
+)
+
+const (
+	// maxWebhookBody limits the size of an accepted webhook payload
+	maxWebhookBody = 1 << 20
+	// webhookQueueSize bounds how many deliveries may wait for processing
+	webhookQueueSize = 100
+)
+
+// webhookPayload is the subset of Sentry's issue and event alert payloads used for routing
+type webhookPayload struct {
+	Action string `json:"action"`
+	Data   struct {
+		Issue *SentryIssue `json:"issue"`
+		Event *struct {
+			IssueID json.RawMessage `json:"issue_id"`
+		} `json:"event"`
+	} `json:"data"`
+}
+
+// webhookJob is a verified delivery waiting to be processed
+type webhookJob struct {
+	requestID   string
+	resource    string
+	action      string
+	issueID     string
+	projectSlug string
+}
+
+// webhookTarget is a connection and mapping a Sentry issue routes to
+type webhookTarget struct {
+	connection *config.BugManagerConnection
+	mapping    *config.BugManagerProjectMapping
+}
+
+// webhookServer receives Sentry webhooks and syncs the issues to the connection's tracker
+type webhookServer struct {
+	module    *Module
+	cfg       *config.Config
+	secret    string
+	state     *SyncState
+	jobs      chan webhookJob
+	startedAt time.Time
+
+	// closing is set once shutdown starts; the lock keeps deliveries from being queued
+	// after the worker has drained the queue
+	closingMu sync.RWMutex
+	closing   bool
+}
+
+// serve runs the webhook receiver until interrupted
+func (m *Module) serve(cfg *config.Config, addr, secret string) error {
+	if secret == "" {
+		return fmt.Errorf("a Sentry client secret is required to verify webhook signatures (use --secret, $SENTRY_CLIENT_SECRET or bug_manager.webhook.client_secret)")
+	}
+	if len(cfg.BugManager.Connections) == 0 {
+		return fmt.Errorf("no Sentry connections configured")
+	}
+
+	state, err := LoadSyncState()
+	if err != nil {
+		return err
+	}
+
+	server := &webhookServer{
+		module:    m,
+		cfg:       cfg,
+		secret:    secret,
+		state:     state,
+		jobs:      make(chan webhookJob, webhookQueueSize),
+		startedAt: time.Now(),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", server.handleHealth)
+	mux.HandleFunc("/webhooks/sentry", server.handleSentry)
+
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Sentry expects a response within a second, so deliveries are processed in the background
+	done := make(chan struct{})
+	go func() {
+		server.worker(ctx)
+		close(done)
+	}()
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Shutdown error: %v", err)
+		}
+	}()
+
+	log.Printf("Listening for Sentry webhooks on %s (POST /webhooks/sentry, GET /healthz)", addr)
+	if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("webhook server failed: %w", err)
+	}
+
+	<-done
+	log.Printf("Webhook server stopped")
+	return nil
+}
+
+// handleHealth reports that the server is up along with queue statistics
+func (s *webhookServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	s.state.mu.Lock()
+	synced := len(s.state.Issues)
+	s.state.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":        "ok",
+		"queued":        len(s.jobs),
+		"synced_issues": synced,
+		"uptime":        time.Since(s.startedAt).Round(time.Second).String(),
+	})
+}
+
+// handleSentry verifies and enqueues a Sentry webhook delivery
+func (s *webhookServer) handleSentry(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "failed to read body"})
+		return
+	}
+
+	if !verifySignature(s.secret, body, r.Header.Get("Sentry-Hook-Signature")) {
+		log.Printf("Rejected webhook with invalid signature from %s", r.RemoteAddr)
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid signature"})
+		return
+	}
+
+	resource := r.Header.Get("Sentry-Hook-Resource")
+	if resource != "issue" && resource != "event_alert" {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ignored", "resource": resource})
+		return
+	}
+
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+		return
+	}
+
+	job := webhookJob{
+		requestID: r.Header.Get("Request-ID"),
+		resource:  resource,
+		action:    payload.Action,
+	}
+	if payload.Data.Issue != nil {
+		job.issueID = payload.Data.Issue.ID
+		job.projectSlug = payload.Data.Issue.Project.Slug
+	} else if payload.Data.Event != nil {
+		job.issueID = strings.Trim(string(payload.Data.Event.IssueID), `"`)
+	}
+
+	if job.issueID == "" {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ignored", "reason": "no issue in payload"})
+		return
+	}
+
+	// Deliveries without a request ID are deduplicated by their content instead
+	if job.requestID == "" {
+		job.requestID = fmt.Sprintf("%s:%s:%s:%s", resource, job.action, job.issueID, r.Header.Get("Sentry-Hook-Timestamp"))
+	}
+	s.closingMu.RLock()
+	defer s.closingMu.RUnlock()
+	if s.closing {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "shutting down"})
+		return
+	}
+	if s.state.MarkDelivery(job.requestID) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "duplicate"})
+		return
+	}
+
+	select {
+	case s.jobs <- job:
+		writeJSON(w, http.StatusAccepted, map[string]string{"status": "accepted"})
+	default:
+		s.state.ForgetDelivery(job.requestID)
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "queue full"})
+	}
+}
+
+// worker processes queued deliveries one at a time so updates to the same issue never race.
+// On shutdown, new deliveries are refused and the accepted ones are processed before it returns.
+func (s *webhookServer) worker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			s.closingMu.Lock()
+			s.closing = true
+			s.closingMu.Unlock()
+
+			if pending := len(s.jobs); pending > 0 {
+				log.Printf("Processing %d queued deliveries before shutting down", pending)
+			}
+			for {
+				select {
+				case job := <-s.jobs:
+					s.handleJob(job)
+				default:
+					return
+				}
+			}
+		case job := <-s.jobs:
+			s.handleJob(job)
+		}
+	}
+}
+
+// handleJob processes a delivery and saves the sync state. Failed deliveries are
+// forgotten so that Sentry's redelivery is processed again.
+func (s *webhookServer) handleJob(job webhookJob) {
+	if err := s.process(job); err != nil {
+		log.Printf("Failed to process %s %s for issue %s: %v", job.resource, job.action, job.issueID, err)
+		s.state.ForgetDelivery(job.requestID)
+	}
+	if err := s.state.Save(); err != nil {
+		log.Printf("Failed to save sync state: %v", err)
+	}
+}
+
+// process syncs a single delivery to the connection's issue tracker
+func (s *webhookServer) process(job webhookJob) error {
+	target, sentryClient, issue, err := s.resolveIssue(job)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		log.Printf("No project mapping for issue %s (project %q), skipping", job.issueID, job.projectSlug)
+		return nil
+	}
+
+	tracker, err := s.module.newTracker(s.cfg, target.connection)
+	if err != nil {
+		return fmt.Errorf("connection %q has no valid issue tracker: %w", target.connection.Name, err)
+	}
+
+	// Pick up issues synced by the CLI or a cron sync since the server started
+	if err := s.state.Reload(); err != nil {
+		log.Printf("Could not reload sync state: %v", err)
+	}
+	synced := s.state.Lookup(target.connection.SentryInstance, issue.ID)
+
+	// Status changes are recorded on the existing tracker issue
+	switch job.action {
+	case "resolved", "ignored", "archived", "assigned":
+		if synced == nil {
+			return nil
+		}
+		comment := fmt.Sprintf("Sentry issue [%s](%s) was marked **%s**.", issue.ShortID, issue.Permalink, job.action)
+		if err := tracker.CreateComment(synced.LinearIssueID, comment); err != nil {
+			return err
+		}
+		log.Printf("Commented on %s: %s %s", synced.LinearURL, issue.ShortID, job.action)
+		return nil
+	}
+
+	// New occurrences are reported as comments so triage edits and the filled-in template
+	// sections of the issue are kept; the template only shapes newly created issues
+	if synced != nil {
+		if err := tracker.CreateComment(synced.LinearIssueID, occurrenceComment(*issue, job.action)); err != nil {
+			return err
+		}
+		s.state.Record(target.connection.SentryInstance, *issue, &TrackerIssue{ID: synced.LinearIssueID, URL: synced.LinearURL})
+		log.Printf("Commented on %s: %s occurred again", synced.LinearURL, issue.ShortID)
+		return nil
+	}
+
+	event, err := sentryClient.GetLatestEvent(issue.ID)
+	if err != nil {
+		log.Printf("Could not fetch latest event for %s: %v", issue.ShortID, err)
+		event = nil
+	}
+	tmpl := findTemplate(s.cfg, target.mapping.Template)
+	details := s.module.applyTemplateToBug(s.module.prepareBugDetails(*issue, event), tmpl)
+
+	suspect, err := s.module.newSuspectResolver(s.cfg, target.mapping, sentryClient).find(*issue, event)
+	if err != nil {
+		log.Printf("Could not determine suspect commit for %s: %v", issue.ShortID, err)
+	}
+
+	var assigneeID string
+	assignee, err := s.module.resolveAssignee(tracker, target.mapping, event, s.state)
+	if err != nil {
+		log.Printf("Could not determine assignee for %s: %v", issue.ShortID, err)
+	}
+	details, assignee = s.module.applySuspect(tracker, target.mapping, suspect, details, assignee)
+	if assignee != nil {
+		assigneeID = assignee.User.ID
+	}
+
+	stateID, err := templateStateID(tracker, target.mapping.TeamID(), tmpl)
+	if err != nil {
+		log.Printf("Using the default state for %s: %v", issue.ShortID, err)
+	}
+
+	trackerIssue, err := s.module.createTrackerIssue(tracker, target.mapping, tmpl, *issue, details, stateID, assigneeID)
+	if err != nil {
+		return err
+	}
+	s.state.Record(target.connection.SentryInstance, *issue, trackerIssue)
+	log.Printf("Created %s for %s", trackerIssue.URL, issue.ShortID)
+
+	// Sub-issue failures are logged rather than retried so the parent is not duplicated
+	if _, err := s.module.createSyncSubIssues(tracker, target.mapping, tmpl, trackerIssue, details); err != nil {
+		log.Printf("Sub-issues of %s: %v", trackerIssue.URL, err)
+	}
+	return nil
+}
+
+// occurrenceComment summarizes a new occurrence or regression of an already synced issue
+func occurrenceComment(issue SentryIssue, action string) string {
+	headline := fmt.Sprintf("Sentry issue [%s](%s) occurred again.", issue.ShortID, issue.Permalink)
+	if action == "unresolved" {
+		headline = fmt.Sprintf("Sentry issue [%s](%s) regressed and is unresolved again.", issue.ShortID, issue.Permalink)
+	}
+	return fmt.Sprintf("%s\n\n%s events, %d users affected, last seen %s.",
+		headline, issue.Count, issue.UserCount, issue.LastSeen.Format("2006-01-02 15:04 MST"))
+}
+
+// resolveIssue finds the mapping a delivery belongs to and fetches the full issue.
+// Event alerts only carry the issue ID, so each connection's Sentry instance is tried.
+func (s *webhookServer) resolveIssue(job webhookJob) (*webhookTarget, *SentryClient, *SentryIssue, error) {
+	var lastErr error
+
+	for i := range s.cfg.BugManager.Connections {
+		conn := &s.cfg.BugManager.Connections[i]
+
+		if job.projectSlug != "" && findMapping(conn, job.projectSlug) == nil {
+			continue
+		}
+
+		sentryInstance := s.cfg.Sentry.Instances[conn.SentryInstance]
+		if sentryInstance == nil {
+			continue
+		}
+		sentryClient := NewSentryClient(sentryInstance.APIKey, sentryInstance.BaseURL)
+
+		issue, err := sentryClient.GetIssueDetails(job.issueID)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if mapping := findMapping(conn, issue.Project.Slug); mapping != nil {
+			return &webhookTarget{connection: conn, mapping: mapping}, sentryClient, issue, nil
+		}
+	}
+
+	if lastErr != nil {
+		return nil, nil, nil, fmt.Errorf("failed to fetch issue: %w", lastErr)
+	}
+	return nil, nil, nil, nil
+}
+
+// findMapping returns the mapping of a connection for a Sentry project slug
+func findMapping(conn *config.BugManagerConnection, projectSlug string) *config.BugManagerProjectMapping {
+	for i := range conn.ProjectMappings {
+		if conn.ProjectMappings[i].SentryProject == projectSlug {
+			return &conn.ProjectMappings[i]
+		}
+	}
+	return nil
+}
+
+// verifySignature checks the Sentry-Hook-Signature HMAC-SHA256 of the body
+func verifySignature(secret string, body []byte, signature string) bool {
+	if signature == "" {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// writeJSON writes a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,328 @@
+package bugmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/kkz6/devtools/internal/config"
+)
+
+// maxDeliveries caps how many webhook delivery IDs are remembered
+const maxDeliveries = 1000
+
+// SyncedIssue records a Sentry issue that has been turned into a tracker issue.
+// The JSON keys keep their Linear names so existing state files stay valid.
+type SyncedIssue struct {
+	SentryIssueID string    `json:"sentry_issue_id"`
+	ShortID       string    `json:"short_id"`
+	LinearIssueID string    `json:"linear_issue_id"`
+	LinearURL     string    `json:"linear_url"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// MappingSyncState records the progress of incremental syncs for one project mapping
+type MappingSyncState struct {
+	LastSyncedAt   time.Time `json:"last_synced_at"`          // Start of the last run that completed
+	Cursor         string    `json:"cursor,omitempty"`        // Sentry page cursor where an unfinished run stopped
+	CursorSince    time.Time `json:"cursor_since,omitempty"`  // Start of the unfinished run the cursor belongs to
+	CursorFrom     time.Time `json:"cursor_from,omitempty"`   // lastSeen lower bound of the query the cursor belongs to
+	CursorFailed   int       `json:"cursor_failed,omitempty"` // Issues that failed on earlier pages of the unfinished run
+	LastRunAt      time.Time `json:"last_run_at"`             // Start of the most recent run, successful or not
+	LastRunCreated int       `json:"last_run_created"`        // Issues created by the most recent run
+	LastError      string    `json:"last_error,omitempty"`    // Why the most recent run did not complete
+}
+
+// SyncState is the bug manager state persisted next to the configuration file
+type SyncState struct {
+	Issues     map[string]*SyncedIssue      `json:"issues"`                // Keyed by syncKey
+	Deliveries map[string]time.Time         `json:"deliveries"`            // Processed webhook request IDs
+	Mappings   map[string]*MappingSyncState `json:"mappings,omitempty"`    // Keyed by mappingKey
+	RoundRobin map[string]int               `json:"round_robin,omitempty"` // Next owner rotation per mapping, keyed by rotationKey
+
+	mu        sync.Mutex
+	path      string
+	forgotten map[string]bool // Deliveries forgotten since the last save, dropped when merging
+}
+
+// stateLockTimeout bounds how long Save waits for another process holding the state lock;
+// locks older than staleStateLock are left over from a crashed process and broken
+const (
+	stateLockTimeout = 10 * time.Second
+	staleStateLock   = time.Minute
+)
+
+// getStatePath returns the path of the bug manager state file
+func getStatePath() string {
+	return filepath.Join(filepath.Dir(config.GetConfigPath()), "bugmanager_state.json")
+}
+
+// syncKey identifies a Sentry issue across instances
+func syncKey(sentryInstance, issueID string) string {
+	return fmt.Sprintf("%s/%s", sentryInstance, issueID)
+}
+
+// mappingKey identifies a project mapping of a connection
+func mappingKey(conn *config.BugManagerConnection, mapping *config.BugManagerProjectMapping) string {
+	return fmt.Sprintf("%s/%s/%s", conn.Name, mapping.SentryOrganization, mapping.SentryProject)
+}
+
+// rotationKey identifies the round-robin rotation of a mapping
+func rotationKey(mapping *config.BugManagerProjectMapping) string {
+	return fmt.Sprintf("%s/%s/%s", mapping.SentryOrganization, mapping.SentryProject, mapping.TeamID())
+}
+
+// LoadSyncState loads the sync state, returning an empty state if none exists yet
+func LoadSyncState() (*SyncState, error) {
+	return readSyncState(getStatePath())
+}
+
+// readSyncState reads a state file, returning an empty state if it does not exist
+func readSyncState(path string) (*SyncState, error) {
+	state := &SyncState{
+		Issues:     make(map[string]*SyncedIssue),
+		Deliveries: make(map[string]time.Time),
+		Mappings:   make(map[string]*MappingSyncState),
+		RoundRobin: make(map[string]int),
+		path:       path,
+		forgotten:  make(map[string]bool),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("failed to read sync state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse sync state: %w", err)
+	}
+	if state.Issues == nil {
+		state.Issues = make(map[string]*SyncedIssue)
+	}
+	if state.Deliveries == nil {
+		state.Deliveries = make(map[string]time.Time)
+	}
+	if state.Mappings == nil {
+		state.Mappings = make(map[string]*MappingSyncState)
+	}
+	if state.RoundRobin == nil {
+		state.RoundRobin = make(map[string]int)
+	}
+
+	return state, nil
+}
+
+// Save writes the sync state to disk. Under the state lock, the file is re-read and merged
+// first so that syncs recorded meanwhile by other processes, such as the CLI, batch and
+// cron syncs next to a running webhook server, are kept.
+func (s *SyncState) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	unlock, err := lockStateFile(s.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	disk, err := readSyncState(s.path)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.merge(disk)
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal sync state: %w", err)
+	}
+
+	// Write atomically so a crash never leaves a truncated state file
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write sync state: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("failed to write sync state: %w", err)
+	}
+
+	s.forgotten = make(map[string]bool)
+	return nil
+}
+
+// Reload merges the state file into the in-memory state, picking up issues synced by
+// other processes since the state was loaded
+func (s *SyncState) Reload() error {
+	disk, err := readSyncState(s.path)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.merge(disk)
+	return nil
+}
+
+// merge adds the entries of a state read from disk. Issues and mappings keep the most
+// recently updated copy; deliveries forgotten in memory stay forgotten.
+func (s *SyncState) merge(disk *SyncState) {
+	for key, issue := range disk.Issues {
+		if existing, ok := s.Issues[key]; !ok || issue.UpdatedAt.After(existing.UpdatedAt) {
+			s.Issues[key] = issue
+		}
+	}
+
+	for id, at := range disk.Deliveries {
+		if _, ok := s.Deliveries[id]; !ok && !s.forgotten[id] {
+			s.Deliveries[id] = at
+		}
+	}
+	s.trimDeliveries()
+
+	// Callers hold on to mapping states, so newer ones are copied into the existing entry
+	for key, mapping := range disk.Mappings {
+		existing, ok := s.Mappings[key]
+		switch {
+		case !ok:
+			s.Mappings[key] = mapping
+		case mapping.LastRunAt.After(existing.LastRunAt):
+			*existing = *mapping
+		}
+	}
+
+	// Rotations only move forward, so the furthest one wins
+	for key, next := range disk.RoundRobin {
+		if next > s.RoundRobin[key] {
+			s.RoundRobin[key] = next
+		}
+	}
+}
+
+// lockStateFile takes an exclusive lock on the state file through a lock file next to it
+// and returns the function releasing it
+func lockStateFile(path string) (func(), error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(stateLockTimeout)
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			fmt.Fprintf(file, "%d\n", os.Getpid())
+			file.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock sync state: %w", err)
+		}
+
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > staleStateLock {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("sync state is locked by another process (remove %s if none is running)", lockPath)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// Lookup returns the tracker issue a Sentry issue was synced to, if any
+func (s *SyncState) Lookup(sentryInstance, issueID string) *SyncedIssue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Issues[syncKey(sentryInstance, issueID)]
+}
+
+// Record stores the tracker issue created for a Sentry issue
+func (s *SyncState) Record(sentryInstance string, issue SentryIssue, trackerIssue *TrackerIssue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	key := syncKey(sentryInstance, issue.ID)
+	if existing, ok := s.Issues[key]; ok {
+		existing.LinearIssueID = trackerIssue.ID
+		existing.LinearURL = trackerIssue.URL
+		existing.UpdatedAt = now
+		return
+	}
+
+	s.Issues[key] = &SyncedIssue{
+		SentryIssueID: issue.ID,
+		ShortID:       issue.ShortID,
+		LinearIssueID: trackerIssue.ID,
+		LinearURL:     trackerIssue.URL,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// MarkDelivery records a webhook delivery and reports whether it was seen before
+func (s *SyncState) MarkDelivery(requestID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, seen := s.Deliveries[requestID]; seen {
+		return true
+	}
+	s.Deliveries[requestID] = time.Now()
+	delete(s.forgotten, requestID)
+	s.trimDeliveries()
+	return false
+}
+
+// trimDeliveries forgets the oldest deliveries once the cap is reached
+func (s *SyncState) trimDeliveries() {
+	for len(s.Deliveries) > maxDeliveries {
+		var oldestID string
+		var oldest time.Time
+		for id, at := range s.Deliveries {
+			if oldestID == "" || at.Before(oldest) {
+				oldestID, oldest = id, at
+			}
+		}
+		delete(s.Deliveries, oldestID)
+	}
+}
+
+// ForgetDelivery removes a delivery so that a redelivery is processed again
+func (s *SyncState) ForgetDelivery(requestID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.Deliveries, requestID)
+	s.forgotten[requestID] = true
+}
+
+// NextRotation returns the round-robin position of a mapping and advances it
+func (s *SyncState) NextRotation(mapping *config.BugManagerProjectMapping) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := rotationKey(mapping)
+	next := s.RoundRobin[key]
+	s.RoundRobin[key] = next + 1
+	return next
+}
+
+// MappingState returns the incremental sync state of a mapping, creating it on first use
+func (s *SyncState) MappingState(conn *config.BugManagerConnection, mapping *config.BugManagerProjectMapping) *MappingSyncState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := mappingKey(conn, mapping)
+	if existing, ok := s.Mappings[key]; ok {
+		return existing
+	}
+	state := &MappingSyncState{}
+	s.Mappings[key] = state
+	return state
+}
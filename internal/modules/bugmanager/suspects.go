@@ -0,0 +1,325 @@
+package bugmanager
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kkz6/devtools/internal/config"
+)
+
+// Suspect commit modes of a mapping's assignment settings
+const (
+	suspectDescribe = ""        // Describe the likely author in the issue
+	suspectMention  = "mention" // Also mention them
+	suspectAssign   = "assign"  // Also assign them when no assignment rule matched
+	suspectOff      = "off"     // Skip the lookup
+)
+
+// githubRemote extracts "owner/repo" from a GitHub remote URL
+var githubRemote = regexp.MustCompile(`github\.com[:/]([^/]+/[^/]+?)(?:\.git)?/?$`)
+
+// SuspectCommit is the commit that likely introduced a bug, with its author and pull request
+type SuspectCommit struct {
+	SHA         string
+	Message     string
+	Repo        string // "owner/repo" when the commit is on GitHub
+	URL         string
+	AuthorName  string
+	AuthorEmail string
+	GitHubLogin string
+	PRNumber    int
+	PRTitle     string
+	PRURL       string
+	Source      string // How the commit was found
+}
+
+// suspectResolver finds suspect commits through Sentry, falling back to git blame
+type suspectResolver struct {
+	sentry       *SentryClient       // Nil for sources other than Sentry
+	github       *GitHubIssuesClient // Nil without a GitHub token
+	organization string
+	project      string
+	repoPath     string
+}
+
+// newSuspectResolver creates the suspect commit resolver of a mapping, or nil when the lookup is off
+func (m *Module) newSuspectResolver(cfg *config.Config, mapping *config.BugManagerProjectMapping, source ErrorSource) *suspectResolver {
+	if mapping.Assignment.SuspectCommits == suspectOff {
+		return nil
+	}
+
+	resolver := &suspectResolver{
+		organization: mapping.SentryOrganization,
+		project:      mapping.SentryProject,
+		repoPath:     mapping.Assignment.RepoPath,
+	}
+	if client, ok := source.(*SentryClient); ok {
+		resolver.sentry = client
+	}
+	if cfg.GitHub.Token != "" {
+		resolver.github = NewGitHubIssuesClient(cfg.GitHub.Token)
+	}
+	return resolver
+}
+
+// find returns the commit that most likely introduced an issue, or nil when none is known
+func (r *suspectResolver) find(issue SentryIssue, event *SentryEvent) (*SuspectCommit, error) {
+	if r == nil || event == nil {
+		return nil, nil
+	}
+
+	var suspect *SuspectCommit
+	var lookupErr error
+	if r.sentry != nil {
+		suspect, lookupErr = r.fromSentry(issue, event)
+	}
+	if suspect == nil && r.repoPath != "" {
+		blamed, err := r.fromBlame(event)
+		if err != nil && lookupErr == nil {
+			lookupErr = err
+		}
+		suspect = blamed
+	}
+	if suspect == nil {
+		return nil, lookupErr
+	}
+
+	r.enrich(suspect)
+	return suspect, nil
+}
+
+// fromSentry takes the most likely commit of the event's Sentry committers
+func (r *suspectResolver) fromSentry(issue SentryIssue, event *SentryEvent) (*SuspectCommit, error) {
+	eventID := event.EventID
+	if eventID == "" {
+		eventID = event.ID
+	}
+	project := issue.Project.Slug
+	if project == "" {
+		project = r.project
+	}
+
+	committers, err := r.sentry.GetCommitters(r.organization, project, eventID)
+	if err != nil {
+		return nil, err
+	}
+	for _, committer := range committers {
+		if len(committer.Commits) == 0 {
+			continue
+		}
+		commit := committer.Commits[0]
+		suspect := &SuspectCommit{
+			SHA:         commit.ID,
+			Message:     commit.Message,
+			Repo:        commit.Repository.Name,
+			AuthorName:  committer.Author.Name,
+			AuthorEmail: committer.Author.Email,
+			Source:      "Sentry suspect commits",
+		}
+		if commit.PullRequest != nil {
+			suspect.PRTitle = commit.PullRequest.Title
+			suspect.PRURL = commit.PullRequest.ExternalURL
+			suspect.PRNumber, _ = strconv.Atoi(commit.PullRequest.ID)
+		}
+		return suspect, nil
+	}
+	return nil, nil
+}
+
+// fromBlame blames the line of the top in-app frame in the mapping's local repository
+func (r *suspectResolver) fromBlame(event *SentryEvent) (*SuspectCommit, error) {
+	frame := topInAppFrame(event)
+	if frame == nil || frame.LineNo <= 0 {
+		return nil, nil
+	}
+
+	repoPath := expandHome(r.repoPath)
+	file := repoRelativePath(repoPath, frame.Filename)
+	cmd := exec.Command("git", "-C", repoPath, "blame", "--porcelain",
+		"-L", fmt.Sprintf("%d,%d", frame.LineNo, frame.LineNo), "--", filepath.FromSlash(file))
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git blame of %s:%d failed: %w", file, frame.LineNo, err)
+	}
+
+	suspect := &SuspectCommit{Source: fmt.Sprintf("git blame of `%s:%d`", file, frame.LineNo)}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case suspect.SHA == "":
+			suspect.SHA = strings.Fields(line)[0]
+		case strings.HasPrefix(line, "author "):
+			suspect.AuthorName = strings.TrimPrefix(line, "author ")
+		case strings.HasPrefix(line, "author-mail "):
+			suspect.AuthorEmail = strings.Trim(strings.TrimPrefix(line, "author-mail "), "<>")
+		case strings.HasPrefix(line, "summary "):
+			suspect.Message = strings.TrimPrefix(line, "summary ")
+		}
+	}
+	if strings.Trim(suspect.SHA, "0") == "" {
+		return nil, nil // The line has uncommitted changes
+	}
+
+	if remote, err := exec.Command("git", "-C", repoPath, "remote", "get-url", "origin").Output(); err == nil {
+		if match := githubRemote.FindStringSubmatch(strings.TrimSpace(string(remote))); match != nil {
+			suspect.Repo = match[1]
+		}
+	}
+	return suspect, nil
+}
+
+// enrich resolves the GitHub author and pull request of a suspect commit.
+// Lookup failures leave the commit as Sentry or git reported it.
+func (r *suspectResolver) enrich(suspect *SuspectCommit) {
+	if !strings.Contains(suspect.Repo, "/") {
+		return
+	}
+	suspect.URL = fmt.Sprintf("https://github.com/%s/commit/%s", suspect.Repo, suspect.SHA)
+	if r.github == nil {
+		return
+	}
+
+	if commit, err := r.github.GetCommit(suspect.Repo, suspect.SHA); err == nil {
+		if commit.Author != nil {
+			suspect.GitHubLogin = commit.Author.Login
+		}
+		if suspect.AuthorName == "" {
+			suspect.AuthorName = commit.Commit.Author.Name
+		}
+		if suspect.AuthorEmail == "" {
+			suspect.AuthorEmail = commit.Commit.Author.Email
+		}
+		if suspect.Message == "" {
+			suspect.Message = commit.Commit.Message
+		}
+	}
+
+	pulls, err := r.github.GetCommitPullRequests(suspect.Repo, suspect.SHA)
+	if err != nil || len(pulls) == 0 {
+		return
+	}
+	pull := pulls[0]
+	for _, p := range pulls {
+		if p.MergedAt != nil {
+			pull = p
+			break
+		}
+	}
+	suspect.PRNumber = pull.Number
+	suspect.PRTitle = pull.Title
+	suspect.PRURL = pull.HTMLURL
+	if suspect.GitHubLogin == "" {
+		suspect.GitHubLogin = pull.User.Login
+	}
+}
+
+// applySuspect adds the "Likely Introduced By" section to a bug and, depending on the
+// mapping's mode, mentions the author or assigns them when no other rule chose an assignee
+func (m *Module) applySuspect(tracker IssueTracker, mapping *config.BugManagerProjectMapping,
+	suspect *SuspectCommit, details BugDetails, assignee *AssignmentResult) (BugDetails, *AssignmentResult) {
+
+	if suspect == nil {
+		return details, assignee
+	}
+
+	mode := mapping.Assignment.SuspectCommits
+	var user *TrackerUser
+	if mode == suspectMention || mode == suspectAssign {
+		if users, err := tracker.GetUsers(mapping.TeamID()); err == nil {
+			for _, handle := range []string{suspect.GitHubLogin, suspect.AuthorEmail, suspect.AuthorName} {
+				if user = resolveTrackerUser(users, handle); user != nil {
+					break
+				}
+			}
+		}
+	}
+
+	// GitHub notifies @login mentions; Linear only mentions users through their profile URL
+	mention := ""
+	if mode == suspectMention {
+		switch {
+		case isGitHubTracker(tracker) && suspect.GitHubLogin != "":
+			mention = "@" + suspect.GitHubLogin
+		case !isGitHubTracker(tracker) && user != nil && user.URL != "":
+			mention = user.URL
+		}
+	}
+	if mode == suspectAssign && assignee == nil && user != nil {
+		reason := fmt.Sprintf("likely introduced the bug in %s", shortSHA(suspect.SHA))
+		if suspect.PRNumber > 0 {
+			reason = fmt.Sprintf("likely introduced the bug in #%d", suspect.PRNumber)
+		}
+		assignee = &AssignmentResult{User: user, Owner: suspect.AuthorEmail, Reason: reason}
+	}
+
+	details.Description = insertBeforeFooter(details.Description, suspectSection(suspect, mention))
+	return details, assignee
+}
+
+// suspectSection renders the "Likely Introduced By" section of a bug description
+func suspectSection(suspect *SuspectCommit, mention string) string {
+	var b strings.Builder
+	b.WriteString("## Likely Introduced By\n\n")
+
+	author := suspect.AuthorName
+	if suspect.GitHubLogin != "" {
+		author = fmt.Sprintf("%s (%s)", suspect.AuthorName, suspect.GitHubLogin)
+		if suspect.AuthorName == "" {
+			author = suspect.GitHubLogin
+		}
+	}
+	if mention != "" {
+		author = fmt.Sprintf("%s — %s", mention, author)
+	}
+	if author != "" {
+		b.WriteString(fmt.Sprintf("**Author:** %s\n", author))
+	}
+
+	if suspect.PRURL != "" {
+		title := suspect.PRTitle
+		if suspect.PRNumber > 0 {
+			title = fmt.Sprintf("#%d %s", suspect.PRNumber, suspect.PRTitle)
+		}
+		b.WriteString(fmt.Sprintf("**Pull Request:** [%s](%s)\n", strings.TrimSpace(title), suspect.PRURL))
+	}
+
+	commit := fmt.Sprintf("`%s`", shortSHA(suspect.SHA))
+	if suspect.URL != "" {
+		commit = fmt.Sprintf("[%s](%s)", commit, suspect.URL)
+	}
+	b.WriteString(fmt.Sprintf("**Commit:** %s %s\n", commit, firstLine(suspect.Message)))
+	b.WriteString(fmt.Sprintf("\n_Found via %s._\n", suspect.Source))
+	return b.String()
+}
+
+// insertBeforeFooter inserts a section before the "View in Sentry" footer, or appends it
+func insertBeforeFooter(description, section string) string {
+	if i := strings.LastIndex(description, "\n---\n\n[View in "); i != -1 {
+		return strings.TrimRight(description[:i], "\n") + "\n\n" + section + description[i:]
+	}
+	return strings.TrimRight(description, "\n") + "\n\n" + section
+}
+
+// isGitHubTracker reports whether issues are filed in GitHub, looking through tracker wrappers
+func isGitHubTracker(tracker IssueTracker) bool {
+	if cached, ok := tracker.(*cachedTracker); ok {
+		tracker = cached.IssueTracker
+	}
+	_, ok := tracker.(*GitHubIssuesClient)
+	return ok
+}
+
+// shortSHA abbreviates a commit SHA
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,223 @@
+package bugmanager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kkz6/devtools/internal/config"
+	"github.com/kkz6/devtools/internal/types"
+	"github.com/kkz6/devtools/internal/ui"
+)
+
+// findTemplate returns the issue template with the given name, or nil
+func findTemplate(cfg *config.Config, name string) *config.BugManagerTemplate {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil
+	}
+	for i := range cfg.BugManager.Templates {
+		if strings.EqualFold(cfg.BugManager.Templates[i].Name, name) {
+			return &cfg.BugManager.Templates[i]
+		}
+	}
+	return nil
+}
+
+// selectTemplate lets the user pick an issue template.
+// It returns nil without prompting when no templates are configured.
+func (m *Module) selectTemplate(cfg *config.Config, title string) (*config.BugManagerTemplate, error) {
+	if len(cfg.BugManager.Templates) == 0 {
+		return nil, nil
+	}
+
+	options := []string{"No Template"}
+	for _, tmpl := range cfg.BugManager.Templates {
+		option := tmpl.Name
+		if len(tmpl.SubIssues) > 0 {
+			option = fmt.Sprintf("%s (%d sub-issues)", tmpl.Name, len(tmpl.SubIssues))
+		}
+		options = append(options, option)
+	}
+
+	choice, err := ui.SelectFromList(title, options)
+	if err != nil {
+		if err.Error() == "cancelled" {
+			return nil, types.ErrNavigateBack
+		}
+		return nil, err
+	}
+
+	if choice == 0 {
+		return nil, nil
+	}
+	return &cfg.BugManager.Templates[choice-1], nil
+}
+
+// applyTemplate fills the fields a draft leaves empty with the template defaults.
+// Template labels are always added to the draft's labels.
+func applyTemplate(draft IssueDraft, tmpl *config.BugManagerTemplate) IssueDraft {
+	if tmpl == nil {
+		return draft
+	}
+
+	draft.Template = tmpl.Name
+	if draft.Type == "" {
+		draft.Type = tmpl.Type
+	}
+	if draft.Priority == "" {
+		draft.Priority = tmpl.Priority
+	}
+	if draft.State == "" {
+		draft.State = tmpl.State
+	}
+	if strings.TrimSpace(draft.Description) == "" {
+		draft.Description = strings.TrimSpace(tmpl.Description)
+	}
+	draft.Labels = mergeLabels(draft.Labels, tmpl.Labels)
+	return draft
+}
+
+// templateTitle prefixes a title with the template's title prefix, or with the
+// issue type when the template has none
+func templateTitle(title, issueType string, tmpl *config.BugManagerTemplate) string {
+	prefix := ""
+	if tmpl != nil && tmpl.TitlePrefix != "" {
+		prefix = tmpl.TitlePrefix
+	} else if issueType != "" {
+		if strings.HasPrefix(strings.ToLower(title), strings.ToLower(issueType)) {
+			return title
+		}
+		prefix = fmt.Sprintf("[%s] ", issueType)
+	}
+
+	if prefix == "" || strings.HasPrefix(strings.ToLower(title), strings.ToLower(strings.TrimSpace(prefix))) {
+		return title
+	}
+	return prefix + title
+}
+
+// applyTemplateToBug applies a mapping's template to a prepared Sentry bug.
+// The description skeleton follows the Sentry report so the details stay on top.
+func (m *Module) applyTemplateToBug(details BugDetails, tmpl *config.BugManagerTemplate) BugDetails {
+	if tmpl == nil {
+		return details
+	}
+
+	details.Title = templateTitle(details.Title, "", tmpl)
+	if skeleton := strings.TrimSpace(tmpl.Description); skeleton != "" {
+		details.Description = strings.TrimRight(details.Description, "\n") + "\n\n" + skeleton + "\n"
+	}
+	if tmpl.Priority != "" {
+		if priority, err := parsePriority(tmpl.Priority); err == nil {
+			details.Priority = priority
+		}
+	}
+	return details
+}
+
+// templateStateID looks up the workflow state named by a template
+func templateStateID(tracker IssueTracker, teamID string, tmpl *config.BugManagerTemplate) (string, error) {
+	if tmpl == nil || tmpl.State == "" {
+		return "", nil
+	}
+
+	states, err := tracker.GetWorkflowStates(teamID)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch workflow states: %w", err)
+	}
+	if stateID := findStateID(states, tmpl.State); stateID != "" {
+		return stateID, nil
+	}
+	return "", fmt.Errorf("state '%s' of template '%s' not found", tmpl.State, tmpl.Name)
+}
+
+// findStateID returns the ID of the state with the given name or ID, or ""
+func findStateID(states []TrackerState, name string) string {
+	for _, state := range states {
+		if matchesName(name, state.ID, state.Name) {
+			return state.ID
+		}
+	}
+	return ""
+}
+
+// createSubIssues creates the template's sub-issues under a parent issue.
+// Every sub-issue is attempted; failures are returned together.
+func (m *Module) createSubIssues(tracker IssueTracker, parent *TrackerIssue, parentInput TrackerIssueInput,
+	tmpl *config.BugManagerTemplate) ([]*TrackerIssue, error) {
+
+	if tmpl == nil || len(tmpl.SubIssues) == 0 {
+		return nil, nil
+	}
+
+	var created []*TrackerIssue
+	var failures []string
+	for _, sub := range tmpl.SubIssues {
+		priority := parentInput.Priority
+		if sub.Priority != "" {
+			p, err := parsePriority(sub.Priority)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", sub.Title, err))
+				continue
+			}
+			priority = p
+		}
+
+		issue, err := tracker.CreateIssue(TrackerIssueInput{
+			TeamID:      parentInput.TeamID,
+			ProjectID:   parentInput.ProjectID,
+			Title:       sub.Title,
+			Description: strings.TrimSpace(sub.Description),
+			LabelIDs:    m.ensureLabels(tracker, parentInput.TeamID, sub.Labels),
+			Priority:    priority,
+			ParentID:    parent.ID,
+		})
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", sub.Title, err))
+			continue
+		}
+		created = append(created, issue)
+	}
+
+	if len(failures) > 0 {
+		return created, fmt.Errorf("failed to create %d of %d sub-issues:\n  %s",
+			len(failures), len(tmpl.SubIssues), strings.Join(failures, "\n  "))
+	}
+	return created, nil
+}
+
+// reportSubIssues creates a template's sub-issues and reports the outcome to the user
+func (m *Module) reportSubIssues(tracker IssueTracker, parent *TrackerIssue, parentInput TrackerIssueInput,
+	tmpl *config.BugManagerTemplate) {
+
+	if tmpl == nil || len(tmpl.SubIssues) == 0 {
+		return
+	}
+
+	ui.ShowInfo(fmt.Sprintf("Creating %d sub-issues...", len(tmpl.SubIssues)))
+	created, err := m.createSubIssues(tracker, parent, parentInput, tmpl)
+	for _, issue := range created {
+		fmt.Printf("  ✓ %s %s\n", issue.Identifier, issue.Title)
+	}
+	if err != nil {
+		ui.ShowWarning(err.Error())
+	}
+}
+
+// mergeLabels appends the labels of extra missing from labels, ignoring case
+func mergeLabels(labels, extra []string) []string {
+	merged := append([]string{}, labels...)
+	for _, label := range extra {
+		found := false
+		for _, existing := range merged {
+			if strings.EqualFold(existing, label) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, label)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,301 @@
+package bugmanager
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kkz6/devtools/internal/config"
+	"github.com/kkz6/devtools/internal/types"
+	"github.com/kkz6/devtools/internal/ui"
+)
+
+// Issue trackers a connection can target
+const (
+	TrackerLinear = "linear"
+	TrackerGitHub = "github"
+)
+
+// TrackerTeam is a team (Linear) or repository (GitHub) issues are filed in
+type TrackerTeam struct {
+	ID   string
+	Name string
+	Key  string
+}
+
+// TrackerProject is a project (Linear) or milestone (GitHub) within a team
+type TrackerProject struct {
+	ID          string
+	Name        string
+	Description string
+	State       string
+}
+
+// TrackerState is a workflow state an issue can be in
+type TrackerState struct {
+	ID    string
+	Name  string
+	Type  string // triage, backlog, unstarted, started, completed, canceled
+	Color string
+}
+
+// TrackerUser is a user issues can be assigned to
+type TrackerUser struct {
+	ID          string
+	Name        string
+	DisplayName string
+	Email       string
+	Active      bool
+	URL         string // Profile URL, used to mention the user where the tracker supports it
+}
+
+// TrackerIssue is an issue as returned by any tracker
+type TrackerIssue struct {
+	ID         string
+	Identifier string // Human readable key, e.g. ENG-123 or owner/repo#45
+	Title      string
+	State      string
+	Priority   int
+	Labels     []string
+	URL        string
+}
+
+// TrackerIssueInput holds the fields used to create an issue
+type TrackerIssueInput struct {
+	TeamID      string
+	ProjectID   string
+	Title       string
+	Description string
+	LabelIDs    []string
+	Priority    int
+	StateID     string
+	AssigneeID  string
+	ParentID    string
+}
+
+// TrackerIssueUpdateInput holds the fields that can be changed on an issue.
+// Nil fields are left untouched.
+type TrackerIssueUpdateInput struct {
+	Title       *string
+	Description *string
+	Priority    *int
+	StateID     *string
+	AssigneeID  *string
+	LabelIDs    []string
+}
+
+// IssueTracker is implemented by every issue tracker the bug manager can file issues in
+type IssueTracker interface {
+	// Name returns a display name such as "Linear (Work)" or "GitHub Issues"
+	Name() string
+	GetTeams() ([]TrackerTeam, error)
+	GetProjects(teamID string) ([]TrackerProject, error)
+	GetWorkflowStates(teamID string) ([]TrackerState, error)
+	GetUsers(teamID string) ([]TrackerUser, error)
+	// GetOrCreateLabel returns the ID to pass in TrackerIssueInput.LabelIDs
+	GetOrCreateLabel(teamID, name, color string) (string, error)
+	CreateIssue(input TrackerIssueInput) (*TrackerIssue, error)
+	UpdateIssue(issueID string, input TrackerIssueUpdateInput) (*TrackerIssue, error)
+	SearchIssues(teamID, query string) ([]TrackerIssue, error)
+	CreateComment(issueID, body string) error
+}
+
+// linearTracker adapts LinearClient to the IssueTracker interface
+type linearTracker struct {
+	client *LinearClient
+	name   string
+}
+
+// NewLinearTracker creates an IssueTracker backed by a Linear instance
+func NewLinearTracker(instance *config.LinearInstance) IssueTracker {
+	return &linearTracker{
+		client: NewLinearClient(instance.APIKey),
+		name:   fmt.Sprintf("Linear (%s)", instance.Name),
+	}
+}
+
+func (t *linearTracker) Name() string {
+	return t.name
+}
+
+func (t *linearTracker) GetTeams() ([]TrackerTeam, error) {
+	teams, err := t.client.GetTeams()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]TrackerTeam, len(teams))
+	for i, team := range teams {
+		result[i] = TrackerTeam(team)
+	}
+	return result, nil
+}
+
+func (t *linearTracker) GetProjects(teamID string) ([]TrackerProject, error) {
+	projects, err := t.client.GetProjects(teamID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]TrackerProject, len(projects))
+	for i, project := range projects {
+		result[i] = TrackerProject(project)
+	}
+	return result, nil
+}
+
+func (t *linearTracker) GetWorkflowStates(teamID string) ([]TrackerState, error) {
+	states, err := t.client.GetWorkflowStates(teamID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]TrackerState, len(states))
+	for i, state := range states {
+		result[i] = TrackerState(state)
+	}
+	return result, nil
+}
+
+// GetUsers returns all workspace members; Linear users are not scoped to a team
+func (t *linearTracker) GetUsers(teamID string) ([]TrackerUser, error) {
+	users, err := t.client.GetUsers()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]TrackerUser, len(users))
+	for i, user := range users {
+		result[i] = TrackerUser(user)
+	}
+	return result, nil
+}
+
+func (t *linearTracker) GetOrCreateLabel(teamID, name, color string) (string, error) {
+	return t.client.GetOrCreateLabel(teamID, name, color)
+}
+
+func (t *linearTracker) CreateIssue(input TrackerIssueInput) (*TrackerIssue, error) {
+	issue, err := t.client.CreateIssue(LinearIssueInput(input))
+	if err != nil {
+		return nil, err
+	}
+	return issue.toTrackerIssue(), nil
+}
+
+func (t *linearTracker) UpdateIssue(issueID string, input TrackerIssueUpdateInput) (*TrackerIssue, error) {
+	issue, err := t.client.UpdateIssue(issueID, LinearIssueUpdateInput(input))
+	if err != nil {
+		return nil, err
+	}
+	return issue.toTrackerIssue(), nil
+}
+
+func (t *linearTracker) SearchIssues(teamID, query string) ([]TrackerIssue, error) {
+	issues, err := t.client.SearchIssues(teamID, query)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]TrackerIssue, len(issues))
+	for i := range issues {
+		result[i] = *issues[i].toTrackerIssue()
+	}
+	return result, nil
+}
+
+func (t *linearTracker) CreateComment(issueID, body string) error {
+	return t.client.CreateComment(issueID, body)
+}
+
+// toTrackerIssue converts a Linear issue to the tracker-neutral form
+func (i *LinearIssue) toTrackerIssue() *TrackerIssue {
+	labels := make([]string, len(i.Labels.Nodes))
+	for j, label := range i.Labels.Nodes {
+		labels[j] = label.Name
+	}
+	return &TrackerIssue{
+		ID:         i.ID,
+		Identifier: i.Identifier,
+		Title:      i.Title,
+		State:      i.State.Name,
+		Priority:   i.Priority,
+		Labels:     labels,
+		URL:        i.URL,
+	}
+}
+
+// newTracker creates the issue tracker a connection files issues in
+func (m *Module) newTracker(cfg *config.Config, conn *config.BugManagerConnection) (IssueTracker, error) {
+	switch conn.Tracker {
+	case "", TrackerLinear:
+		instance := cfg.Linear.Instances[conn.LinearInstance]
+		if instance == nil {
+			return nil, fmt.Errorf("Linear instance '%s' not found", conn.LinearInstance)
+		}
+		return NewLinearTracker(instance), nil
+	case TrackerGitHub:
+		if cfg.GitHub.Token == "" {
+			return nil, fmt.Errorf("GitHub token is not configured")
+		}
+		return NewGitHubIssuesClient(cfg.GitHub.Token), nil
+	default:
+		return nil, fmt.Errorf("unknown issue tracker '%s'", conn.Tracker)
+	}
+}
+
+// trackerDisplayName describes the tracker a connection targets without contacting it
+func trackerDisplayName(cfg *config.Config, conn *config.BugManagerConnection) string {
+	if conn.Tracker == TrackerGitHub {
+		return "GitHub Issues"
+	}
+	if linear, ok := cfg.Linear.Instances[conn.LinearInstance]; ok {
+		return linear.Name
+	}
+	return "Unknown"
+}
+
+// trackerTerms returns how a tracker calls its teams and projects
+func trackerTerms(tracker IssueTracker) (team, project string) {
+	if isGitHubTracker(tracker) {
+		return "repository", "milestone"
+	}
+	return "team", "project"
+}
+
+// selectTracker lets the user pick a Linear instance or GitHub Issues.
+// It returns the tracker kind and, for Linear, the instance key.
+func (m *Module) selectTracker(cfg *config.Config, title string) (string, string, error) {
+	linearKeys := make([]string, 0, len(cfg.Linear.Instances))
+	for key := range cfg.Linear.Instances {
+		linearKeys = append(linearKeys, key)
+	}
+	sort.Strings(linearKeys)
+
+	options := make([]string, 0, len(linearKeys)+1)
+	for _, key := range linearKeys {
+		options = append(options, fmt.Sprintf("Linear: %s (%s)", cfg.Linear.Instances[key].Name, key))
+	}
+	if cfg.GitHub.Token != "" {
+		options = append(options, "GitHub Issues")
+	}
+
+	if len(options) == 0 {
+		ui.ShowError("No issue trackers configured. Please add a Linear instance or a GitHub token first.")
+		return "", "", types.ErrNavigateBack
+	}
+	// Skip the prompt when there is nothing to choose
+	if len(options) == 1 {
+		if len(linearKeys) == 1 {
+			return TrackerLinear, linearKeys[0], nil
+		}
+		return TrackerGitHub, "", nil
+	}
+
+	choice, err := ui.SelectFromList(title, options)
+	if err != nil {
+		if err.Error() == "cancelled" {
+			return "", "", types.ErrNavigateBack
+		}
+		return "", "", err
+	}
+
+	if choice < len(linearKeys) {
+		return TrackerLinear, linearKeys[choice], nil
+	}
+	return TrackerGitHub, "", nil
+}
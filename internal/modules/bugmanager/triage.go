@@ -0,0 +1,613 @@
+package bugmanager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kkz6/devtools/internal/config"
+	"github.com/kkz6/devtools/internal/types"
+	"github.com/kkz6/devtools/internal/ui"
+)
+
+// triagePageSize is how many issues are fetched per page in the triage console
+const triagePageSize = 25
+
+// triageFilter holds the active filters along with their display names
+type triageFilter struct {
+	LinearIssueFilter
+	stateName    string
+	labelName    string
+	assigneeName string
+}
+
+// describe summarizes the active filters
+func (f *triageFilter) describe(m *Module) string {
+	var parts []string
+	if f.stateName != "" {
+		parts = append(parts, "state="+f.stateName)
+	}
+	if f.labelName != "" {
+		parts = append(parts, "label="+f.labelName)
+	}
+	if f.assigneeName != "" {
+		parts = append(parts, "assignee="+f.assigneeName)
+	}
+	if f.Priority != nil {
+		parts = append(parts, "priority="+m.getPriorityName(*f.Priority))
+	}
+	if f.Search != "" {
+		parts = append(parts, fmt.Sprintf("search=%q", f.Search))
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// triageLookups lazily loads and caches the states, labels and users of a team
+type triageLookups struct {
+	client *LinearClient
+	teamID string
+	states []LinearWorkflowState
+	labels []LinearLabel
+	users  []LinearUser
+}
+
+func (l *triageLookups) getStates() ([]LinearWorkflowState, error) {
+	if l.states == nil {
+		states, err := l.client.GetWorkflowStates(l.teamID)
+		if err != nil {
+			return nil, err
+		}
+		l.states = states
+	}
+	return l.states, nil
+}
+
+func (l *triageLookups) getLabels() ([]LinearLabel, error) {
+	if l.labels == nil {
+		labels, err := l.client.GetLabels(l.teamID)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(labels, func(i, j int) bool {
+			return strings.ToLower(labels[i].Name) < strings.ToLower(labels[j].Name)
+		})
+		l.labels = labels
+	}
+	return l.labels, nil
+}
+
+func (l *triageLookups) getUsers() ([]LinearUser, error) {
+	if l.users == nil {
+		users, err := l.client.GetUsers()
+		if err != nil {
+			return nil, err
+		}
+		active := make([]LinearUser, 0, len(users))
+		for _, user := range users {
+			if user.Active {
+				active = append(active, user)
+			}
+		}
+		sort.Slice(active, func(i, j int) bool {
+			return strings.ToLower(active[i].DisplayName) < strings.ToLower(active[j].DisplayName)
+		})
+		l.users = active
+	}
+	return l.users, nil
+}
+
+// selectLinearInstance picks a Linear instance, skipping the prompt when only one exists
+func (m *Module) selectLinearInstance(cfg *config.Config) (*config.LinearInstance, error) {
+	if len(cfg.Linear.Instances) == 0 {
+		ui.ShowError("No Linear instances configured. Please add a Linear instance first.")
+		return nil, types.ErrNavigateBack
+	}
+
+	keys := make([]string, 0, len(cfg.Linear.Instances))
+	for key := range cfg.Linear.Instances {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if len(keys) == 1 {
+		return cfg.Linear.Instances[keys[0]], nil
+	}
+
+	options := make([]string, len(keys))
+	for i, key := range keys {
+		options[i] = fmt.Sprintf("%s (%s)", cfg.Linear.Instances[key].Name, key)
+	}
+
+	choice, err := ui.SelectFromList("Select Linear instance", options)
+	if err != nil {
+		if err.Error() == "cancelled" {
+			return nil, types.ErrNavigateBack
+		}
+		return nil, err
+	}
+
+	return cfg.Linear.Instances[keys[choice]], nil
+}
+
+// triageIssues lists Linear issues of a team or project and lets the user act on them
+func (m *Module) triageIssues(cfg *config.Config) error {
+	instance, err := m.selectLinearInstance(cfg)
+	if err != nil {
+		return err
+	}
+	client := NewLinearClient(instance.APIKey)
+
+	// Select team
+	ui.ShowInfo("Fetching Linear teams...")
+	teams, err := client.GetTeams()
+	if err != nil {
+		ui.ShowError(fmt.Sprintf("Failed to fetch teams: %v", err))
+		return nil
+	}
+	if len(teams) == 0 {
+		ui.ShowWarning("No teams found in Linear.")
+		return nil
+	}
+
+	teamOptions := make([]string, len(teams))
+	for i, team := range teams {
+		teamOptions[i] = fmt.Sprintf("%s (%s)", team.Name, team.Key)
+	}
+
+	teamChoice, err := ui.SelectFromList("Select team", teamOptions)
+	if err != nil {
+		if err.Error() == "cancelled" {
+			return types.ErrNavigateBack
+		}
+		return err
+	}
+	selectedTeam := teams[teamChoice]
+	scope := selectedTeam.Name
+
+	// Select project (optional)
+	filter := triageFilter{LinearIssueFilter: LinearIssueFilter{TeamID: selectedTeam.ID}}
+
+	projects, err := client.GetProjects(selectedTeam.ID)
+	if err != nil {
+		ui.ShowWarning(fmt.Sprintf("Could not fetch projects: %v", err))
+	} else if len(projects) > 0 {
+		projectOptions := []string{"All Projects"}
+		for _, project := range projects {
+			projectOptions = append(projectOptions, project.Name)
+		}
+
+		projectChoice, err := ui.SelectFromList("Select project (optional)", projectOptions)
+		if err != nil {
+			if err.Error() == "cancelled" {
+				return types.ErrNavigateBack
+			}
+			return err
+		}
+		if projectChoice > 0 {
+			filter.ProjectID = projects[projectChoice-1].ID
+			scope = fmt.Sprintf("%s / %s", selectedTeam.Name, projects[projectChoice-1].Name)
+		}
+	}
+
+	lookups := &triageLookups{client: client, teamID: selectedTeam.ID}
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("205")).
+		MarginBottom(1)
+
+	var issues []LinearIssue
+	var pageInfo LinearPageInfo
+	reload := true
+
+	for {
+		if reload {
+			ui.ShowInfo("Fetching issues...")
+			issues, pageInfo, err = client.ListIssues(filter.LinearIssueFilter, triagePageSize, "")
+			if err != nil {
+				ui.ShowError(fmt.Sprintf("Failed to fetch issues: %v", err))
+				return nil
+			}
+			reload = false
+		}
+
+		fmt.Println(titleStyle.Render(fmt.Sprintf("Triage: %s", scope)))
+		fmt.Printf("Filters: %s\n", filter.describe(m))
+
+		options := make([]string, 0, len(issues)+6)
+		for _, issue := range issues {
+			options = append(options, m.formatTriageIssue(issue))
+		}
+		if pageInfo.HasNextPage {
+			options = append(options, "Load more issues...")
+		}
+		options = append(options, "Search", "Filter", "Clear Filters", "Refresh", "Back")
+
+		choice, err := ui.SelectFromList(fmt.Sprintf("%d issues", len(issues)), options)
+		if err != nil {
+			if err.Error() == "cancelled" {
+				return types.ErrNavigateBack
+			}
+			return err
+		}
+
+		if choice < len(issues) {
+			if err := m.triageIssue(client, lookups, issues[choice].ID); err != nil && err != types.ErrNavigateBack {
+				return err
+			}
+			reload = true
+			continue
+		}
+
+		switch options[choice] {
+		case "Load more issues...":
+			ui.ShowInfo("Fetching next page...")
+			page, next, err := client.ListIssues(filter.LinearIssueFilter, triagePageSize, pageInfo.EndCursor)
+			if err != nil {
+				ui.ShowError(fmt.Sprintf("Failed to fetch more issues: %v", err))
+				continue
+			}
+			issues = append(issues, page...)
+			pageInfo = next
+
+		case "Search":
+			search, err := ui.GetInput("Search title and description (empty to clear)", filter.Search, false, nil)
+			if err != nil {
+				if err.Error() == "cancelled" {
+					continue
+				}
+				return err
+			}
+			filter.Search = strings.TrimSpace(search)
+			reload = true
+
+		case "Filter":
+			if err := m.editTriageFilter(&filter, lookups); err != nil && err != types.ErrNavigateBack {
+				return err
+			}
+			reload = true
+
+		case "Clear Filters":
+			filter = triageFilter{LinearIssueFilter: LinearIssueFilter{
+				TeamID:    filter.TeamID,
+				ProjectID: filter.ProjectID,
+			}}
+			reload = true
+
+		case "Refresh":
+			reload = true
+
+		case "Back":
+			return types.ErrNavigateBack
+		}
+	}
+}
+
+// formatTriageIssue renders an issue as a single list line
+func (m *Module) formatTriageIssue(issue LinearIssue) string {
+	assignee := "Unassigned"
+	if issue.Assignee != nil {
+		assignee = issue.Assignee.DisplayName
+	}
+	return fmt.Sprintf("[%s] %s · %s · %s · %s",
+		issue.Identifier, issue.Title, issue.State.Name, m.getPriorityName(issue.Priority), assignee)
+}
+
+// editTriageFilter changes the state, label, assignee and priority filters
+func (m *Module) editTriageFilter(filter *triageFilter, lookups *triageLookups) error {
+	for {
+		priority := "any"
+		if filter.Priority != nil {
+			priority = m.getPriorityName(*filter.Priority)
+		}
+
+		options := []string{
+			fmt.Sprintf("State (current: %s)", valueOrAny(filter.stateName)),
+			fmt.Sprintf("Label (current: %s)", valueOrAny(filter.labelName)),
+			fmt.Sprintf("Assignee (current: %s)", valueOrAny(filter.assigneeName)),
+			fmt.Sprintf("Priority (current: %s)", priority),
+			"Done",
+		}
+
+		choice, err := ui.SelectFromList("Filter Issues", options)
+		if err != nil {
+			if err.Error() == "cancelled" {
+				return types.ErrNavigateBack
+			}
+			return err
+		}
+
+		switch choice {
+		case 0: // State
+			states, err := lookups.getStates()
+			if err != nil {
+				ui.ShowError(fmt.Sprintf("Failed to fetch workflow states: %v", err))
+				continue
+			}
+			stateOptions := []string{"Any"}
+			for _, state := range states {
+				stateOptions = append(stateOptions, state.Name)
+			}
+			stateChoice, err := ui.SelectFromList("Filter by state", stateOptions)
+			if err != nil {
+				continue
+			}
+			if stateChoice == 0 {
+				filter.StateID, filter.stateName = "", ""
+			} else {
+				filter.StateID, filter.stateName = states[stateChoice-1].ID, states[stateChoice-1].Name
+			}
+
+		case 1: // Label
+			labels, err := lookups.getLabels()
+			if err != nil {
+				ui.ShowError(fmt.Sprintf("Failed to fetch labels: %v", err))
+				continue
+			}
+			labelOptions := []string{"Any"}
+			for _, label := range labels {
+				labelOptions = append(labelOptions, label.Name)
+			}
+			labelChoice, err := ui.SelectFromList("Filter by label", labelOptions)
+			if err != nil {
+				continue
+			}
+			if labelChoice == 0 {
+				filter.LabelID, filter.labelName = "", ""
+			} else {
+				filter.LabelID, filter.labelName = labels[labelChoice-1].ID, labels[labelChoice-1].Name
+			}
+
+		case 2: // Assignee
+			users, err := lookups.getUsers()
+			if err != nil {
+				ui.ShowError(fmt.Sprintf("Failed to fetch users: %v", err))
+				continue
+			}
+			userOptions := []string{"Any", "Unassigned"}
+			for _, user := range users {
+				userOptions = append(userOptions, fmt.Sprintf("%s (%s)", user.DisplayName, user.Email))
+			}
+			userChoice, err := ui.SelectFromList("Filter by assignee", userOptions)
+			if err != nil {
+				continue
+			}
+			switch userChoice {
+			case 0:
+				filter.AssigneeID, filter.assigneeName = "", ""
+			case 1:
+				filter.AssigneeID, filter.assigneeName = "unassigned", "Unassigned"
+			default:
+				user := users[userChoice-2]
+				filter.AssigneeID, filter.assigneeName = user.ID, user.DisplayName
+			}
+
+		case 3: // Priority
+			priorityOptions := []string{"Any", "No priority", "Urgent", "High", "Medium", "Low"}
+			priorityChoice, err := ui.SelectFromList("Filter by priority", priorityOptions)
+			if err != nil {
+				continue
+			}
+			if priorityChoice == 0 {
+				filter.Priority = nil
+			} else {
+				value := priorityChoice - 1
+				filter.Priority = &value
+			}
+
+		case 4: // Done
+			return nil
+		}
+	}
+}
+
+// triageIssue shows an issue and lets the user change it or comment on it
+func (m *Module) triageIssue(client *LinearClient, lookups *triageLookups, issueID string) error {
+	for {
+		ui.ShowInfo("Fetching issue...")
+		issue, err := client.GetIssue(issueID)
+		if err != nil {
+			ui.ShowError(fmt.Sprintf("Failed to fetch issue: %v", err))
+			return nil
+		}
+
+		m.printTriageIssue(issue)
+
+		options := []string{
+			"Change State",
+			"Change Priority",
+			"Change Assignee",
+			"Edit Labels",
+			"Add Comment",
+			"Back",
+		}
+
+		choice, err := ui.SelectFromList(fmt.Sprintf("%s: %s", issue.Identifier, issue.Title), options)
+		if err != nil {
+			if err.Error() == "cancelled" {
+				return types.ErrNavigateBack
+			}
+			return err
+		}
+
+		var update LinearIssueUpdateInput
+
+		switch choice {
+		case 0: // Change state
+			states, err := lookups.getStates()
+			if err != nil {
+				ui.ShowError(fmt.Sprintf("Failed to fetch workflow states: %v", err))
+				continue
+			}
+			stateOptions := make([]string, len(states))
+			for i, state := range states {
+				stateOptions[i] = state.Name
+			}
+			stateChoice, err := ui.SelectFromList("Select new state", stateOptions)
+			if err != nil {
+				continue
+			}
+			update.StateID = &states[stateChoice].ID
+
+		case 1: // Change priority
+			priorities := []string{"No priority", "Urgent", "High", "Medium", "Low"}
+			priorityChoice, err := ui.SelectFromList("Select new priority", priorities)
+			if err != nil {
+				continue
+			}
+			update.Priority = &priorityChoice
+
+		case 2: // Change assignee
+			users, err := lookups.getUsers()
+			if err != nil {
+				ui.ShowError(fmt.Sprintf("Failed to fetch users: %v", err))
+				continue
+			}
+			userOptions := []string{"Unassigned"}
+			for _, user := range users {
+				userOptions = append(userOptions, fmt.Sprintf("%s (%s)", user.DisplayName, user.Email))
+			}
+			userChoice, err := ui.SelectFromList("Select assignee", userOptions)
+			if err != nil {
+				continue
+			}
+			assigneeID := ""
+			if userChoice > 0 {
+				assigneeID = users[userChoice-1].ID
+			}
+			update.AssigneeID = &assigneeID
+
+		case 3: // Edit labels
+			labelIDs, err := m.selectTriageLabels(lookups, issue)
+			if err != nil {
+				if err == types.ErrNavigateBack {
+					continue
+				}
+				ui.ShowError(fmt.Sprintf("Failed to fetch labels: %v", err))
+				continue
+			}
+			update.LabelIDs = labelIDs
+
+		case 4: // Add comment
+			body, err := ui.GetInput("Comment (markdown)", "", false, func(s string) error {
+				if strings.TrimSpace(s) == "" {
+					return fmt.Errorf("comment must not be empty")
+				}
+				return nil
+			})
+			if err != nil {
+				continue
+			}
+			if err := client.CreateComment(issue.ID, body); err != nil {
+				ui.ShowError(fmt.Sprintf("Failed to add comment: %v", err))
+			} else {
+				ui.ShowSuccess("Comment added!")
+			}
+			continue
+
+		case 5: // Back
+			return types.ErrNavigateBack
+		}
+
+		if _, err := client.UpdateIssue(issue.ID, update); err != nil {
+			ui.ShowError(fmt.Sprintf("Failed to update issue: %v", err))
+		} else {
+			ui.ShowSuccess("Issue updated!")
+		}
+	}
+}
+
+// selectTriageLabels lets the user toggle the labels of an issue and returns the new label IDs
+func (m *Module) selectTriageLabels(lookups *triageLookups, issue *LinearIssueDetail) ([]string, error) {
+	labels, err := lookups.getLabels()
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]bool)
+	for _, label := range issue.Labels.Nodes {
+		selected[label.ID] = true
+	}
+
+	for {
+		options := []string{"Save Labels"}
+		for _, label := range labels {
+			mark := "[ ]"
+			if selected[label.ID] {
+				mark = "[x]"
+			}
+			options = append(options, fmt.Sprintf("%s %s", mark, label.Name))
+		}
+
+		choice, err := ui.SelectFromList("Toggle labels", options)
+		if err != nil {
+			return nil, types.ErrNavigateBack
+		}
+
+		if choice == 0 {
+			labelIDs := []string{}
+			for _, label := range labels {
+				if selected[label.ID] {
+					labelIDs = append(labelIDs, label.ID)
+				}
+			}
+			return labelIDs, nil
+		}
+
+		id := labels[choice-1].ID
+		selected[id] = !selected[id]
+	}
+}
+
+// printTriageIssue prints the details of an issue
+func (m *Module) printTriageIssue(issue *LinearIssueDetail) {
+	separator := strings.Repeat("─", 60)
+	fmt.Println("\n" + separator)
+	fmt.Println(lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("%s: %s", issue.Identifier, issue.Title)))
+	fmt.Println(separator)
+
+	assignee := "Unassigned"
+	if issue.Assignee != nil {
+		assignee = fmt.Sprintf("%s (%s)", issue.Assignee.DisplayName, issue.Assignee.Email)
+	}
+	labelNames := make([]string, len(issue.Labels.Nodes))
+	for i, label := range issue.Labels.Nodes {
+		labelNames[i] = label.Name
+	}
+
+	fmt.Printf("State: %s\n", issue.State.Name)
+	fmt.Printf("Priority: %s\n", m.getPriorityName(issue.Priority))
+	fmt.Printf("Assignee: %s\n", assignee)
+	fmt.Printf("Team: %s\n", issue.Team.Name)
+	if issue.Project != nil {
+		fmt.Printf("Project: %s\n", issue.Project.Name)
+	}
+	fmt.Printf("Labels: %s\n", valueOrNone(strings.Join(labelNames, ", ")))
+	fmt.Printf("URL: %s\n", issue.URL)
+
+	if issue.Description != "" {
+		description := issue.Description
+		if len(description) > 1500 {
+			description = description[:1500] + "..."
+		}
+		fmt.Println("\nDescription:")
+		fmt.Println(description)
+	}
+
+	if comments := issue.Comments.Nodes; len(comments) > 0 {
+		fmt.Printf("\nComments (%d):\n", len(comments))
+		for _, comment := range comments {
+			author := "Unknown"
+			if comment.User != nil {
+				author = comment.User.DisplayName
+			}
+			fmt.Printf("\n▸ %s · %s\n%s\n", author, comment.CreatedAt, comment.Body)
+		}
+	}
+
+	fmt.Println(separator)
+}
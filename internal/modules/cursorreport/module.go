@@ -103,7 +103,7 @@ func (m *Module) showCurrentUsage(cfg *config.Config) error {
 	}
 
 	// Display usage in a nice table
-	table := NewTable("Current Usage Statistics")
+	table := ui.NewTable("Current Usage Statistics")
 	table.AddHeader("Metric", "Used", "Limit", "Usage %")
 	
 	// Add usage data
@@ -139,7 +139,7 @@ func (m *Module) showCostAnalysis(cfg *config.Config) error {
 	analysis := analyzeCosts(usage, cfg.Cursor.CurrentPlan)
 	
 	// Display cost analysis table
-	table := NewTable("Cost Analysis & Savings")
+	table := ui.NewTable("Cost Analysis & Savings")
 	table.AddHeader("Plan", "Monthly Cost", "Your Usage Cost", "Status")
 	
 	for _, plan := range analysis.Plans {
@@ -226,7 +226,7 @@ func (m *Module) showUsageHistory(cfg *config.Config) error {
 	
 	// Summary statistics
 	fmt.Println()
-	table := NewTable("30-Day Summary")
+	table := ui.NewTable("30-Day Summary")
 	table.AddHeader("Metric", "Total", "Daily Average", "Peak Day")
 	table.AddRow("Tokens", formatNumber(history.TotalTokens), formatNumber(history.AvgTokensPerDay), history.PeakDay.Format("Jan 2"))
 	table.AddRow("API Calls", fmt.Sprintf("%d", history.TotalAPICalls), fmt.Sprintf("%d", history.AvgCallsPerDay), "-")
@@ -240,7 +240,7 @@ func (m *Module) showUsageHistory(cfg *config.Config) error {
 func (m *Module) showPlanComparison(cfg *config.Config) error {
 	fmt.Println()
 	
-	table := NewTable("Cursor AI Plan Comparison")
+	table := ui.NewTable("Cursor AI Plan Comparison")
 	table.AddHeader("Feature", "Free", "Pro ($20/mo)", "Business ($40/mo)")
 	
 	// Add comparison data
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/kkz6/devtools/internal/config"
+	"github.com/kkz6/devtools/internal/semver"
 	"github.com/kkz6/devtools/internal/ui"
 )
 
@@ -411,23 +412,10 @@ func (vm *VersionManager) ValidateVersion() error {
 	return nil
 }
 
-// CompareVersions compares two semantic versions
+// CompareVersions compares two semantic versions by SemVer precedence, so
+// pre-releases sort before their release and build numbers are ignored
 func (vm *VersionManager) CompareVersions(v1, v2 string) int {
-	parts1 := strings.Split(v1, ".")
-	parts2 := strings.Split(v2, ".")
-
-	for i := 0; i < 3; i++ {
-		num1, _ := strconv.Atoi(parts1[i])
-		num2, _ := strconv.Atoi(parts2[i])
-
-		if num1 > num2 {
-			return 1
-		} else if num1 < num2 {
-			return -1
-		}
-	}
-
-	return 0
+	return semver.Compare(v1, v2)
 }
 
 // ExportVersionInfo exports version information to a file
@@ -0,0 +1,419 @@
+package releasemanager
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kkz6/devtools/internal/config"
+	"github.com/kkz6/devtools/internal/ui"
+)
+
+// defaultBuildTargets are the platforms built when a repository configures none
+var defaultBuildTargets = []string{"darwin/amd64", "darwin/arm64", "linux/amd64", "linux/arm64", "windows/amd64"}
+
+const (
+	checksumsFile = "checksums.txt"
+	manifestFile  = "manifest.json"
+)
+
+// buildManifest describes the archives of a release build
+type buildManifest struct {
+	Project   string          `json:"project"`
+	Version   string          `json:"version"`
+	Commit    string          `json:"commit"`
+	BuildTime string          `json:"build_time"`
+	Artifacts []buildArtifact `json:"artifacts"`
+}
+
+// buildArtifact is one archive of a release build
+type buildArtifact struct {
+	Name   string `json:"name"`
+	OS     string `json:"os"`
+	Arch   string `json:"arch"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	path   string
+}
+
+// buildSettings returns the build configuration of a repository with defaults applied.
+// The boolean is false for repositories that configure no build and are no Go module.
+func buildSettings(repo config.ReleaseRepo) (config.ReleaseBuild, bool) {
+	var build config.ReleaseBuild
+	if repo.Build != nil {
+		build = *repo.Build
+	} else if _, err := os.Stat(filepath.Join(repo.Path, "go.mod")); err != nil {
+		return build, false
+	}
+
+	if build.Binary == "" {
+		build.Binary = goModuleName(repo.Path)
+	}
+	if build.Package == "" {
+		build.Package = "."
+	}
+	if len(build.Targets) == 0 {
+		build.Targets = defaultBuildTargets
+	}
+	if build.Output == "" {
+		build.Output = "dist"
+	}
+	if !filepath.IsAbs(build.Output) {
+		build.Output = filepath.Join(repo.Path, build.Output)
+	}
+	return build, true
+}
+
+// goModuleName returns the last element of the module path in the repository's go.mod,
+// skipping a major version suffix, or the directory name when there is no go.mod
+func goModuleName(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return filepath.Base(dir)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "module" {
+			continue
+		}
+		parts := strings.Split(strings.Trim(fields[1], `"`), "/")
+		name := parts[len(parts)-1]
+		if len(parts) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+			name = parts[len(parts)-2]
+		}
+		return name
+	}
+	return filepath.Base(dir)
+}
+
+// parseTarget splits a GOOS/GOARCH target
+func parseTarget(target string) (string, string, error) {
+	parts := strings.Split(strings.TrimSpace(target), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid target %q, expected GOOS/GOARCH such as linux/amd64", target)
+	}
+	return parts[0], parts[1], nil
+}
+
+// archiveFiles resolves the files archived next to the binary. Configured files must
+// exist; by default the LICENSE and README found in the repository are used.
+func archiveFiles(repo config.ReleaseRepo, build config.ReleaseBuild) ([]string, error) {
+	if len(build.Files) > 0 {
+		files := make([]string, 0, len(build.Files))
+		for _, file := range build.Files {
+			path := filepath.Join(repo.Path, file)
+			if _, err := os.Stat(path); err != nil {
+				return nil, fmt.Errorf("archive file %s not found", file)
+			}
+			files = append(files, path)
+		}
+		return files, nil
+	}
+
+	var files []string
+	for _, pattern := range []string{"LICENSE*", "README*"} {
+		matches, _ := filepath.Glob(filepath.Join(repo.Path, pattern))
+		if len(matches) > 0 {
+			files = append(files, matches[0])
+		}
+	}
+	return files, nil
+}
+
+// buildRelease cross-compiles the binary for every target, packages each into a tar.gz
+// (zip for Windows) archive with the LICENSE and README, and writes checksums.txt and
+// manifest.json to the output directory. Archives of earlier builds are removed first.
+func (m *Module) buildRelease(repo config.ReleaseRepo, build config.ReleaseBuild, version string) (*buildManifest, error) {
+	files, err := archiveFiles(repo, build)
+	if err != nil {
+		return nil, err
+	}
+	for _, target := range build.Targets {
+		if _, _, err := parseTarget(target); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.MkdirAll(build.Output, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %v", build.Output, err)
+	}
+	stale := []string{filepath.Join(build.Output, checksumsFile), filepath.Join(build.Output, manifestFile)}
+	for _, ext := range []string{".tar.gz", ".zip"} {
+		matches, _ := filepath.Glob(filepath.Join(build.Output, build.Binary+"_*"+ext))
+		stale = append(stale, matches...)
+	}
+	for _, path := range stale {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove %s: %v", path, err)
+		}
+	}
+
+	staging, err := os.MkdirTemp("", "devtools-build-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(staging)
+
+	manifest := &buildManifest{
+		Project:   build.Binary,
+		Version:   version,
+		BuildTime: time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+	}
+	if output, err := exec.Command("git", "-C", repo.Path, "rev-parse", "HEAD").Output(); err == nil {
+		manifest.Commit = strings.TrimSpace(string(output))
+	}
+	ldflags := fmt.Sprintf("-s -w -X main.Version=%s -X main.BuildTime=%s %s", version, manifest.BuildTime, build.Ldflags)
+
+	progress := ui.NewProgressBar("Building", len(build.Targets))
+	for _, target := range build.Targets {
+		goos, goarch, _ := parseTarget(target)
+		progress.UpdateTitle(fmt.Sprintf("Building %s", target))
+
+		binary := build.Binary
+		if goos == "windows" {
+			binary += ".exe"
+		}
+		binaryPath := filepath.Join(staging, target, binary)
+
+		cmd := exec.Command("go", "build", "-trimpath", "-ldflags", strings.TrimSpace(ldflags), "-o", binaryPath, build.Package)
+		cmd.Dir = repo.Path
+		cmd.Env = append(os.Environ(), "GOOS="+goos, "GOARCH="+goarch, "CGO_ENABLED=0")
+		cmd.Env = append(cmd.Env, envList(repo.Env)...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			progress.Finish()
+			return nil, fmt.Errorf("build for %s failed: %v\n%s", target, err, strings.TrimSpace(string(output)))
+		}
+
+		name := fmt.Sprintf("%s_%s_%s_%s", build.Binary, strings.TrimPrefix(version, "v"), goos, goarch)
+		archive := filepath.Join(build.Output, name)
+		entries := append([]string{binaryPath}, files...)
+		if goos == "windows" {
+			archive += ".zip"
+			err = writeZip(archive, entries)
+		} else {
+			archive += ".tar.gz"
+			err = writeTarGz(archive, entries)
+		}
+		if err != nil {
+			progress.Finish()
+			return nil, fmt.Errorf("failed to package %s: %v", target, err)
+		}
+
+		artifact, err := describeArtifact(archive, goos, goarch)
+		if err != nil {
+			progress.Finish()
+			return nil, err
+		}
+		manifest.Artifacts = append(manifest.Artifacts, artifact)
+		progress.Increment()
+	}
+	progress.Finish()
+
+	if err := writeChecksums(filepath.Join(build.Output, checksumsFile), manifest.Artifacts); err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(build.Output, manifestFile), append(data, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %v", manifestFile, err)
+	}
+	return manifest, nil
+}
+
+// describeArtifact returns the size and SHA-256 checksum of an archive
+func describeArtifact(path, goos, goarch string) (buildArtifact, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return buildArtifact{}, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return buildArtifact{}, fmt.Errorf("failed to checksum %s: %v", path, err)
+	}
+	return buildArtifact{
+		Name:   filepath.Base(path),
+		OS:     goos,
+		Arch:   goarch,
+		Size:   size,
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+		path:   path,
+	}, nil
+}
+
+// writeChecksums writes checksums in the sha256sum format
+func writeChecksums(path string, artifacts []buildArtifact) error {
+	sorted := append([]buildArtifact(nil), artifacts...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	lines := make([]string, 0, len(sorted))
+	for _, artifact := range sorted {
+		lines = append(lines, fmt.Sprintf("%s  %s", artifact.SHA256, artifact.Name))
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", checksumsFile, err)
+	}
+	return nil
+}
+
+// writeTarGz writes files into the root of a gzip-compressed tar archive
+func writeTarGz(path string, files []string) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	for _, file := range files {
+		if err := addTarFile(tw, file); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// addTarFile adds a file to a tar archive under its base name
+func addTarFile(tw *tar.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = filepath.Base(path)
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, file)
+	return err
+}
+
+// writeZip writes files into the root of a zip archive
+func writeZip(path string, files []string) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	zw := zip.NewWriter(out)
+	for _, file := range files {
+		if err := addZipFile(zw, file); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// addZipFile adds a file to a zip archive under its base name
+func addZipFile(zw *zip.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = filepath.Base(path)
+	header.Method = zip.Deflate
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, file)
+	return err
+}
+
+// buildAssets returns the files of a build to upload to a GitHub Release
+func buildAssets(build config.ReleaseBuild, manifest *buildManifest) []string {
+	assets := make([]string, 0, len(manifest.Artifacts)+2)
+	for _, artifact := range manifest.Artifacts {
+		assets = append(assets, artifact.path)
+	}
+	return append(assets, filepath.Join(build.Output, checksumsFile), filepath.Join(build.Output, manifestFile))
+}
+
+// showBuildManifest prints the archives of a build
+func showBuildManifest(build config.ReleaseBuild, manifest *buildManifest) {
+	table := ui.NewTable(fmt.Sprintf("Release Archives for %s", manifest.Version))
+	table.AddHeader("Archive", "Platform", "Size", "SHA-256")
+	for _, artifact := range manifest.Artifacts {
+		table.AddRow(artifact.Name, artifact.OS+"/"+artifact.Arch, fmt.Sprintf("%.1f MB", float64(artifact.Size)/(1<<20)), artifact.SHA256[:16]+"…")
+	}
+	fmt.Println()
+	fmt.Println(table.Render())
+	fmt.Println()
+	ui.ShowSuccess(fmt.Sprintf("✅ %d archives, %s and %s written to %s", len(manifest.Artifacts), checksumsFile, manifestFile, build.Output))
+}
+
+// buildVersion describes HEAD like the Makefile does, for builds outside a release
+func (m *Module) buildVersion(repo config.ReleaseRepo) string {
+	output, err := exec.Command("git", "-C", repo.Path, "describe", "--tags", "--always", "--dirty").Output()
+	if err != nil {
+		return "dev"
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// runBuild builds the release archives of the current repository from the menu
+func (m *Module) runBuild(repo config.ReleaseRepo) error {
+	build, ok := buildSettings(repo)
+	if !ok {
+		ui.ShowWarning("No build configured and this is not a Go module")
+		ui.ShowInfo("Add build under release_manager.repos in the configuration")
+		return nil
+	}
+
+	version := m.buildVersion(repo)
+	fmt.Println()
+	ui.ShowInfo(fmt.Sprintf("🔨 Building %s %s for %s", build.Binary, version, strings.Join(build.Targets, ", ")))
+	fmt.Println()
+
+	manifest, err := m.buildRelease(repo, build, version)
+	if err != nil {
+		return err
+	}
+	showBuildManifest(build, manifest)
+	return nil
+}
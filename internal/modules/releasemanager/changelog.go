@@ -0,0 +1,679 @@
+package releasemanager
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kkz6/devtools/internal/config"
+	"github.com/kkz6/devtools/internal/ui"
+)
+
+// changelogSections lists the Keep a Changelog sections in the order they are written
+var changelogSections = []string{"Added", "Changed", "Deprecated", "Removed", "Fixed", "Security"}
+
+// labelSections maps pull request labels to changelog sections, checked in this order
+var labelSections = []struct {
+	Section string
+	Labels  []string
+}{
+	{"Security", []string{"security", "vulnerability"}},
+	{"Removed", []string{"removed", "removal", "breaking-removal"}},
+	{"Deprecated", []string{"deprecated", "deprecation"}},
+	{"Fixed", []string{"bug", "bugfix", "fix", "fixed", "regression"}},
+	{"Added", []string{"feature", "enhancement", "feat", "added", "new feature"}},
+	{"Changed", []string{"changed", "breaking", "breaking-change", "breaking change", "performance", "refactor"}},
+}
+
+// changelogLinkRef matches a markdown link reference definition such as "[1.0.0]: https://..."
+var changelogLinkRef = regexp.MustCompile(`^\[([^\]]+)\]:\s*(\S+)`)
+
+// ChangelogEntry is a generated changelog entry grouped by section
+type ChangelogEntry struct {
+	Sections map[string][]string
+	Skipped  []Commit // Commits left out, e.g. chores and non-conforming messages
+}
+
+// Empty reports whether the entry has no changes
+func (e *ChangelogEntry) Empty() bool {
+	for _, items := range e.Sections {
+		if len(items) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// commitSection returns the changelog section of a Conventional Commit, or "" to leave it out
+func commitSection(commit Commit) string {
+	if !commit.Conforming {
+		return ""
+	}
+
+	subject := strings.ToLower(commit.Subject)
+	switch {
+	case commit.Type == "security" || strings.EqualFold(commit.Scope, "security"):
+		return "Security"
+	case (commit.Type == "feat" || commit.Type == "refactor") && (strings.HasPrefix(subject, "remove") || strings.HasPrefix(subject, "drop")):
+		return "Removed"
+	case strings.HasPrefix(subject, "deprecate"):
+		return "Deprecated"
+	case commit.Breaking:
+		return "Changed"
+	}
+
+	switch commit.Type {
+	case "feat":
+		return "Added"
+	case "fix":
+		return "Fixed"
+	case "perf", "revert":
+		return "Changed" // A revert undoes a change rather than removing a feature
+	}
+	return ""
+}
+
+// labelSection returns the changelog section of a pull request from its labels, or ""
+func labelSection(labels []githubLabel) string {
+	names := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		name := strings.ToLower(label.Name)
+		names[name] = true
+		// Prefixed labels such as "type: bug" or "kind/feature"
+		if i := strings.LastIndexAny(name, ":/"); i != -1 {
+			names[strings.TrimSpace(name[i+1:])] = true
+		}
+	}
+
+	for _, mapping := range labelSections {
+		for _, label := range mapping.Labels {
+			if names[label] {
+				return mapping.Section
+			}
+		}
+	}
+	return ""
+}
+
+// commitEntryLine formats a commit as a changelog line
+func commitEntryLine(commit Commit) string {
+	if !commit.Conforming {
+		return commit.Header
+	}
+
+	line := commit.Subject
+	if commit.Scope != "" {
+		line = fmt.Sprintf("**%s:** %s", commit.Scope, line)
+	}
+	if commit.Breaking {
+		line = "**BREAKING:** " + line
+	}
+	return line
+}
+
+// buildChangelogEntry groups commits into changelog sections. With a GitHub client, commits
+// merged through a pull request are listed once per pull request and grouped by its labels,
+// falling back to the commit type for unlabelled pull requests.
+func (m *Module) buildChangelogEntry(commits []Commit, github *githubClient, repo string) (*ChangelogEntry, error) {
+	entry := &ChangelogEntry{Sections: make(map[string][]string)}
+	seenPulls := make(map[int]bool)
+
+	// git log lists the newest commit first; changelogs read oldest first within a section
+	for i := len(commits) - 1; i >= 0; i-- {
+		commit := commits[i]
+		section := commitSection(commit)
+		line := commitEntryLine(commit)
+
+		if github != nil {
+			pulls, err := github.getCommitPullRequests(repo, commit.Hash)
+			if err != nil {
+				return nil, fmt.Errorf("failed to look up pull requests of %s: %v", commit.Hash[:7], err)
+			}
+			if pull := mergedPull(pulls); pull != nil {
+				if seenPulls[pull.Number] {
+					continue
+				}
+				seenPulls[pull.Number] = true
+
+				title := parseCommit("", pull.Title, "")
+				section = labelSection(pull.Labels)
+				if section == "" {
+					section = commitSection(title)
+				}
+				if section == "" {
+					section = commitSection(commit)
+				}
+				line = fmt.Sprintf("%s ([#%d](%s))", commitEntryLine(title), pull.Number, pull.HTMLURL)
+			}
+		}
+
+		if section == "" {
+			entry.Skipped = append(entry.Skipped, commit)
+			continue
+		}
+		entry.Sections[section] = append(entry.Sections[section], line)
+	}
+
+	return entry, nil
+}
+
+// mergedPull returns the merged pull request of a commit, or its first pull request
+func mergedPull(pulls []githubPullRequest) *githubPullRequest {
+	for i := range pulls {
+		if pulls[i].MergedAt != nil {
+			return &pulls[i]
+		}
+	}
+	if len(pulls) > 0 {
+		return &pulls[0]
+	}
+	return nil
+}
+
+// renderChangelogEntry renders the sections of an entry as markdown
+func renderChangelogEntry(entry *ChangelogEntry) string {
+	var b strings.Builder
+	for _, section := range changelogSections {
+		items := entry.Sections[section]
+		if len(items) == 0 {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(fmt.Sprintf("### %s\n\n", section))
+		for _, item := range items {
+			b.WriteString(fmt.Sprintf("- %s\n", item))
+		}
+	}
+	return b.String()
+}
+
+// parseChangelogEntry reads back an entry rendered by renderChangelogEntry after editing
+func parseChangelogEntry(text string) (*ChangelogEntry, error) {
+	entry := &ChangelogEntry{Sections: make(map[string][]string)}
+	section := ""
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "<!--"):
+			continue
+		case strings.HasPrefix(trimmed, "### "):
+			section = strings.TrimSpace(strings.TrimPrefix(trimmed, "### "))
+			if !containsSection(section) {
+				return nil, fmt.Errorf("unknown section %q, use one of %s", section, strings.Join(changelogSections, ", "))
+			}
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
+			if section == "" {
+				return nil, fmt.Errorf("change %q is not under a section", trimmed)
+			}
+			entry.Sections[section] = append(entry.Sections[section], strings.TrimSpace(trimmed[2:]))
+		default:
+			// Continuation of the previous item
+			items := entry.Sections[section]
+			if len(items) == 0 {
+				return nil, fmt.Errorf("unexpected line %q", trimmed)
+			}
+			items[len(items)-1] += " " + trimmed
+		}
+	}
+	return entry, scanner.Err()
+}
+
+// containsSection reports whether a name is a Keep a Changelog section
+func containsSection(name string) bool {
+	for _, section := range changelogSections {
+		if section == name {
+			return true
+		}
+	}
+	return false
+}
+
+// writeChangelogEntry adds an entry to the [Unreleased] section and, unless the version is
+// "Unreleased", promotes that section to the version with today's date and compare links.
+// tag is the git tag of the version, previous the tag it follows and repo the GitHub
+// "owner/repo" for links.
+func (m *Module) writeChangelogEntry(content, version, tag, previous, repo string, entry *ChangelogEntry) string {
+	content = ensureUnreleased(content)
+
+	// Items already pending in [Unreleased] are not repeated; older releases may list the same line
+	unreleased, _ := changelogVersionSection(content, "Unreleased")
+	pending := make(map[string]bool)
+	for _, line := range strings.Split(unreleased, "\n") {
+		pending[strings.TrimSpace(line)] = true
+	}
+
+	for _, section := range changelogSections {
+		var items []string
+		for _, item := range entry.Sections[section] {
+			if !pending["- "+item] {
+				items = append(items, item)
+			}
+		}
+		if len(items) > 0 {
+			content = m.insertChangelogEntry(content, "Unreleased", section, items)
+		}
+	}
+
+	if version == "Unreleased" {
+		return content
+	}
+	return promoteUnreleased(content, version, tag, previous, repo, time.Now().Format("2006-01-02"))
+}
+
+// ensureUnreleased adds an empty [Unreleased] section before the first release when missing
+func ensureUnreleased(content string) string {
+	lines := strings.Split(content, "\n")
+	for _, line := range lines {
+		if strings.HasPrefix(line, "## [Unreleased]") {
+			return content
+		}
+	}
+
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## ") || changelogLinkRef.MatchString(line) {
+			result := append([]string{}, lines[:i]...)
+			result = append(result, "## [Unreleased]", "")
+			return strings.Join(append(result, lines[i:]...), "\n")
+		}
+	}
+	return strings.TrimRight(content, "\n") + "\n\n## [Unreleased]\n"
+}
+
+// promoteUnreleased turns the [Unreleased] section into a dated version section, leaves a fresh
+// [Unreleased] section above it and updates the compare links at the bottom of the file
+func promoteUnreleased(content, version, tag, previous, repo, date string) string {
+	lines := strings.Split(content, "\n")
+	start := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## [Unreleased]") {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		return content
+	}
+
+	end := start + 1
+	for end < len(lines) && !strings.HasPrefix(lines[end], "## ") && !changelogLinkRef.MatchString(lines[end]) {
+		end++
+	}
+
+	result := append([]string{}, lines[:start]...)
+	result = append(result, "## [Unreleased]", "", fmt.Sprintf("## [%s] - %s", version, date), "")
+	result = append(result, compactSections(lines[start+1:end])...)
+	result = append(result, "")
+	result = append(result, lines[end:]...)
+
+	return updateCompareLinks(strings.Join(result, "\n"), version, tag, previous, repo)
+}
+
+// compactSections drops empty "###" sections, orders them and collapses blank lines of a release body
+func compactSections(body []string) []string {
+	type group struct {
+		heading string
+		lines   []string
+	}
+
+	var groups []group
+	current := group{}
+	for _, line := range body {
+		if strings.HasPrefix(line, "### ") {
+			groups = append(groups, current)
+			current = group{heading: line}
+			continue
+		}
+		if strings.TrimSpace(line) != "" {
+			current.lines = append(current.lines, line)
+		}
+	}
+	groups = append(groups, current)
+
+	// Keep a Changelog sections in their usual order, other headings after them
+	rank := func(heading string) int {
+		for i, section := range changelogSections {
+			if heading == "### "+section {
+				return i + 1
+			}
+		}
+		if heading == "" {
+			return 0
+		}
+		return len(changelogSections) + 1
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return rank(groups[i].heading) < rank(groups[j].heading) })
+
+	var result []string
+	for _, g := range groups {
+		if len(g.lines) == 0 {
+			continue
+		}
+		if len(result) > 0 {
+			result = append(result, "")
+		}
+		if g.heading != "" {
+			result = append(result, g.heading, "")
+		}
+		result = append(result, g.lines...)
+	}
+	return result
+}
+
+// updateCompareLinks points [Unreleased] at the new version and adds the version's compare
+// link. The links use tag, which differs from the version for prefixed package tags.
+func updateCompareLinks(content, version, tag, previous, repo string) string {
+	if repo == "" {
+		return content
+	}
+
+	base := fmt.Sprintf("https://github.com/%s", repo)
+	versionLink := fmt.Sprintf("[%s]: %s/releases/tag/%s", version, base, tag)
+	if previous != "" {
+		versionLink = fmt.Sprintf("[%s]: %s/compare/%s...%s", version, base, previous, tag)
+	}
+	links := []string{
+		fmt.Sprintf("[Unreleased]: %s/compare/%s...HEAD", base, tag),
+		versionLink,
+	}
+
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	var result []string
+	insertAt := -1
+	for _, line := range lines {
+		if match := changelogLinkRef.FindStringSubmatch(line); match != nil {
+			if match[1] == "Unreleased" || match[1] == version {
+				if insertAt == -1 {
+					insertAt = len(result)
+				}
+				continue
+			}
+			if insertAt == -1 {
+				insertAt = len(result)
+			}
+		}
+		result = append(result, line)
+	}
+
+	if insertAt == -1 {
+		result = append(result, "")
+		result = append(result, links...)
+	} else {
+		result = append(result[:insertAt], append(links, result[insertAt:]...)...)
+	}
+	return strings.Join(result, "\n") + "\n"
+}
+
+// editText opens text in the user's editor and returns the saved result
+func (m *Module) editText(pattern, text string) (string, error) {
+	file, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(text); err != nil {
+		file.Close()
+		return "", fmt.Errorf("failed to write temporary file: %v", err)
+	}
+	file.Close()
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vim"
+	}
+	cmd := exec.Command(editor, file.Name())
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor failed: %v", err)
+	}
+
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read edited file: %v", err)
+	}
+	return string(data), nil
+}
+
+// saveChangelogEntry writes an entry to CHANGELOG.md, creating the file when missing
+func (m *Module) saveChangelogEntry(version, previous, repo string, entry *ChangelogEntry) error {
+	if _, err := os.Stat("CHANGELOG.md"); os.IsNotExist(err) {
+		if err := m.createDefaultChangelog(); err != nil {
+			return fmt.Errorf("failed to create changelog: %v", err)
+		}
+	}
+	return m.saveChangelogEntryTo("CHANGELOG.md", version, version, previous, repo, entry)
+}
+
+// saveChangelogEntryTo writes an entry to an existing changelog file
+func (m *Module) saveChangelogEntryTo(path, version, tag, previous, repo string, entry *ChangelogEntry) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", path, err)
+	}
+
+	updated := m.writeChangelogEntry(string(content), version, tag, previous, repo, entry)
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
+
+// changelogSince resolves the tag the next changelog entry starts from, or "" without tags
+func (m *Module) changelogSince() string {
+	currentVersion, _ := m.getCurrentVersion()
+	if m.runCommandSilent("git", "rev-parse", "--verify", "--quiet", "refs/tags/"+currentVersion) != nil {
+		return ""
+	}
+	return currentVersion
+}
+
+// generateChangelog generates a changelog entry from the commits since the last tag,
+// previews it for editing and writes it to CHANGELOG.md
+func (m *Module) generateChangelog(cfg *config.Config) error {
+	fmt.Println()
+	ui.ShowInfo("📝 Generate Changelog")
+	fmt.Println()
+
+	since := m.changelogSince()
+	analysis, err := m.analyzeCommits(since)
+	if err != nil {
+		return err
+	}
+	if len(analysis.Commits) == 0 {
+		ui.ShowWarning("No commits since the last release")
+		return nil
+	}
+
+	// Group by commit type, or by pull request labels when GitHub is configured
+	var github *githubClient
+	repo, repoErr := m.getGitHubRepo()
+	if cfg.GitHub.Token != "" && repoErr == nil {
+		choice, err := ui.SelectFromList("Group changes by:", []string{
+			"Commit type (Conventional Commits)",
+			"Pull request labels",
+			"Back",
+		})
+		if err != nil || choice == 2 {
+			return nil
+		}
+		if choice == 1 {
+			github = newGitHubClient(cfg.GitHub.Token)
+		}
+	}
+
+	var entry *ChangelogEntry
+	err = ui.ShowLoadingAnimation("Grouping commits", func() error {
+		var buildErr error
+		entry, buildErr = m.buildChangelogEntry(analysis.Commits, github, repo)
+		return buildErr
+	})
+	if err != nil {
+		return err
+	}
+
+	// Pick the version the entry is released as
+	currentVersion := since
+	if currentVersion == "" {
+		currentVersion = "v0.0.0"
+	}
+	nextVersions := m.calculateNextVersions(currentVersion)
+	versions := []string{nextVersions.Patch, nextVersions.Minor, nextVersions.Major, "Unreleased"}
+	options := []string{
+		fmt.Sprintf("Patch (%s)", nextVersions.Patch),
+		fmt.Sprintf("Minor (%s)", nextVersions.Minor),
+		fmt.Sprintf("Major (%s)", nextVersions.Major),
+		"Keep under [Unreleased]",
+		"Back",
+	}
+	defaultIndex := 3
+	if analysis.Bump != BumpNone {
+		defaultIndex = int(analysis.Bump) - 1
+		options[defaultIndex] += " (recommended)"
+	}
+	choice, err := ui.SelectFromListWithDefault("Release the entry as:", options, defaultIndex)
+	if err != nil || choice == 4 {
+		return nil
+	}
+	version := versions[choice]
+
+	// Preview and edit until the entry is written or cancelled
+	for {
+		fmt.Println()
+		heading := fmt.Sprintf("## [%s] - %s", version, time.Now().Format("2006-01-02"))
+		if version == "Unreleased" {
+			heading = "## [Unreleased]"
+		}
+		fmt.Println(heading)
+		fmt.Println()
+		if entry.Empty() {
+			ui.ShowWarning("The entry is empty")
+		} else {
+			fmt.Print(renderChangelogEntry(entry))
+		}
+		fmt.Println()
+		if len(entry.Skipped) > 0 {
+			ui.ShowInfo(fmt.Sprintf("%d commits left out (chores, docs, tests and non-conforming messages); add them while editing if needed", len(entry.Skipped)))
+			fmt.Println()
+		}
+
+		action, err := ui.SelectFromList("Changelog entry:", []string{
+			"Write to CHANGELOG.md",
+			"Edit entry",
+			"Cancel",
+		})
+		if err != nil || action == 2 {
+			ui.ShowInfo("Changelog unchanged")
+			return nil
+		}
+
+		if action == 1 {
+			text := renderChangelogEntry(entry)
+			if len(entry.Skipped) > 0 {
+				text += "\n<!-- Left out:\n"
+				for _, commit := range entry.Skipped {
+					text += fmt.Sprintf("  - %s\n", commit.Header)
+				}
+				text += "-->\n"
+			}
+			edited, err := m.editText("CHANGELOG-*.md", text)
+			if err != nil {
+				return err
+			}
+			parsed, err := parseChangelogEntry(stripComments(edited))
+			if err != nil {
+				ui.ShowError(fmt.Sprintf("Could not read the edited entry: %v", err))
+				continue
+			}
+			parsed.Skipped = entry.Skipped
+			entry = parsed
+			continue
+		}
+
+		if entry.Empty() {
+			ui.ShowWarning("Nothing to write")
+			continue
+		}
+		break
+	}
+
+	if err := m.saveChangelogEntry(version, since, repoOrEmpty(repo, repoErr), entry); err != nil {
+		return err
+	}
+
+	ui.ShowSuccess(fmt.Sprintf("✅ CHANGELOG.md updated for %s", version))
+	return nil
+}
+
+// releaseNotes renders the commits after since up to until grouped by changelog section,
+// listing commits without a section under "Other"
+func (m *Module) releaseNotes(since, until string) (string, error) {
+	commits, err := m.getCommitsBetween(since, until)
+	if err != nil {
+		return "", err
+	}
+	entry, err := m.buildChangelogEntry(commits, nil, "")
+	if err != nil {
+		return "", err
+	}
+
+	notes := renderChangelogEntry(entry)
+	var other []string
+	for _, commit := range entry.Skipped {
+		if !commit.Conforming {
+			other = append(other, commit.Header)
+		}
+	}
+	if len(other) > 0 {
+		if notes != "" {
+			notes += "\n"
+		}
+		notes += "### Other\n\n"
+		for _, header := range other {
+			notes += fmt.Sprintf("- %s\n", header)
+		}
+	}
+	return notes, nil
+}
+
+// valueOr returns value, or fallback when value is empty
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// stripComments removes HTML comments from edited markdown
+func stripComments(text string) string {
+	for {
+		start := strings.Index(text, "<!--")
+		if start == -1 {
+			return text
+		}
+		end := strings.Index(text[start:], "-->")
+		if end == -1 {
+			return text[:start]
+		}
+		text = text[:start] + text[start+end+3:]
+	}
+}
+
+// repoOrEmpty returns the GitHub repository, or "" when it could not be determined
+func repoOrEmpty(repo string, err error) string {
+	if err != nil {
+		return ""
+	}
+	return repo
+}
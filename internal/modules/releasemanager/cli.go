@@ -0,0 +1,628 @@
+package releasemanager
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kkz6/devtools/internal/config"
+	"github.com/kkz6/devtools/internal/semver"
+)
+
+// commandUsage describes the release manager command-line commands
+const commandUsage = `Usage: devtools release-manager <command> [flags]
+
+Commands:
+  release         Tag and push a release without prompting once the release gates pass
+  gates           Check the release gates without releasing
+  pipeline        Run the repository's pipeline steps
+  packages        Show or release the separately versioned packages of a monorepo
+  versions        Report version files that disagree with the latest tag
+  build           Cross-compile release archives with checksums and a manifest
+  bump            Print the recommended next version from the commits since the last tag
+  changelog       Write a CHANGELOG.md entry generated from the commits since the last tag
+  github-release  Create, update, delete or mark the latest GitHub Release through the API
+
+Release:
+  release [--bump auto|patch|minor|major|alpha|beta|rc|final|vX.Y.Z] [--message text] [--dry-run]
+          [--override gate,...|all] [--ci] [--signed] [--build] [--github [GitHub Release flags]]
+  --bump auto (the default) reads the commits since the last tag as Conventional Commits:
+  a breaking change ("type!:" or a "BREAKING CHANGE:" footer) bumps major, feat bumps
+  minor, and fix, perf or revert bump patch. It fails when no commit requires a release.
+  alpha, beta and rc continue the current pre-release (rc.1 to rc.2) or start one for the
+  next minor version; final promotes the current pre-release to its normal version.
+  Tags are signed (git tag -s) when git signing is configured: user.signingkey,
+  commit.gpgsign or tag.gpgsign, as set up by the Git Signing module.
+
+Release gates:
+  gates [--version auto|...|vX.Y.Z] [--ci] [--signed]
+  A release is refused unless every gate passes:
+    clean      no uncommitted or untracked files
+    branch     HEAD is on the release branch (default main)
+    remote     the branch is not behind the remote (default origin)
+    tag        the tag exists neither locally nor on the remote
+    changelog  CHANGELOG.md has a non-empty section for the version
+    test, lint the pipeline steps named test and lint succeed (skipped without them)
+    ci         all GitHub checks on HEAD succeeded (with --ci or require_ci)
+    signed     every commit since the last tag has a valid signature (with --signed or
+               require_signed_commits)
+  Branch, remote, steps, require_ci and require_signed_commits are set per repository under
+  release_manager.repos. A failed gate is only skipped with --override, e.g.
+  --override changelog,lint.
+
+Pipeline:
+  pipeline [--version vX.Y.Z] [step ...]
+  Runs all steps in order, or the named ones (e.g. "pipeline build package"). Steps run
+  through the shell in their dir with the repository and step env plus RELEASE_STEP and
+  RELEASE_VERSION. on_failure abort (the default) stops the pipeline, continue only warns,
+  and prompt asks in the menu and aborts here. Without configured steps, build, test and
+  lint are detected from go.mod, pubspec.yaml, package.json or pyproject.toml. The
+  pre_tag and post_tag hooks run around tagging in release.
+
+Packages:
+  packages [status]
+  packages release [--bump auto|patch|minor|major|vX.Y.Z] [--dry-run] [--override gate,...|all]
+                   [--ci] [--signed] [package ...]
+  Monorepos declare packages with a path and a tag prefix (default "<name>/") under
+  packages per repository. Each package is versioned by its own tags, e.g. api/v1.4.0, and
+  only the commits touching its path count for the bump, the changelog and the signed gate.
+  Without package names, release releases every changed package: one release commit with
+  each package's CHANGELOG.md and version_files, one tag per package, then a single push.
+  The hooks run once per package with RELEASE_PACKAGE and RELEASE_TAG set.
+
+Version files:
+  After the pre_tag hooks, release writes the version into the repository's version_files
+  and commits them as "chore(release): vX.Y.Z" before tagging. A file is given a format
+  (json, pubspec, cargo, pyproject, go or helm, detected for package.json, pubspec.yaml,
+  Cargo.toml, pyproject.toml, Chart.yaml and *.go) or a pattern whose first group is the
+  version. Files keep their "v" style; pubspec build numbers are incremented.
+  versions compares every file with the latest tag and fails on a mismatch.
+
+Build:
+  build [--version vX.Y.Z] [--targets linux/amd64,darwin/arm64] [--output dir]
+  Cross-compiles the main package for each GOOS/GOARCH target with CGO disabled and
+  -ldflags "-X main.Version=<version> -X main.BuildTime=<UTC time>", then packages every
+  binary with the LICENSE and README into <binary>_<version>_<os>_<arch>.tar.gz (.zip for
+  windows) and writes checksums.txt (SHA-256) and manifest.json. The version defaults to
+  git describe. Binary, package, targets, output, extra ldflags and archived files are set
+  under build per repository; Go modules default to darwin, linux and windows builds in
+  dist. release --build builds after tagging, and --github uploads the build.
+
+Changelog:
+  changelog [--version auto|patch|minor|major|unreleased|vX.Y.Z] [--labels] [--dry-run]
+  Commits are grouped into Added, Changed, Deprecated, Removed, Fixed and Security by
+  commit type, or by pull request labels with --labels (needs the GitHub token). The
+  [Unreleased] section is promoted to the version with today's date and compare links.
+  --dry-run prints the entry instead of writing it.
+
+GitHub Releases (need the GitHub token and a GitHub origin remote):
+  github-release create [--draft] [--prerelease auto|true|false] [--latest auto|true|false]
+                        [--notes-file file] [--assets "dist/*,checksums.txt"] <tag>
+  github-release delete <tag>
+  github-release latest <tag>
+  create updates the release when the tag already has one and replaces assets of the same
+  name. Notes default to the tag's CHANGELOG.md section, or are generated from the commits
+  since the previous tag. delete keeps the tag.`
+
+// RunCommand runs release manager commands from the command line
+func (m *Module) RunCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing command\n\n%s", commandUsage)
+	}
+
+	switch args[0] {
+	case "release":
+		return m.runReleaseCommand(cfg, args[1:])
+	case "gates":
+		return m.runGatesCommand(cfg, args[1:])
+	case "pipeline":
+		return m.runPipelineCommand(cfg, args[1:])
+	case "build":
+		return m.runBuildCommand(cfg, args[1:])
+	case "packages":
+		return m.runPackagesCommand(cfg, args[1:])
+	case "versions":
+		return m.checkVersionFiles(m.releaseRepo(cfg))
+	case "bump":
+		return m.runBumpCommand(args[1:])
+	case "changelog":
+		return m.runChangelogCommand(cfg, args[1:])
+	case "github-release":
+		return m.runGitHubReleaseCommand(cfg, args[1:])
+	case "help", "-h", "--help":
+		fmt.Println(commandUsage)
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q\n\n%s", args[0], commandUsage)
+	}
+}
+
+// runReleaseCommand parses the flags of "devtools release-manager release"
+func (m *Module) runReleaseCommand(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("release", flag.ContinueOnError)
+	bump := fs.String("bump", "auto", "Version bump: auto, patch, minor, major, alpha, beta, rc, final or an explicit version such as v1.2.3")
+	message := fs.String("message", "", "Tag message (default: \"Release <version>\")")
+	dryRun := fs.Bool("dry-run", false, "Print the version that would be released without tagging")
+	github := fs.Bool("github", false, "Create the GitHub Release through the API after pushing the tag")
+	buildFlag := fs.Bool("build", false, "Build the release archives after pushing the tag; with --github they are uploaded unless --assets is set")
+	override := fs.String("override", "", "Comma-separated failed gates to release anyway, or \"all\"")
+	ci := fs.Bool("ci", false, "Require green GitHub checks on HEAD even when the repository does not")
+	signed := fs.Bool("signed", false, "Require signed commits since the last tag even when the repository does not")
+	opts := githubReleaseFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	overrides, err := parseOverrides(*override)
+	if err != nil {
+		return err
+	}
+	version, err := m.resolveBump(*bump)
+	if err != nil {
+		return err
+	}
+
+	repo := m.releaseRepo(cfg)
+	if *dryRun {
+		fmt.Printf("Would release %s\n", version)
+		for _, file := range repo.VersionFiles {
+			fmt.Printf("Would set the version in %s\n", file.Path)
+		}
+		return nil
+	}
+
+	repo.RequireCI = repo.RequireCI || *ci
+	repo.RequireSigned = repo.RequireSigned || *signed
+	if err := m.enforceReleaseGates(cfg, repo, version, overrides); err != nil {
+		return err
+	}
+
+	var client *githubClient
+	var githubRepo string
+	if *github {
+		if client, githubRepo, err = m.githubReleaseClient(cfg); err != nil {
+			return err
+		}
+	}
+
+	tagMessage := *message
+	if tagMessage == "" {
+		tagMessage = fmt.Sprintf("Release %s", version)
+	}
+	if err := m.publishRelease(repo, version, tagMessage); err != nil {
+		return err
+	}
+
+	var assets []string
+	if *buildFlag {
+		build, ok := buildSettings(repo)
+		if !ok {
+			return fmt.Errorf("%s is tagged, but there is no build configured and this is not a Go module", version)
+		}
+		manifest, err := m.buildRelease(repo, build, version)
+		if err != nil {
+			return fmt.Errorf("%s is tagged, but the build failed: %v", version, err)
+		}
+		showBuildManifest(build, manifest)
+		assets = buildAssets(build, manifest)
+	}
+
+	if client == nil {
+		return nil
+	}
+	releaseOpts, err := opts.options(version)
+	if err != nil {
+		return err
+	}
+	if len(releaseOpts.Assets) == 0 {
+		releaseOpts.Assets = assets
+	}
+	release, err := m.publishGitHubRelease(client, githubRepo, version, releaseOpts)
+	if err != nil {
+		return err
+	}
+	showGitHubRelease(release)
+	return nil
+}
+
+// runPackagesCommand runs "devtools release-manager packages [status|release]"
+func (m *Module) runPackagesCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 || args[0] == "status" {
+		repo := m.releaseRepo(cfg)
+		packages, err := selectPackages(repo, nil)
+		if err != nil {
+			return err
+		}
+		plans, err := m.planPackages(packages, "auto")
+		if err != nil {
+			return err
+		}
+		fmt.Println(packageTable(plans).Render())
+		return nil
+	}
+	if args[0] != "release" {
+		return fmt.Errorf("unknown packages command %q, use status or release", args[0])
+	}
+
+	fs := flag.NewFlagSet("packages release", flag.ContinueOnError)
+	bump := fs.String("bump", "auto", "Version bump: auto, patch, minor, major or an explicit version such as v1.2.3")
+	dryRun := fs.Bool("dry-run", false, "Print the tags that would be released without tagging")
+	override := fs.String("override", "", "Comma-separated failed gates to release anyway, or \"all\"")
+	ci := fs.Bool("ci", false, "Require green GitHub checks on HEAD even when the repository does not")
+	signed := fs.Bool("signed", false, "Require signed commits since each package's last tag even when the repository does not")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	overrides, err := parseOverrides(*override)
+	if err != nil {
+		return err
+	}
+	repo := m.releaseRepo(cfg)
+	packages, err := selectPackages(repo, fs.Args())
+	if err != nil {
+		return err
+	}
+	plans, err := m.planPackages(packages, *bump)
+	if err != nil {
+		return err
+	}
+
+	// Without names only the packages changed since their last tag are released
+	if fs.NArg() == 0 {
+		plans = changedPackages(plans)
+		if len(plans) == 0 {
+			return fmt.Errorf("no package has commits that require a release")
+		}
+	}
+	for _, plan := range plans {
+		if plan.Next == "" {
+			return fmt.Errorf("no feat, fix or breaking commits touch %s since %s; pass --bump to release it anyway",
+				plan.Package.Name, valueOr(plan.CurrentTag, "the first commit"))
+		}
+	}
+
+	if *dryRun {
+		for _, plan := range plans {
+			fmt.Printf("Would release %s (%d commits since %s)\n", plan.Tag(), len(plan.Analysis.Commits), valueOr(plan.CurrentTag, "the first commit"))
+			for _, file := range plan.Package.VersionFiles {
+				fmt.Printf("Would set the version in %s\n", filepath.Join(plan.Package.Path, file.Path))
+			}
+		}
+		return nil
+	}
+
+	repo.RequireCI = repo.RequireCI || *ci
+	repo.RequireSigned = repo.RequireSigned || *signed
+	if err := m.enforcePackageGates(cfg, repo, plans, overrides); err != nil {
+		return err
+	}
+	return m.publishPackages(repo, plans)
+}
+
+// runGatesCommand parses the flags of "devtools release-manager gates"
+func (m *Module) runGatesCommand(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("gates", flag.ContinueOnError)
+	versionFlag := fs.String("version", "auto", "Version to check: auto, patch, minor, major, alpha, beta, rc, final or an explicit version")
+	ci := fs.Bool("ci", false, "Require green GitHub checks on HEAD even when the repository does not")
+	signed := fs.Bool("signed", false, "Require signed commits since the last tag even when the repository does not")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	version, err := m.resolveBump(*versionFlag)
+	if err != nil {
+		return err
+	}
+	repo := m.releaseRepo(cfg)
+	repo.RequireCI = repo.RequireCI || *ci
+	repo.RequireSigned = repo.RequireSigned || *signed
+	return m.enforceReleaseGates(cfg, repo, version, nil)
+}
+
+// runBuildCommand parses the flags of "devtools release-manager build"
+func (m *Module) runBuildCommand(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("build", flag.ContinueOnError)
+	version := fs.String("version", "", "Version injected as main.Version (default: git describe)")
+	targets := fs.String("targets", "", "Comma-separated GOOS/GOARCH targets (default: the configured targets)")
+	output := fs.String("output", "", "Output directory (default: the configured output or dist)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	repo := m.releaseRepo(cfg)
+	build, ok := buildSettings(repo)
+	if !ok {
+		return fmt.Errorf("no build configured for %s and it is not a Go module", repo.Path)
+	}
+	if *targets != "" {
+		build.Targets = splitPatterns(*targets)
+	}
+	if *output != "" {
+		build.Output, _ = filepath.Abs(*output)
+	}
+	if *version == "" {
+		*version = m.buildVersion(repo)
+	}
+
+	manifest, err := m.buildRelease(repo, build, *version)
+	if err != nil {
+		return err
+	}
+	showBuildManifest(build, manifest)
+	return nil
+}
+
+// runPipelineCommand runs "devtools release-manager pipeline [step ...]"
+func (m *Module) runPipelineCommand(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("pipeline", flag.ContinueOnError)
+	version := fs.String("version", "", "Version passed to the steps as RELEASE_VERSION")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *version != "" {
+		if err := validateVersion(*version); err != nil {
+			return fmt.Errorf("invalid --version %q: %v", *version, err)
+		}
+	}
+
+	repo := m.releaseRepo(cfg)
+	if len(repo.Steps) == 0 {
+		return fmt.Errorf("no pipeline steps configured for %s", repo.Path)
+	}
+	steps, err := selectSteps(repo, fs.Args())
+	if err != nil {
+		return err
+	}
+	return m.runSteps(repo, steps, *version, false)
+}
+
+// releaseFlags holds the GitHub Release flags shared by "release" and "github-release"
+type releaseFlags struct {
+	draft      *bool
+	prerelease *string
+	latest     *string
+	notesFile  *string
+	assets     *string
+}
+
+// githubReleaseFlags registers the GitHub Release flags of a command
+func githubReleaseFlags(fs *flag.FlagSet) *releaseFlags {
+	return &releaseFlags{
+		draft:      fs.Bool("draft", false, "Save the GitHub Release as a draft"),
+		prerelease: fs.String("prerelease", "auto", "Mark as pre-release: auto (from the version), true or false"),
+		latest:     fs.String("latest", "auto", "Mark as latest: auto (highest normal version), true or false"),
+		notesFile:  fs.String("notes-file", "", "Release notes file (default: the CHANGELOG.md section or generated notes)"),
+		assets:     fs.String("assets", "", "Comma-separated files or globs to upload, e.g. \"dist/*\""),
+	}
+}
+
+// options converts the parsed flags into GitHub Release options for a version
+func (f *releaseFlags) options(version string) (githubReleaseOptions, error) {
+	opts := githubReleaseOptions{Draft: *f.draft, Assets: splitPatterns(*f.assets)}
+
+	switch strings.ToLower(*f.prerelease) {
+	case "auto":
+		v, err := semver.Parse(version)
+		opts.Prerelease = err == nil && v.IsPrerelease()
+	case "true":
+		opts.Prerelease = true
+	case "false":
+	default:
+		return opts, fmt.Errorf("invalid --prerelease %q: use auto, true or false", *f.prerelease)
+	}
+
+	switch strings.ToLower(*f.latest) {
+	case "auto":
+	case "true", "false":
+		opts.Latest = strings.ToLower(*f.latest)
+	default:
+		return opts, fmt.Errorf("invalid --latest %q: use auto, true or false", *f.latest)
+	}
+
+	if *f.notesFile != "" {
+		data, err := os.ReadFile(*f.notesFile)
+		if err != nil {
+			return opts, fmt.Errorf("failed to read notes: %v", err)
+		}
+		opts.Notes = string(data)
+	}
+	return opts, nil
+}
+
+// runGitHubReleaseCommand runs "devtools release-manager github-release <action> <tag>"
+func (m *Module) runGitHubReleaseCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing action: create, delete or latest\n\n%s", commandUsage)
+	}
+
+	action := args[0]
+	if action != "create" && action != "delete" && action != "latest" {
+		return fmt.Errorf("unknown github-release action %q: use create, delete or latest", action)
+	}
+	fs := flag.NewFlagSet("github-release "+action, flag.ContinueOnError)
+	var opts *releaseFlags
+	if action == "create" {
+		opts = githubReleaseFlags(fs)
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("github-release %s needs exactly one tag", action)
+	}
+	tag := fs.Arg(0)
+
+	client, repo, err := m.githubReleaseClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "create":
+		releaseOpts, err := opts.options(tag)
+		if err != nil {
+			return err
+		}
+		release, err := m.publishGitHubRelease(client, repo, tag, releaseOpts)
+		if err != nil {
+			return err
+		}
+		showGitHubRelease(release)
+		return nil
+	case "delete", "latest":
+		release, err := client.getReleaseByTag(repo, tag)
+		if err != nil {
+			return err
+		}
+		if release == nil {
+			return fmt.Errorf("%s has no GitHub Release", tag)
+		}
+		if action == "delete" {
+			if err := client.deleteRelease(repo, release.ID); err != nil {
+				return err
+			}
+			fmt.Printf("Deleted the GitHub Release %s (the tag is kept)\n", tag)
+			return nil
+		}
+		if release.Draft || release.Prerelease {
+			return fmt.Errorf("%s is a draft or pre-release and cannot be the latest release", tag)
+		}
+		_, err = client.updateRelease(repo, release.ID, githubReleaseRequest{
+			Name:       release.Name,
+			Body:       release.Body,
+			MakeLatest: "true",
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s is now the latest release\n", tag)
+		return nil
+	}
+	return nil
+}
+
+// runBumpCommand parses the flags of "devtools release-manager bump"
+func (m *Module) runBumpCommand(args []string) error {
+	fs := flag.NewFlagSet("bump", flag.ContinueOnError)
+	quiet := fs.Bool("quiet", false, "Only print the version")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	currentVersion, _ := m.getCurrentVersion()
+	analysis, err := m.analyzeCommits(currentVersion)
+	if err != nil {
+		return err
+	}
+	if !*quiet {
+		m.showBumpAnalysis(analysis)
+	}
+	if analysis.Bump == BumpNone {
+		return fmt.Errorf("no feat, fix or breaking commits since %s", currentVersion)
+	}
+
+	fmt.Println(m.calculateNextVersions(currentVersion).forBump(analysis.Bump))
+	return nil
+}
+
+// resolveBump turns a --bump value into the version to release
+func (m *Module) resolveBump(bump string) (string, error) {
+	currentVersion, _ := m.getCurrentVersion()
+	nextVersions := m.calculateNextVersions(currentVersion)
+
+	switch strings.ToLower(bump) {
+	case "patch":
+		return nextVersions.Patch, nil
+	case "minor":
+		return nextVersions.Minor, nil
+	case "major":
+		return nextVersions.Major, nil
+	case "alpha":
+		return nextVersions.Alpha, nil
+	case "beta":
+		return nextVersions.Beta, nil
+	case "rc":
+		return nextVersions.RC, nil
+	case "final":
+		if nextVersions.Final == "" {
+			return "", fmt.Errorf("%s is not a pre-release", currentVersion)
+		}
+		return nextVersions.Final, nil
+	case "auto":
+		analysis, err := m.analyzeCommits(currentVersion)
+		if err != nil {
+			return "", err
+		}
+		m.showBumpAnalysis(analysis)
+		if analysis.Bump == BumpNone {
+			return "", fmt.Errorf("no feat, fix or breaking commits since %s, nothing to release", currentVersion)
+		}
+		return nextVersions.forBump(analysis.Bump), nil
+	}
+
+	if err := validateVersion(bump); err != nil {
+		return "", fmt.Errorf("invalid --bump %q: use auto, patch, minor, major, alpha, beta, rc, final or a version (%v)", bump, err)
+	}
+	return bump, nil
+}
+
+// runChangelogCommand parses the flags of "devtools release-manager changelog"
+func (m *Module) runChangelogCommand(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("changelog", flag.ContinueOnError)
+	versionFlag := fs.String("version", "auto", "Version of the entry: auto, patch, minor, major, unreleased or an explicit version")
+	labels := fs.Bool("labels", false, "Group changes by pull request labels instead of commit types")
+	dryRun := fs.Bool("dry-run", false, "Print the entry without writing CHANGELOG.md")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	version := "Unreleased"
+	if !strings.EqualFold(*versionFlag, "unreleased") {
+		resolved, err := m.resolveBump(*versionFlag)
+		if err != nil {
+			return err
+		}
+		version = resolved
+	}
+
+	since := m.changelogSince()
+	commits, err := m.getCommitsSince(since)
+	if err != nil {
+		return err
+	}
+
+	repo, repoErr := m.getGitHubRepo()
+	var github *githubClient
+	if *labels {
+		if cfg.GitHub.Token == "" {
+			return fmt.Errorf("--labels needs a GitHub token in the configuration")
+		}
+		if repoErr != nil {
+			return fmt.Errorf("--labels needs a GitHub origin remote: %v", repoErr)
+		}
+		github = newGitHubClient(cfg.GitHub.Token)
+	}
+
+	entry, err := m.buildChangelogEntry(commits, github, repo)
+	if err != nil {
+		return err
+	}
+	if entry.Empty() {
+		return fmt.Errorf("no changelog-worthy commits since %s", valueOr(since, "the first commit"))
+	}
+
+	if *dryRun {
+		fmt.Printf("## [%s]\n\n%s", version, renderChangelogEntry(entry))
+		return nil
+	}
+
+	if err := m.saveChangelogEntry(version, since, repoOrEmpty(repo, repoErr), entry); err != nil {
+		return err
+	}
+
+	fmt.Printf("CHANGELOG.md updated for %s\n", version)
+	return nil
+}
@@ -0,0 +1,231 @@
+package releasemanager
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/kkz6/devtools/internal/ui"
+)
+
+// BumpLevel is the semantic version component a release increments
+type BumpLevel int
+
+// Bump levels, ordered so that the highest required bump wins
+const (
+	BumpNone BumpLevel = iota
+	BumpPatch
+	BumpMinor
+	BumpMajor
+)
+
+// String returns the name of the bump level
+func (b BumpLevel) String() string {
+	switch b {
+	case BumpPatch:
+		return "patch"
+	case BumpMinor:
+		return "minor"
+	case BumpMajor:
+		return "major"
+	default:
+		return "none"
+	}
+}
+
+// conventionalHeader matches "type(scope)!: subject"
+var conventionalHeader = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^()]*)\))?(!)?: (\S.*)$`)
+
+// breakingFooter matches the BREAKING CHANGE footer of a commit body
+var breakingFooter = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: `)
+
+// conventionalTypes lists the commit types recognised by the analysis
+var conventionalTypes = map[string]BumpLevel{
+	"feat":     BumpMinor,
+	"fix":      BumpPatch,
+	"perf":     BumpPatch,
+	"revert":   BumpPatch,
+	"docs":     BumpNone,
+	"style":    BumpNone,
+	"refactor": BumpNone,
+	"test":     BumpNone,
+	"build":    BumpNone,
+	"ci":       BumpNone,
+	"chore":    BumpNone,
+}
+
+// Commit is a commit parsed as a Conventional Commit
+type Commit struct {
+	Hash       string
+	Header     string
+	Body       string
+	Type       string
+	Scope      string
+	Subject    string
+	Breaking   bool
+	Conforming bool
+	Problem    string // Why a non-conforming commit does not follow the convention
+}
+
+// Bump returns the version bump the commit requires on its own
+func (c Commit) Bump() BumpLevel {
+	if !c.Conforming {
+		return BumpNone
+	}
+	if c.Breaking {
+		return BumpMajor
+	}
+	return conventionalTypes[c.Type]
+}
+
+// BumpAnalysis is the recommended version bump for the commits since the last tag
+type BumpAnalysis struct {
+	Since         string // Tag the commits were read from, or "" for the whole history
+	Commits       []Commit
+	Bump          BumpLevel
+	Reasons       []Commit // Commits requiring the recommended bump
+	NonConforming []Commit
+}
+
+// parseCommit parses the header and body of a commit message
+func parseCommit(hash, header, body string) Commit {
+	commit := Commit{Hash: hash, Header: header, Body: strings.TrimSpace(body)}
+
+	match := conventionalHeader.FindStringSubmatch(header)
+	if match == nil {
+		commit.Problem = "header is not \"type(scope): subject\""
+		return commit
+	}
+
+	commit.Type = strings.ToLower(match[1])
+	commit.Scope = match[2]
+	commit.Subject = match[4]
+	commit.Breaking = match[3] == "!" || breakingFooter.MatchString(commit.Body)
+
+	if _, ok := conventionalTypes[commit.Type]; !ok {
+		commit.Problem = fmt.Sprintf("unknown type %q", match[1])
+		return commit
+	}
+
+	commit.Conforming = true
+	return commit
+}
+
+// getCommitsSince reads the non-merge commits after a tag, or the whole history when the
+// tag is empty. Paths limit the commits to those touching them.
+func (m *Module) getCommitsSince(tag string, paths ...string) ([]Commit, error) {
+	return m.getCommitsBetween(tag, "HEAD", paths...)
+}
+
+// getCommitsBetween reads the non-merge commits after from up to and including until,
+// optionally only those touching paths
+func (m *Module) getCommitsBetween(from, until string, paths ...string) ([]Commit, error) {
+	args := []string{"log", "--no-merges", "--format=%H%x1f%s%x1f%b%x1e"}
+	if from != "" {
+		args = append(args, fmt.Sprintf("%s..%s", from, until))
+	} else {
+		args = append(args, until)
+	}
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commits: %v", err)
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(string(output), "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+		fields := strings.SplitN(record, "\x1f", 3)
+		if len(fields) < 3 {
+			continue
+		}
+		commits = append(commits, parseCommit(fields[0], fields[1], fields[2]))
+	}
+	return commits, nil
+}
+
+// analyzeCommits recommends the version bump for the commits since a tag, optionally
+// only those touching paths
+func (m *Module) analyzeCommits(tag string, paths ...string) (*BumpAnalysis, error) {
+	if tag == "v0.0.0" && m.runCommandSilent("git", "rev-parse", "--verify", "--quiet", "refs/tags/"+tag) != nil {
+		tag = "" // getCurrentVersion's default when the repository has no tags
+	}
+
+	commits, err := m.getCommitsSince(tag, paths...)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := &BumpAnalysis{Since: tag, Commits: commits}
+	for _, commit := range commits {
+		if !commit.Conforming {
+			analysis.NonConforming = append(analysis.NonConforming, commit)
+			continue
+		}
+		bump := commit.Bump()
+		switch {
+		case bump > analysis.Bump:
+			analysis.Bump = bump
+			analysis.Reasons = []Commit{commit}
+		case bump == analysis.Bump && bump != BumpNone:
+			analysis.Reasons = append(analysis.Reasons, commit)
+		}
+	}
+	return analysis, nil
+}
+
+// forBump returns the next version for a bump level
+func (n NextVersions) forBump(bump BumpLevel) string {
+	switch bump {
+	case BumpMajor:
+		return n.Major
+	case BumpMinor:
+		return n.Minor
+	default:
+		return n.Patch
+	}
+}
+
+// showBumpAnalysis prints the recommended bump with the commits that justify it
+func (m *Module) showBumpAnalysis(analysis *BumpAnalysis) {
+	since := analysis.Since
+	if since == "" {
+		since = "the first commit"
+	}
+
+	if len(analysis.Commits) == 0 {
+		ui.ShowWarning(fmt.Sprintf("No commits since %s", since))
+		return
+	}
+
+	if analysis.Bump == BumpNone {
+		ui.ShowInfo(fmt.Sprintf("%d commits since %s, none with feat, fix or breaking changes", len(analysis.Commits), since))
+	} else {
+		table := ui.NewTable(fmt.Sprintf("Recommended: %s release (%d of %d commits since %s)",
+			analysis.Bump, len(analysis.Reasons), len(analysis.Commits), since))
+		table.AddHeader("Commit", "Type", "Scope", "Subject")
+		for _, commit := range analysis.Reasons {
+			commitType := commit.Type
+			if commit.Breaking {
+				commitType += "!"
+			}
+			table.AddRow(commit.Hash[:7], commitType, commit.Scope, commit.Subject)
+		}
+		fmt.Println(table.Render())
+	}
+
+	if len(analysis.NonConforming) > 0 {
+		ui.ShowWarning(fmt.Sprintf("%d commits do not follow Conventional Commits and were not counted:", len(analysis.NonConforming)))
+		for _, commit := range analysis.NonConforming {
+			fmt.Printf("  %s %s (%s)\n", commit.Hash[:7], commit.Header, commit.Problem)
+		}
+		fmt.Println()
+	}
+}
@@ -0,0 +1,385 @@
+package releasemanager
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kkz6/devtools/internal/config"
+	"github.com/kkz6/devtools/internal/ui"
+)
+
+// Release gate IDs, used to override failed gates from the command line
+const (
+	gateClean     = "clean"
+	gateBranch    = "branch"
+	gateRemote    = "remote"
+	gateTag       = "tag"
+	gateChangelog = "changelog"
+	gateTest      = "test"
+	gateLint      = "lint"
+	gateCI        = "ci"
+	gateSigned    = "signed"
+)
+
+// gateIDs lists the release gates in the order they are checked
+var gateIDs = []string{gateClean, gateBranch, gateRemote, gateTag, gateChangelog, gateTest, gateLint, gateCI, gateSigned}
+
+// gateStatus is the outcome of a release gate
+type gateStatus int
+
+const (
+	gatePassed gateStatus = iota
+	gateFailed
+	gateSkipped
+)
+
+// gateResult is the outcome of one release gate
+type gateResult struct {
+	ID     string
+	Name   string
+	Status gateStatus
+	Detail string
+}
+
+// passed, failed and skipped build gate results
+func passed(id, name, detail string) gateResult  { return gateResult{id, name, gatePassed, detail} }
+func failed(id, name, detail string) gateResult  { return gateResult{id, name, gateFailed, detail} }
+func skipped(id, name, detail string) gateResult { return gateResult{id, name, gateSkipped, detail} }
+
+// checkReleaseGates checks every pre-release gate of a version. All gates are checked so
+// that one run reports every problem.
+func (m *Module) checkReleaseGates(cfg *config.Config, repo config.ReleaseRepo, version string) []gateResult {
+	return []gateResult{
+		m.checkCleanTree(),
+		m.checkBranch(repo),
+		m.checkRemote(repo),
+		m.checkTagAbsent(repo, version),
+		m.checkChangelogEntry(repo, version),
+		m.checkStep(gateTest, "Tests pass", repo, version),
+		m.checkStep(gateLint, "Lint passes", repo, version),
+		m.checkCI(cfg, repo.RequireCI),
+		m.checkSignedCommits(repo, m.changelogSince()),
+	}
+}
+
+// checkCleanTree requires a working tree without uncommitted or untracked changes
+func (m *Module) checkCleanTree() gateResult {
+	const name = "Working tree clean"
+	output, err := exec.Command("git", "status", "--porcelain").Output()
+	if err != nil {
+		return failed(gateClean, name, fmt.Sprintf("git status failed: %v", err))
+	}
+	changes := strings.TrimSpace(string(output))
+	if changes == "" {
+		return passed(gateClean, name, "no uncommitted changes")
+	}
+	count := len(strings.Split(changes, "\n"))
+	return failed(gateClean, name, fmt.Sprintf("%d uncommitted or untracked files", count))
+}
+
+// checkBranch requires HEAD to be on the release branch
+func (m *Module) checkBranch(repo config.ReleaseRepo) gateResult {
+	name := fmt.Sprintf("On release branch (%s)", repo.Branch)
+	output, err := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return failed(gateBranch, name, fmt.Sprintf("cannot determine the branch: %v", err))
+	}
+	branch := strings.TrimSpace(string(output))
+	if branch != repo.Branch {
+		return failed(gateBranch, name, fmt.Sprintf("on %s", branch))
+	}
+	return passed(gateBranch, name, branch)
+}
+
+// checkRemote requires the release branch to contain everything on the remote
+func (m *Module) checkRemote(repo config.ReleaseRepo) gateResult {
+	upstream := repo.Remote + "/" + repo.Branch
+	name := fmt.Sprintf("Up to date with %s", upstream)
+
+	if output, err := exec.Command("git", "fetch", "--quiet", repo.Remote, repo.Branch).CombinedOutput(); err != nil {
+		return failed(gateRemote, name, fmt.Sprintf("fetch failed: %s", lastLine(string(output), err)))
+	}
+
+	output, err := exec.Command("git", "rev-list", "--left-right", "--count", "HEAD..."+upstream).Output()
+	if err != nil {
+		return failed(gateRemote, name, fmt.Sprintf("cannot compare with %s: %v", upstream, err))
+	}
+	counts := strings.Fields(string(output))
+	if len(counts) != 2 {
+		return failed(gateRemote, name, fmt.Sprintf("unexpected git output %q", strings.TrimSpace(string(output))))
+	}
+	ahead, _ := strconv.Atoi(counts[0])
+	behind, _ := strconv.Atoi(counts[1])
+
+	switch {
+	case behind > 0:
+		return failed(gateRemote, name, fmt.Sprintf("%d commits behind, pull first", behind))
+	case ahead > 0:
+		return passed(gateRemote, name, fmt.Sprintf("%d local commits are pushed with the release", ahead))
+	}
+	return passed(gateRemote, name, "up to date")
+}
+
+// checkTagAbsent requires the version's tag to exist neither locally nor on the remote
+func (m *Module) checkTagAbsent(repo config.ReleaseRepo, version string) gateResult {
+	name := fmt.Sprintf("Tag %s is new", version)
+	if m.runCommandSilent("git", "rev-parse", "--verify", "--quiet", "refs/tags/"+version) == nil {
+		return failed(gateTag, name, "tag exists locally")
+	}
+
+	output, err := exec.Command("git", "ls-remote", "--tags", repo.Remote, "refs/tags/"+version).Output()
+	if err != nil {
+		return failed(gateTag, name, fmt.Sprintf("cannot list tags of %s: %v", repo.Remote, err))
+	}
+	if strings.TrimSpace(string(output)) != "" {
+		return failed(gateTag, name, fmt.Sprintf("tag exists on %s", repo.Remote))
+	}
+	return passed(gateTag, name, "absent locally and on "+repo.Remote)
+}
+
+// checkChangelogEntry requires CHANGELOG.md to have a non-empty section for the version
+func (m *Module) checkChangelogEntry(repo config.ReleaseRepo, version string) gateResult {
+	name := "CHANGELOG.md entry"
+	content, err := os.ReadFile(filepath.Join(repo.Path, "CHANGELOG.md"))
+	if err != nil {
+		return failed(gateChangelog, name, "CHANGELOG.md not found")
+	}
+	section, ok := changelogVersionSection(string(content), version)
+	switch {
+	case !ok:
+		return failed(gateChangelog, name, fmt.Sprintf("no section for %s, generate one from the commits", version))
+	case strings.TrimSpace(section) == "":
+		return failed(gateChangelog, name, fmt.Sprintf("the %s section is empty", version))
+	}
+	return passed(gateChangelog, name, fmt.Sprintf("section for %s found", version))
+}
+
+// checkStep requires the pipeline step named like the gate to succeed; repositories
+// without that step skip the gate. Failures of steps with on_failure continue only warn.
+func (m *Module) checkStep(id, name string, repo config.ReleaseRepo, version string) gateResult {
+	step, ok := findStep(repo, id)
+	if !ok {
+		return skipped(id, name, fmt.Sprintf("no %s step configured", id))
+	}
+	if err := validateStep(step); err != nil {
+		return failed(id, name, err.Error())
+	}
+
+	output, err := stepCommand(repo, step, version).CombinedOutput()
+	if err != nil && step.OnFailure == failureContinue {
+		return skipped(id, name, fmt.Sprintf("failure tolerated by on_failure continue: %s", lastLine(string(output), err)))
+	}
+	if err != nil {
+		return failed(id, name, fmt.Sprintf("%s: %s", step.Run, lastLine(string(output), err)))
+	}
+	return passed(id, name, step.Run)
+}
+
+// checkCI requires every GitHub check run and commit status of HEAD to have succeeded
+func (m *Module) checkCI(cfg *config.Config, required bool) gateResult {
+	const name = "CI green on HEAD"
+	if !required {
+		return skipped(gateCI, name, "not required")
+	}
+
+	client, repo, err := m.githubReleaseClient(cfg)
+	if err != nil {
+		return failed(gateCI, name, err.Error())
+	}
+	output, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return failed(gateCI, name, fmt.Sprintf("cannot resolve HEAD: %v", err))
+	}
+	sha := strings.TrimSpace(string(output))
+
+	runs, err := client.getCheckRuns(repo, sha)
+	if err != nil {
+		// GitHub answers 404 or 422 for commits it has not received
+		if apiErr, ok := err.(*githubAPIError); ok && (apiErr.StatusCode == 404 || apiErr.StatusCode == 422) {
+			return failed(gateCI, name, fmt.Sprintf("%s is not on GitHub yet, push it and wait for CI", sha[:7]))
+		}
+		return failed(gateCI, name, err.Error())
+	}
+	status, err := client.getCombinedStatus(repo, sha)
+	if err != nil {
+		return failed(gateCI, name, err.Error())
+	}
+
+	var pending, failing []string
+	for _, run := range runs.CheckRuns {
+		switch {
+		case run.Status != "completed":
+			pending = append(pending, run.Name)
+		case run.Conclusion != "success" && run.Conclusion != "neutral" && run.Conclusion != "skipped":
+			failing = append(failing, fmt.Sprintf("%s (%s)", run.Name, run.Conclusion))
+		}
+	}
+	for _, s := range status.Statuses {
+		switch s.State {
+		case "pending":
+			pending = append(pending, s.Context)
+		case "failure", "error":
+			failing = append(failing, fmt.Sprintf("%s (%s)", s.Context, s.State))
+		}
+	}
+
+	total := len(runs.CheckRuns) + len(status.Statuses)
+	switch {
+	case len(failing) > 0:
+		return failed(gateCI, name, "failing: "+strings.Join(failing, ", "))
+	case len(pending) > 0:
+		return failed(gateCI, name, "still running: "+strings.Join(pending, ", "))
+	case total == 0:
+		return failed(gateCI, name, fmt.Sprintf("no checks reported for %s", sha[:7]))
+	}
+	return passed(gateCI, name, fmt.Sprintf("%d checks passed on %s", total, sha[:7]))
+}
+
+// checkSignedCommits requires every commit since a tag, optionally only those touching
+// paths, to have a valid signature
+func (m *Module) checkSignedCommits(repo config.ReleaseRepo, since string, paths ...string) gateResult {
+	const name = "Commits since the last tag signed"
+	if !repo.RequireSigned {
+		return skipped(gateSigned, name, "not required")
+	}
+
+	args, cleanup, err := verifyArgs()
+	if err != nil {
+		return failed(gateSigned, name, fmt.Sprintf("cannot prepare signature verification: %v", err))
+	}
+	defer cleanup()
+
+	unsigned, err := unsignedCommitsSince(since, args, paths...)
+	if err != nil {
+		return failed(gateSigned, name, err.Error())
+	}
+	if len(unsigned) > 0 {
+		first := unsigned[0]
+		detail := fmt.Sprintf("%d commits without a valid signature, e.g. %s %s (%s)", len(unsigned), first.Hash, first.Subject, first.Status)
+		return failed(gateSigned, name, detail)
+	}
+	return passed(gateSigned, name, fmt.Sprintf("all commits since %s signed", valueOr(since, "the first commit")))
+}
+
+// lastLine returns the last non-empty line of command output, or the error
+func lastLine(output string, err error) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if line := strings.TrimSpace(lines[i]); line != "" {
+			if len(line) > 100 {
+				line = line[:97] + "..."
+			}
+			return line
+		}
+	}
+	return err.Error()
+}
+
+// parseOverrides parses a comma-separated list of gate IDs, or "all"
+func parseOverrides(value string) (map[string]bool, error) {
+	overrides := make(map[string]bool)
+	for _, id := range strings.Split(value, ",") {
+		id = strings.ToLower(strings.TrimSpace(id))
+		switch {
+		case id == "":
+			continue
+		case id == "all":
+			for _, gate := range gateIDs {
+				overrides[gate] = true
+			}
+		case containsString(gateIDs, id):
+			overrides[id] = true
+		default:
+			return nil, fmt.Errorf("unknown gate %q, use %s or all", id, strings.Join(gateIDs, ", "))
+		}
+	}
+	return overrides, nil
+}
+
+// containsString reports whether a slice contains a string
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// blockingGates returns the failed gates that were not overridden
+func blockingGates(results []gateResult, overrides map[string]bool) []gateResult {
+	var blocking []gateResult
+	for _, result := range results {
+		if result.Status == gateFailed && !overrides[result.ID] {
+			blocking = append(blocking, result)
+		}
+	}
+	return blocking
+}
+
+// gateTable renders gate results, marking overridden failures
+func gateTable(title string, results []gateResult, overrides map[string]bool) *ui.Table {
+	table := ui.NewTable(title)
+	table.AddHeader("Gate", "ID", "Result", "Details")
+	for _, result := range results {
+		status := "✅ pass"
+		switch {
+		case result.Status == gateSkipped:
+			status = "⏭  skipped"
+		case result.Status == gateFailed && overrides[result.ID]:
+			status = "⚠️  overridden"
+		case result.Status == gateFailed:
+			status = "❌ fail"
+		}
+		table.AddRow(result.Name, result.ID, status, result.Detail)
+	}
+	return table
+}
+
+// gateIDList joins the IDs of gate results
+func gateIDList(results []gateResult) string {
+	ids := make([]string, 0, len(results))
+	for _, result := range results {
+		ids = append(ids, result.ID)
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+// enforceReleaseGates checks the release gates of a version and fails when a gate failed
+// that was not explicitly overridden
+func (m *Module) enforceReleaseGates(cfg *config.Config, repo config.ReleaseRepo, version string, overrides map[string]bool) error {
+	return enforceGates(fmt.Sprintf("Release Gates for %s", version), func() []gateResult {
+		return m.checkReleaseGates(cfg, repo, version)
+	}, overrides)
+}
+
+// enforceGates runs gate checks and fails unless every failed gate is overridden
+func enforceGates(title string, check func() []gateResult, overrides map[string]bool) error {
+	var results []gateResult
+	_ = ui.ShowLoadingAnimation("Checking release gates", func() error {
+		results = check()
+		return nil
+	})
+
+	fmt.Println()
+	fmt.Println(gateTable(title, results, overrides).Render())
+	fmt.Println()
+
+	if blocking := blockingGates(results, overrides); len(blocking) > 0 {
+		return fmt.Errorf("%d release gates failed (%s); fix them or override explicitly with --override %s",
+			len(blocking), gateIDList(blocking), gateIDList(blocking))
+	}
+
+	for _, result := range results {
+		if result.Status == gateFailed {
+			ui.ShowWarning(fmt.Sprintf("⚠️  Gate %q overridden: %s", result.ID, result.Detail))
+		}
+	}
+	return nil
+}
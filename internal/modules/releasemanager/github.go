@@ -0,0 +1,315 @@
+package releasemanager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const githubAPIURL = "https://api.github.com"
+
+// githubClient is a minimal GitHub REST API client for release automation
+type githubClient struct {
+	token  string
+	client *http.Client
+}
+
+// newGitHubClient creates a GitHub client authenticated with a token
+func newGitHubClient(token string) *githubClient {
+	return &githubClient{
+		token:  token,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// githubLabel is a label of an issue or pull request
+type githubLabel struct {
+	Name string `json:"name"`
+}
+
+// githubPullRequest is a pull request as returned by the GitHub REST API
+type githubPullRequest struct {
+	Number   int           `json:"number"`
+	Title    string        `json:"title"`
+	HTMLURL  string        `json:"html_url"`
+	Labels   []githubLabel `json:"labels"`
+	MergedAt *time.Time    `json:"merged_at"`
+}
+
+// githubRelease is a release as returned by the GitHub REST API
+type githubRelease struct {
+	ID          int64         `json:"id"`
+	TagName     string        `json:"tag_name"`
+	Name        string        `json:"name"`
+	Body        string        `json:"body"`
+	Draft       bool          `json:"draft"`
+	Prerelease  bool          `json:"prerelease"`
+	HTMLURL     string        `json:"html_url"`
+	UploadURL   string        `json:"upload_url"`
+	Assets      []githubAsset `json:"assets"`
+	CreatedAt   time.Time     `json:"created_at"`
+	PublishedAt *time.Time    `json:"published_at"`
+}
+
+// githubAsset is a file attached to a release
+type githubAsset struct {
+	ID                 int64  `json:"id"`
+	Name               string `json:"name"`
+	Size               int64  `json:"size"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// githubReleaseRequest creates or updates a release. MakeLatest is "true", "false" or
+// "legacy" (latest by date and semver); empty leaves GitHub's default.
+type githubReleaseRequest struct {
+	TagName         string `json:"tag_name,omitempty"`
+	TargetCommitish string `json:"target_commitish,omitempty"`
+	Name            string `json:"name,omitempty"`
+	Body            string `json:"body"`
+	Draft           bool   `json:"draft"`
+	Prerelease      bool   `json:"prerelease"`
+	MakeLatest      string `json:"make_latest,omitempty"`
+}
+
+// githubAPIError is an unsuccessful GitHub API response
+type githubAPIError struct {
+	StatusCode int
+	Message    string
+}
+
+// Error implements the error interface
+func (e *githubAPIError) Error() string {
+	return fmt.Sprintf("GitHub API error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// isNotFound reports whether an error is a GitHub 404 response
+func isNotFound(err error) bool {
+	apiErr, ok := err.(*githubAPIError)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// do sends a JSON request to the GitHub API and decodes the response into out
+func (c *githubClient) do(method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	rawURL := path
+	if !strings.HasPrefix(rawURL, "http") {
+		rawURL = githubAPIURL + path
+	}
+
+	req, err := http.NewRequest(method, rawURL, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "token "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return c.send(req, out)
+}
+
+// send performs a prepared request and decodes the JSON response into out
+func (c *githubClient) send(req *http.Request, out interface{}) error {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("GitHub request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return &githubAPIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode GitHub response: %v", err)
+	}
+	return nil
+}
+
+// getCommitPullRequests returns the pull requests a commit belongs to
+func (c *githubClient) getCommitPullRequests(repo, sha string) ([]githubPullRequest, error) {
+	var pulls []githubPullRequest
+	if err := c.do("GET", fmt.Sprintf("/repos/%s/commits/%s/pulls", repo, sha), nil, &pulls); err != nil {
+		return nil, err
+	}
+	return pulls, nil
+}
+
+// getReleaseByTag returns the release of a tag, or nil when the tag has no published
+// release. Draft releases are not returned by this endpoint, so they are looked up in the list.
+func (c *githubClient) getReleaseByTag(repo, tag string) (*githubRelease, error) {
+	var release githubRelease
+	err := c.do("GET", fmt.Sprintf("/repos/%s/releases/tags/%s", repo, url.PathEscape(tag)), nil, &release)
+	if err == nil {
+		return &release, nil
+	}
+	if !isNotFound(err) {
+		return nil, err
+	}
+
+	releases, err := c.listReleases(repo)
+	if err != nil {
+		return nil, err
+	}
+	for i := range releases {
+		if releases[i].TagName == tag {
+			return &releases[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// listReleases returns the most recent releases of a repository, drafts included
+func (c *githubClient) listReleases(repo string) ([]githubRelease, error) {
+	var releases []githubRelease
+	if err := c.do("GET", fmt.Sprintf("/repos/%s/releases?per_page=100", repo), nil, &releases); err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
+
+// getLatestRelease returns the release GitHub shows as latest, or nil when there is none
+func (c *githubClient) getLatestRelease(repo string) (*githubRelease, error) {
+	var release githubRelease
+	if err := c.do("GET", fmt.Sprintf("/repos/%s/releases/latest", repo), nil, &release); err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &release, nil
+}
+
+// createRelease creates a release
+func (c *githubClient) createRelease(repo string, req githubReleaseRequest) (*githubRelease, error) {
+	var release githubRelease
+	if err := c.do("POST", fmt.Sprintf("/repos/%s/releases", repo), req, &release); err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// updateRelease updates a release
+func (c *githubClient) updateRelease(repo string, id int64, req githubReleaseRequest) (*githubRelease, error) {
+	var release githubRelease
+	if err := c.do("PATCH", fmt.Sprintf("/repos/%s/releases/%d", repo, id), req, &release); err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// deleteRelease deletes a release; the tag is kept
+func (c *githubClient) deleteRelease(repo string, id int64) error {
+	return c.do("DELETE", fmt.Sprintf("/repos/%s/releases/%d", repo, id), nil, nil)
+}
+
+// deleteAsset deletes a release asset
+func (c *githubClient) deleteAsset(repo string, id int64) error {
+	return c.do("DELETE", fmt.Sprintf("/repos/%s/releases/assets/%d", repo, id), nil, nil)
+}
+
+// uploadAsset uploads a file to a release, replacing an existing asset of the same name
+func (c *githubClient) uploadAsset(repo string, release *githubRelease, path string) (*githubAsset, error) {
+	name := filepath.Base(path)
+	for _, asset := range release.Assets {
+		if asset.Name == name {
+			if err := c.deleteAsset(repo, asset.ID); err != nil {
+				return nil, fmt.Errorf("failed to replace %s: %v", name, err)
+			}
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	// The upload URL is a template such as ".../assets{?name,label}"
+	uploadURL := release.UploadURL
+	if i := strings.Index(uploadURL, "{"); i != -1 {
+		uploadURL = uploadURL[:i]
+	}
+	req, err := http.NewRequest("POST", uploadURL+"?name="+url.QueryEscape(name), file)
+	if err != nil {
+		return nil, err
+	}
+	contentType := mime.TypeByExtension(filepath.Ext(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Authorization", "token "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", contentType)
+
+	// Uploads of large binaries outlast the API timeout
+	uploader := &githubClient{token: c.token, client: &http.Client{Timeout: 30 * time.Minute}}
+	var asset githubAsset
+	if err := uploader.send(req, &asset); err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
+
+// githubCheckRuns is the list of check runs of a commit
+type githubCheckRuns struct {
+	TotalCount int `json:"total_count"`
+	CheckRuns  []struct {
+		Name       string `json:"name"`
+		Status     string `json:"status"`     // "queued", "in_progress" or "completed"
+		Conclusion string `json:"conclusion"` // "success", "failure", "neutral", "skipped", ...
+	} `json:"check_runs"`
+}
+
+// githubCombinedStatus is the combined commit status reported by external CI services
+type githubCombinedStatus struct {
+	State    string `json:"state"` // "success", "pending" or "failure"
+	Statuses []struct {
+		Context string `json:"context"`
+		State   string `json:"state"`
+	} `json:"statuses"`
+}
+
+// getCheckRuns returns the check runs of a commit
+func (c *githubClient) getCheckRuns(repo, ref string) (*githubCheckRuns, error) {
+	var runs githubCheckRuns
+	if err := c.do("GET", fmt.Sprintf("/repos/%s/commits/%s/check-runs?per_page=100", repo, ref), nil, &runs); err != nil {
+		return nil, err
+	}
+	return &runs, nil
+}
+
+// getCombinedStatus returns the combined status of a commit
+func (c *githubClient) getCombinedStatus(repo, ref string) (*githubCombinedStatus, error) {
+	var status githubCombinedStatus
+	if err := c.do("GET", fmt.Sprintf("/repos/%s/commits/%s/status", repo, ref), nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/kkz6/devtools/internal/semver"
 	"github.com/kkz6/devtools/internal/ui"
 )
 
@@ -69,9 +70,7 @@ func (m *Module) listTags() error {
 		return m.compareVersions(tags[i], tags[j]) < 0
 	})
 
-	for i, tag := range tags {
-		fmt.Printf("  %d. %s\n", i+1, tag)
-	}
+	showTagSignatures(tags)
 
 	fmt.Println()
 	ui.ShowInfo("Press Enter to continue...")
@@ -79,6 +78,29 @@ func (m *Module) listTags() error {
 	return nil
 }
 
+// showTagSignatures renders tags with the verification result of their signatures
+func showTagSignatures(tags []string) {
+	args, cleanup, err := verifyArgs()
+	if err != nil {
+		ui.ShowWarning(fmt.Sprintf("Cannot prepare SSH signature verification: %v", err))
+	}
+	defer cleanup()
+
+	table := ui.NewTable("Tags")
+	table.AddHeader("#", "Tag", "Signature", "Signer", "Details")
+	_ = ui.ShowLoadingAnimation("Verifying tag signatures", func() error {
+		for i, tag := range tags {
+			signature := verifyTag(tag, args)
+			table.AddRow(fmt.Sprintf("%d", i+1), tag, signatureLabel(signature.State), valueOr(signature.Signer, "-"), valueOr(signature.Detail, "-"))
+		}
+		return nil
+	})
+	fmt.Println(table.Render())
+	if signingFormat() == "ssh" && gitConfig("gpg.ssh.allowedSignersFile") == "" {
+		ui.ShowInfo("SSH signatures are checked against your own signing key; set gpg.ssh.allowedSignersFile to trust other signers")
+	}
+}
+
 // deleteTag deletes a git tag
 func (m *Module) deleteTag() error {
 	fmt.Println()
@@ -155,108 +177,6 @@ func (m *Module) deleteTag() error {
 	})
 }
 
-// buildLocal builds the project locally
-func (m *Module) buildLocal() error {
-	fmt.Println()
-	ui.ShowInfo("🔨 Building DevTools locally...")
-	fmt.Println()
-
-	return ui.ShowLoadingAnimation("Building", func() error {
-		if err := m.runCommand("go", "build", "-v", "-o", "devtools", "."); err != nil {
-			return fmt.Errorf("build failed: %v", err)
-		}
-
-		ui.ShowSuccess("✅ Build complete: ./devtools")
-		return nil
-	})
-}
-
-// installLocal installs the built binary locally
-func (m *Module) installLocal() error {
-	fmt.Println()
-
-	// First build if binary doesn't exist
-	if _, err := os.Stat("devtools"); os.IsNotExist(err) {
-		ui.ShowInfo("Binary not found, building first...")
-		if err := m.buildLocal(); err != nil {
-			return err
-		}
-	}
-
-	ui.ShowInfo("📦 Installing DevTools to /usr/local/bin...")
-	fmt.Println()
-
-	return ui.ShowLoadingAnimation("Installing", func() error {
-		if err := m.runCommand("sudo", "cp", "devtools", "/usr/local/bin/"); err != nil {
-			return fmt.Errorf("installation failed: %v", err)
-		}
-
-		ui.ShowSuccess("✅ Installed successfully to /usr/local/bin/devtools")
-		return nil
-	})
-}
-
-// runTests runs the test suite
-func (m *Module) runTests() error {
-	fmt.Println()
-	ui.ShowInfo("🧪 Running tests...")
-	fmt.Println()
-
-	return ui.ShowLoadingAnimation("Testing", func() error {
-		if err := m.runCommand("go", "test", "-v", "./..."); err != nil {
-			return fmt.Errorf("tests failed: %v", err)
-		}
-
-		ui.ShowSuccess("✅ All tests passed")
-		return nil
-	})
-}
-
-// runLinter runs the linter
-func (m *Module) runLinter() error {
-	fmt.Println()
-	ui.ShowInfo("🔍 Running linter...")
-	fmt.Println()
-
-	// Check if golangci-lint is available
-	if err := m.runCommandSilent("which", "golangci-lint"); err != nil {
-		ui.ShowError("golangci-lint not found")
-		ui.ShowInfo("Install with: brew install golangci-lint")
-		return nil
-	}
-
-	return ui.ShowLoadingAnimation("Linting", func() error {
-		if err := m.runCommand("golangci-lint", "run"); err != nil {
-			return fmt.Errorf("linter found issues: %v", err)
-		}
-
-		ui.ShowSuccess("✅ Linter passed")
-		return nil
-	})
-}
-
-// cleanArtifacts cleans build artifacts
-func (m *Module) cleanArtifacts() error {
-	fmt.Println()
-	ui.ShowInfo("🧹 Cleaning build artifacts...")
-	fmt.Println()
-
-	return ui.ShowLoadingAnimation("Cleaning", func() error {
-		// Remove binary
-		if err := os.Remove("devtools"); err != nil && !os.IsNotExist(err) {
-			return fmt.Errorf("failed to remove binary: %v", err)
-		}
-
-		// Remove dist directory if it exists
-		if err := os.RemoveAll("dist/"); err != nil && !os.IsNotExist(err) {
-			return fmt.Errorf("failed to remove dist directory: %v", err)
-		}
-
-		ui.ShowSuccess("✅ Clean complete")
-		return nil
-	})
-}
-
 // openChangelog opens the changelog for editing
 func (m *Module) openChangelog() error {
 	fmt.Println()
@@ -382,50 +302,27 @@ func (m *Module) openGitHubPulls() error {
 	return m.runCommand("open", url)
 }
 
-// generateReleaseNotes generates release notes from git log
+// generateReleaseNotes prints release notes grouped from the commits since the last tag
 func (m *Module) generateReleaseNotes() error {
 	fmt.Println()
 
-	currentVersion, err := m.getCurrentVersion()
-	if err != nil {
+	since := m.changelogSince()
+	if since == "" {
 		ui.ShowInfo("No previous version found, showing all commits")
-		currentVersion = ""
 	}
 
-	ui.ShowInfo(fmt.Sprintf("📝 Release notes since %s:", currentVersion))
-	fmt.Println()
-
-	var cmd *exec.Cmd
-	if currentVersion != "" {
-		cmd = exec.Command("git", "log", fmt.Sprintf("%s..HEAD", currentVersion), "--pretty=format:- %s", "--reverse")
-	} else {
-		cmd = exec.Command("git", "log", "--pretty=format:- %s", "--reverse")
-	}
-
-	output, err := cmd.Output()
+	notes, err := m.releaseNotes(since, "HEAD")
 	if err != nil {
-		return fmt.Errorf("failed to generate release notes: %v", err)
+		return err
 	}
 
-	notes := string(output)
-
-	// Filter out merge commits
-	lines := strings.Split(notes, "\n")
-	var filteredLines []string
-	for _, line := range lines {
-		if !strings.Contains(strings.ToLower(line), "merge") {
-			filteredLines = append(filteredLines, line)
-		}
-	}
+	ui.ShowInfo(fmt.Sprintf("📝 Release notes since %s:", valueOr(since, "the first commit")))
+	fmt.Println()
 
-	if len(filteredLines) == 0 {
+	if notes == "" {
 		ui.ShowInfo("No new commits found")
 	} else {
-		for _, line := range filteredLines {
-			if strings.TrimSpace(line) != "" {
-				fmt.Println(line)
-			}
-		}
+		fmt.Print(notes)
 	}
 
 	fmt.Println()
@@ -477,12 +374,7 @@ func (m *Module) updateChangelog() error {
 			"Enter version (e.g., v1.2.3)",
 			"",
 			false,
-			func(s string) error {
-				if !strings.HasPrefix(s, "v") {
-					return fmt.Errorf("version must start with 'v'")
-				}
-				return nil
-			},
+			validateVersion,
 		)
 		if err != nil {
 			return err
@@ -574,7 +466,7 @@ func (m *Module) updateChangelog() error {
 
 // insertChangelogEntry inserts a new entry into the changelog content
 func (m *Module) insertChangelogEntry(content, version, changeType string, changes []string) string {
-	lines := strings.Split(content, "\n")
+	lines := strings.Split(ensureUnreleased(content), "\n")
 	var result []string
 	inserted := false
 	currentDate := time.Now().Format("2006-01-02")
@@ -586,48 +478,54 @@ func (m *Module) insertChangelogEntry(content, version, changeType string, chang
 				// Add to existing Unreleased section
 				result = append(result, line)
 
+				// The section ends at the next release heading or the link references
+				end := i + 1
+				for end < len(lines) && !strings.HasPrefix(lines[end], "## ") && !changelogLinkRef.MatchString(lines[end]) {
+					end++
+				}
+
 				// Find or create the change type section
-				changeTypeFound := false
-				j := i + 1
-				for j < len(lines) && !strings.HasPrefix(lines[j], "##") {
-					if strings.HasPrefix(lines[j], "### "+changeType) {
-						changeTypeFound = true
-						// Add changes under this section
-						result = append(result, lines[j])
+				for j := i + 1; j < end; j++ {
+					if strings.TrimSpace(lines[j]) == "### "+changeType {
+						result = append(result, lines[i+1:j+1]...)
 						j++
 						// Skip empty lines
-						for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+						for j < end && strings.TrimSpace(lines[j]) == "" {
 							result = append(result, lines[j])
 							j++
 						}
-						// Add new changes
+						if strings.TrimSpace(result[len(result)-1]) != "" {
+							result = append(result, "")
+						}
+						// Add new changes before the existing ones
 						for _, change := range changes {
 							result = append(result, fmt.Sprintf("- %s", change))
 						}
-						// Continue with the rest
-						for k := j; k < len(lines); k++ {
-							result = append(result, lines[k])
+						if j < len(lines) && (j == end || strings.HasPrefix(lines[j], "### ")) {
+							result = append(result, "")
 						}
-						return strings.Join(result, "\n")
+						// Continue with the rest
+						return strings.Join(append(result, lines[j:]...), "\n")
 					}
-					result = append(result, lines[j])
-					j++
 				}
 
-				if !changeTypeFound {
-					// Add new change type section
-					result = append(result, "")
-					result = append(result, "### "+changeType)
+				// Add new change type section at the end of the Unreleased section
+				body := lines[i+1 : end]
+				for len(body) > 0 && strings.TrimSpace(body[len(body)-1]) == "" {
+					body = body[:len(body)-1]
+				}
+				result = append(result, body...)
+				result = append(result, "")
+				result = append(result, "### "+changeType)
+				result = append(result, "")
+				for _, change := range changes {
+					result = append(result, fmt.Sprintf("- %s", change))
+				}
+				if end < len(lines) {
 					result = append(result, "")
-					for _, change := range changes {
-						result = append(result, fmt.Sprintf("- %s", change))
-					}
-					// Continue with the rest
-					for k := j; k < len(lines); k++ {
-						result = append(result, lines[k])
-					}
-					return strings.Join(result, "\n")
 				}
+				// Continue with the rest
+				return strings.Join(append(result, lines[end:]...), "\n")
 			} else if strings.HasPrefix(line, "## [Unreleased]") && version != "Unreleased" {
 				// Insert new version section after Unreleased
 				result = append(result, line)
@@ -691,18 +589,9 @@ func (m *Module) getGitHubRepo() (string, error) {
 	return "", fmt.Errorf("not a GitHub repository")
 }
 
-// compareVersions compares two version strings
+// compareVersions compares two version strings by semantic version precedence
 func (m *Module) compareVersions(v1, v2 string) int {
-	version1 := m.parseVersion(v1)
-	version2 := m.parseVersion(v2)
-
-	if version1.Major != version2.Major {
-		return version1.Major - version2.Major
-	}
-	if version1.Minor != version2.Minor {
-		return version1.Minor - version2.Minor
-	}
-	return version1.Patch - version2.Patch
+	return semver.Compare(v1, v2)
 }
 
 // createDefaultChangelog creates a default changelog if it doesn't exist
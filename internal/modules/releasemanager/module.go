@@ -4,11 +4,11 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/kkz6/devtools/internal/config"
+	"github.com/kkz6/devtools/internal/semver"
 	"github.com/kkz6/devtools/internal/types"
 	"github.com/kkz6/devtools/internal/ui"
 )
@@ -62,7 +62,7 @@ func (m *Module) Execute(cfg *config.Config) error {
 
 		switch choice {
 		case 0:
-			if err := m.handleReleaseMenu(); err != nil {
+			if err := m.handleReleaseMenu(cfg); err != nil {
 				ui.ShowError(fmt.Sprintf("Release error: %v", err))
 			}
 		case 1:
@@ -70,7 +70,7 @@ func (m *Module) Execute(cfg *config.Config) error {
 				ui.ShowError(fmt.Sprintf("Tag management error: %v", err))
 			}
 		case 2:
-			if err := m.handleDevelopmentMenu(); err != nil {
+			if err := m.handleDevelopmentMenu(cfg); err != nil {
 				ui.ShowError(fmt.Sprintf("Development error: %v", err))
 			}
 		case 3:
@@ -78,7 +78,7 @@ func (m *Module) Execute(cfg *config.Config) error {
 				ui.ShowError(fmt.Sprintf("Git operation error: %v", err))
 			}
 		case 4:
-			if err := m.handleGitHubMenu(); err != nil {
+			if err := m.handleGitHubMenu(cfg); err != nil {
 				ui.ShowError(fmt.Sprintf("GitHub integration error: %v", err))
 			}
 		case 5:
@@ -91,8 +91,19 @@ func (m *Module) Execute(cfg *config.Config) error {
 	}
 }
 
+// releaseTarget is a version offered by the release menu
+type releaseTarget struct {
+	label   string
+	version string
+	bump    BumpLevel
+}
+
 // handleReleaseMenu handles release creation
-func (m *Module) handleReleaseMenu() error {
+func (m *Module) handleReleaseMenu(cfg *config.Config) error {
+	if repo := m.releaseRepo(cfg); len(repo.Packages) > 0 {
+		return m.handlePackageReleaseMenu(cfg, repo)
+	}
+
 	fmt.Println()
 	currentVersion, err := m.getCurrentVersion()
 	if err != nil {
@@ -104,47 +115,52 @@ func (m *Module) handleReleaseMenu() error {
 	ui.ShowInfo(fmt.Sprintf("Current Version: %s", currentVersion))
 	fmt.Println()
 
-	options := []string{
-		fmt.Sprintf("Patch Release (%s) - Bug fixes", nextVersions.Patch),
-		fmt.Sprintf("Minor Release (%s) - New features", nextVersions.Minor),
-		fmt.Sprintf("Major Release (%s) - Breaking changes", nextVersions.Major),
-		fmt.Sprintf("Alpha Release (%s-alpha.1)", nextVersions.Minor),
-		fmt.Sprintf("Beta Release (%s-beta.1)", nextVersions.Minor),
-		fmt.Sprintf("RC Release (%s-rc.1)", nextVersions.Minor),
-		"Custom Version",
-		"Back",
+	// Recommend a bump from the Conventional Commits since the last tag
+	analysis, err := m.analyzeCommits(currentVersion)
+	if err != nil {
+		ui.ShowWarning(fmt.Sprintf("Could not analyze commits: %v", err))
+	} else {
+		m.showBumpAnalysis(analysis)
 	}
 
-	choice, err := ui.SelectFromList("Select release type:", options)
-	if err != nil || choice == 7 {
+	var targets []releaseTarget
+	if nextVersions.Final != "" {
+		targets = append(targets, releaseTarget{label: fmt.Sprintf("Promote to Final (%s)", nextVersions.Final), version: nextVersions.Final})
+	}
+	targets = append(targets,
+		releaseTarget{label: fmt.Sprintf("Patch Release (%s) - Bug fixes", nextVersions.Patch), version: nextVersions.Patch, bump: BumpPatch},
+		releaseTarget{label: fmt.Sprintf("Minor Release (%s) - New features", nextVersions.Minor), version: nextVersions.Minor, bump: BumpMinor},
+		releaseTarget{label: fmt.Sprintf("Major Release (%s) - Breaking changes", nextVersions.Major), version: nextVersions.Major, bump: BumpMajor},
+		releaseTarget{label: fmt.Sprintf("Alpha Release (%s)", nextVersions.Alpha), version: nextVersions.Alpha},
+		releaseTarget{label: fmt.Sprintf("Beta Release (%s)", nextVersions.Beta), version: nextVersions.Beta},
+		releaseTarget{label: fmt.Sprintf("RC Release (%s)", nextVersions.RC), version: nextVersions.RC},
+		releaseTarget{label: "Custom Version"},
+	)
+
+	options := make([]string, 0, len(targets)+1)
+	recommended := 0
+	for i, target := range targets {
+		label := target.label
+		if analysis != nil && analysis.Bump != BumpNone && target.bump == analysis.Bump {
+			label += " (recommended)"
+			recommended = i
+		}
+		options = append(options, label)
+	}
+	options = append(options, "Back")
+
+	choice, err := ui.SelectFromListWithDefault("Select release type:", options, recommended)
+	if err != nil || choice == len(targets) {
 		return nil
 	}
 
-	var targetVersion string
-	switch choice {
-	case 0:
-		targetVersion = nextVersions.Patch
-	case 1:
-		targetVersion = nextVersions.Minor
-	case 2:
-		targetVersion = nextVersions.Major
-	case 3:
-		targetVersion = nextVersions.Minor + "-alpha.1"
-	case 4:
-		targetVersion = nextVersions.Minor + "-beta.1"
-	case 5:
-		targetVersion = nextVersions.Minor + "-rc.1"
-	case 6:
+	targetVersion := targets[choice].version
+	if targetVersion == "" {
 		customVersion, err := ui.GetInput(
-			"Enter custom version (e.g., v1.2.3)",
+			"Enter custom version (e.g., v1.2.3 or v1.3.0-rc.1)",
 			"",
 			false,
-			func(s string) error {
-				if !strings.HasPrefix(s, "v") {
-					return fmt.Errorf("version must start with 'v'")
-				}
-				return nil
-			},
+			validateVersion,
 		)
 		if err != nil {
 			return err
@@ -152,7 +168,7 @@ func (m *Module) handleReleaseMenu() error {
 		targetVersion = customVersion
 	}
 
-	return m.createRelease(targetVersion)
+	return m.createRelease(cfg, targetVersion)
 }
 
 // handleTagMenu handles tag management
@@ -180,39 +196,45 @@ func (m *Module) handleTagMenu() error {
 }
 
 // handleDevelopmentMenu handles development workflow
-func (m *Module) handleDevelopmentMenu() error {
+func (m *Module) handleDevelopmentMenu(cfg *config.Config) error {
 	fmt.Println()
-	options := []string{
-		"Build Locally",
-		"Install Locally",
-		"Run Tests",
-		"Run Linter",
-		"Clean Build Artifacts",
+	repo := m.releaseRepo(cfg)
+
+	options := []string{"Run Full Pipeline"}
+	for _, step := range repo.Steps {
+		options = append(options, fmt.Sprintf("Run %s (%s)", step.Name, step.Run))
+	}
+	steps := len(repo.Steps)
+	options = append(options,
+		"Generate Changelog from Commits",
 		"Update Changelog",
 		"Open Changelog",
+		"Check Version Files",
+		"Build Release Archives",
 		"Back",
-	}
+	)
 
 	choice, err := ui.SelectFromList("Development Workflow:", options)
-	if err != nil || choice == 7 {
+	if err != nil || choice == steps+6 {
 		return nil
 	}
 
-	switch choice {
-	case 0:
-		return m.buildLocal()
-	case 1:
-		return m.installLocal()
-	case 2:
-		return m.runTests()
-	case 3:
-		return m.runLinter()
-	case 4:
-		return m.cleanArtifacts()
-	case 5:
+	switch {
+	case choice == 0:
+		return m.runPipeline(repo)
+	case choice <= steps:
+		fmt.Println()
+		return m.runStep(repo, repo.Steps[choice-1], "", true)
+	case choice == steps+1:
+		return m.generateChangelog(cfg)
+	case choice == steps+2:
 		return m.updateChangelog()
-	case 6:
+	case choice == steps+3:
 		return m.openChangelog()
+	case choice == steps+4:
+		return m.checkVersionFiles(repo)
+	case choice == steps+5:
+		return m.runBuild(repo)
 	}
 
 	return nil
@@ -249,17 +271,18 @@ func (m *Module) handleGitMenu() error {
 }
 
 // handleGitHubMenu handles GitHub integration
-func (m *Module) handleGitHubMenu() error {
+func (m *Module) handleGitHubMenu(cfg *config.Config) error {
 	fmt.Println()
 	options := []string{
 		"Open Issues",
 		"Open Pull Requests",
 		"Generate Release Notes",
+		"Manage GitHub Releases",
 		"Back",
 	}
 
 	choice, err := ui.SelectFromList("GitHub Integration:", options)
-	if err != nil || choice == 3 {
+	if err != nil || choice == 4 {
 		return nil
 	}
 
@@ -270,100 +293,101 @@ func (m *Module) handleGitHubMenu() error {
 		return m.openGitHubPulls()
 	case 2:
 		return m.generateReleaseNotes()
+	case 3:
+		return m.manageGitHubReleases(cfg)
 	}
 
 	return nil
 }
 
-// Version represents a semantic version
-type Version struct {
-	Major int
-	Minor int
-	Patch int
-}
-
 // NextVersions holds calculated next versions
 type NextVersions struct {
 	Patch string
 	Minor string
 	Major string
+	Alpha string
+	Beta  string
+	RC    string
+	Final string // The normal version of a pre-release, empty for normal versions
 }
 
-// getCurrentVersion gets the current version from git tags
+// getCurrentVersion returns the highest semantic version tag across all tags,
+// including pre-releases, so that tags on other branches are not missed
 func (m *Module) getCurrentVersion() (string, error) {
-	cmd := exec.Command("git", "describe", "--tags", "--abbrev=0")
-	output, err := cmd.Output()
+	output, err := exec.Command("git", "tag", "--list").Output()
 	if err != nil {
+		return "v0.0.0", nil // Default outside a repository
+	}
+
+	latest, ok := semver.Latest(strings.Fields(string(output)), true)
+	if !ok {
 		return "v0.0.0", nil // Default if no tags exist
 	}
-	return strings.TrimSpace(string(output)), nil
+	return latest.String(), nil
 }
 
-// calculateNextVersions calculates next patch, minor, and major versions
+// calculateNextVersions calculates the next normal and pre-release versions. Pre-releases
+// continue the current pre-release (rc.1 to rc.2) or start one for the next minor version.
 func (m *Module) calculateNextVersions(currentVersion string) NextVersions {
 	version := m.parseVersion(currentVersion)
 
-	return NextVersions{
-		Patch: fmt.Sprintf("v%d.%d.%d", version.Major, version.Minor, version.Patch+1),
-		Minor: fmt.Sprintf("v%d.%d.0", version.Major, version.Minor+1),
-		Major: fmt.Sprintf("v%d.0.0", version.Major+1),
+	next := NextVersions{
+		Patch: version.BumpPatch().String(),
+		Minor: version.BumpMinor().String(),
+		Major: version.BumpMajor().String(),
+	}
+	if version.IsPrerelease() {
+		next.Final = version.Release().String()
 	}
-}
 
-// parseVersion parses a version string into components
-func (m *Module) parseVersion(versionStr string) Version {
-	// Remove 'v' prefix and any pre-release suffixes
-	versionStr = strings.TrimPrefix(versionStr, "v")
-	if idx := strings.Index(versionStr, "-"); idx != -1 {
-		versionStr = versionStr[:idx]
+	prerelease := func(label string) string {
+		if v, err := version.NextPrerelease(label); err == nil {
+			return v.String()
+		}
+		return version.Release().BumpMinor().WithPrerelease(label, "1").String()
 	}
+	next.Alpha = prerelease("alpha")
+	next.Beta = prerelease("beta")
+	next.RC = prerelease("rc")
 
-	parts := strings.Split(versionStr, ".")
-	version := Version{Major: 0, Minor: 0, Patch: 0}
+	return next
+}
 
-	if len(parts) >= 1 {
-		if major, err := strconv.Atoi(parts[0]); err == nil {
-			version.Major = major
-		}
+// parseVersion parses a version string, falling back to v0.0.0 for invalid versions
+func (m *Module) parseVersion(versionStr string) semver.Version {
+	version, err := semver.Parse(versionStr)
+	if err != nil {
+		return semver.Version{Prefix: "v"}
 	}
-	if len(parts) >= 2 {
-		if minor, err := strconv.Atoi(parts[1]); err == nil {
-			version.Minor = minor
-		}
+	return version
+}
+
+// validateVersion checks a version entered by the user
+func validateVersion(s string) error {
+	if !strings.HasPrefix(s, "v") {
+		return fmt.Errorf("version must start with 'v'")
 	}
-	if len(parts) >= 3 {
-		if patch, err := strconv.Atoi(parts[2]); err == nil {
-			version.Patch = patch
-		}
+	if _, err := semver.Parse(s); err != nil {
+		return err
 	}
-
-	return version
+	return nil
 }
 
 // createRelease creates a new release with the specified version
-func (m *Module) createRelease(version string) error {
+func (m *Module) createRelease(cfg *config.Config, version string) error {
 	fmt.Println()
 	ui.ShowInfo(fmt.Sprintf("Creating release %s...", version))
 	fmt.Println()
 
-	// Pre-release checklist
-	ui.ShowInfo("📋 Pre-release checklist:")
-	fmt.Println("  ☐ All changes committed?")
-	fmt.Println("  ☐ Tests passing?")
-	fmt.Println("  ☐ CHANGELOG.md updated?")
-	fmt.Println()
-
-	proceed, err := ui.GetInput(
-		"Continue with release? (y/N)",
-		"N",
-		false,
-		nil,
-	)
-	if err != nil {
-		return err
+	// Failed gates abort the interactive flow; overriding needs the explicit CLI flag
+	repo := m.releaseRepo(cfg)
+	if err := m.enforceReleaseGates(cfg, repo, version, nil); err != nil {
+		ui.ShowError(err.Error())
+		ui.ShowInfo(fmt.Sprintf("To release anyway, override from the command line: devtools release-manager release --bump %s --override <gates>", version))
+		return nil
 	}
 
-	if strings.ToLower(proceed) != "y" {
+	if !ui.GetConfirmation(fmt.Sprintf("All gates passed. Release %s?", version)) {
 		ui.ShowInfo("Release cancelled")
 		return nil
 	}
@@ -384,39 +408,109 @@ func (m *Module) createRelease(version string) error {
 		return err
 	}
 
-	return ui.ShowLoadingAnimation("Creating release", func() error {
-		// Create git tag
-		if err := m.runCommand("git", "tag", "-a", version, "-m", message); err != nil {
-			return fmt.Errorf("failed to create tag: %v", err)
-		}
+	// Decide on the build and GitHub Release before pushing so nothing is asked halfway through
+	build, canBuild := buildSettings(repo)
+	buildArchives := canBuild && ui.GetConfirmation(fmt.Sprintf("Build release archives for %d platforms after tagging?", len(build.Targets)))
 
-		// Push changes and tags
-		if err := m.runCommand("git", "push", "origin", "main"); err != nil {
-			return fmt.Errorf("failed to push changes: %v", err)
-		}
-
-		if err := m.runCommand("git", "push", "origin", version); err != nil {
-			return fmt.Errorf("failed to push tag: %v", err)
+	var releaseOpts *githubReleaseOptions
+	client, githubRepo, clientErr := m.githubReleaseClient(cfg)
+	if clientErr == nil {
+		if releaseOpts, err = m.promptGitHubRelease(version, buildArchives); err != nil {
+			return err
 		}
+	}
 
-		ui.ShowSuccess(fmt.Sprintf("✅ Release %s created!", version))
-		fmt.Println()
-		ui.ShowInfo("🚀 GitHub Actions will now:")
-		fmt.Println("  • Build binaries for all platforms")
-		fmt.Println("  • Create GitHub release")
-		fmt.Println("  • Upload release assets")
-		fmt.Println()
+	err = ui.ShowLoadingAnimation("Creating release", func() error {
+		return m.publishRelease(repo, version, message)
+	})
+	if err != nil {
+		return err
+	}
 
-		if repo, err := m.getGitHubRepo(); err == nil {
-			ui.ShowInfo("📊 Monitor progress at:")
-			fmt.Printf("  https://github.com/%s/actions\n", repo)
-			fmt.Println()
-			ui.ShowInfo("📦 View release at:")
-			fmt.Printf("  https://github.com/%s/releases/tag/%s\n", repo, version)
+	if buildArchives {
+		manifest, err := m.buildRelease(repo, build, version)
+		if err != nil {
+			return fmt.Errorf("%s is tagged, but the build failed: %v", version, err)
+		}
+		showBuildManifest(build, manifest)
+		if releaseOpts != nil {
+			releaseOpts.Assets = buildAssets(build, manifest)
 		}
+	}
 
+	if releaseOpts == nil {
+		m.showActionsHint(version)
 		return nil
-	})
+	}
+
+	release, err := m.publishGitHubRelease(client, githubRepo, version, *releaseOpts)
+	if err != nil {
+		return err
+	}
+	showGitHubRelease(release)
+	return nil
+}
+
+// publishRelease commits the version files, tags the release, pushes the release branch
+// and tag, and runs the pre-tag and post-tag hooks around them
+func (m *Module) publishRelease(repo config.ReleaseRepo, version, message string) error {
+	if err := m.runSteps(repo, repo.Hooks.PreTag, version, false); err != nil {
+		return fmt.Errorf("pre-tag hook failed, nothing was tagged: %v", err)
+	}
+
+	if err := m.commitVersionBump(repo, version); err != nil {
+		return err
+	}
+
+	if err := m.createTag(version, message); err != nil {
+		return err
+	}
+
+	// Push changes and tags
+	if err := m.runCommand("git", "push", repo.Remote, repo.Branch); err != nil {
+		return fmt.Errorf("failed to push changes: %v", err)
+	}
+
+	if err := m.runCommand("git", "push", repo.Remote, version); err != nil {
+		return fmt.Errorf("failed to push tag: %v", err)
+	}
+
+	if err := m.runSteps(repo, repo.Hooks.PostTag, version, false); err != nil {
+		return fmt.Errorf("post-tag hook failed after %s was pushed: %v", version, err)
+	}
+
+	ui.ShowSuccess(fmt.Sprintf("✅ Release %s created!", version))
+	fmt.Println()
+	return nil
+}
+
+// createTag creates an annotated tag, signed when git signing is configured
+func (m *Module) createTag(tag, message string) error {
+	tagFlag := "-a"
+	if signingConfigured() {
+		tagFlag = "-s"
+	}
+	if err := m.runCommand("git", "tag", tagFlag, tag, "-m", message); err != nil {
+		return fmt.Errorf("failed to create tag %s: %v", tag, err)
+	}
+	return nil
+}
+
+// showActionsHint points to the GitHub Actions workflow expected to build and publish a tag
+func (m *Module) showActionsHint(version string) {
+	ui.ShowInfo("🚀 GitHub Actions will now:")
+	fmt.Println("  • Build binaries for all platforms")
+	fmt.Println("  • Create GitHub release")
+	fmt.Println("  • Upload release assets")
+	fmt.Println()
+
+	if repo, err := m.getGitHubRepo(); err == nil {
+		ui.ShowInfo("📊 Monitor progress at:")
+		fmt.Printf("  https://github.com/%s/actions\n", repo)
+		fmt.Println()
+		ui.ShowInfo("📦 View release at:")
+		fmt.Printf("  https://github.com/%s/releases/tag/%s\n", repo, version)
+	}
 }
 
 // Helper method to run commands
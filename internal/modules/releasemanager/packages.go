@@ -0,0 +1,402 @@
+package releasemanager
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/kkz6/devtools/internal/config"
+	"github.com/kkz6/devtools/internal/semver"
+	"github.com/kkz6/devtools/internal/ui"
+)
+
+// packageChangelogHeader starts the changelog of a package released for the first time
+const packageChangelogHeader = `# Changelog
+
+All notable changes to %s will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.0.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+
+## [Unreleased]
+`
+
+// packagePlan is the next release of a monorepo package
+type packagePlan struct {
+	Package    config.ReleasePackage
+	Current    string // Latest released version, v0.0.0 without tags
+	CurrentTag string // Tag of the current version, empty without tags
+	Analysis   *BumpAnalysis
+	Next       string // Version to release, empty when no commit requires a release
+}
+
+// Tag returns the tag of the planned version, e.g. api/v1.4.0
+func (p packagePlan) Tag() string {
+	return packageTagPrefix(p.Package) + p.Next
+}
+
+// Changed reports whether commits touching the package require a release
+func (p packagePlan) Changed() bool {
+	return p.Next != "" && len(p.Analysis.Commits) > 0
+}
+
+// packageTagPrefix returns the tag prefix of a package, "<name>/" by default
+func packageTagPrefix(pkg config.ReleasePackage) string {
+	if pkg.TagPrefix != "" {
+		return pkg.TagPrefix
+	}
+	return pkg.Name + "/"
+}
+
+// packageVersion returns the highest version tagged with a package's prefix and its tag
+func packageVersion(pkg config.ReleasePackage) (version, tag string) {
+	prefix := packageTagPrefix(pkg)
+	output, err := exec.Command("git", "tag", "--list", prefix+"*").Output()
+	if err != nil {
+		return "v0.0.0", ""
+	}
+
+	var versions []string
+	for _, name := range strings.Fields(string(output)) {
+		versions = append(versions, strings.TrimPrefix(name, prefix))
+	}
+	latest, ok := semver.Latest(versions, true)
+	if !ok {
+		return "v0.0.0", ""
+	}
+	return latest.String(), prefix + latest.String()
+}
+
+// selectPackages returns the named packages of a repository, or all without names
+func selectPackages(repo config.ReleaseRepo, names []string) ([]config.ReleasePackage, error) {
+	if len(repo.Packages) == 0 {
+		return nil, fmt.Errorf("no packages configured for %s", repo.Path)
+	}
+	for _, pkg := range repo.Packages {
+		if pkg.Name == "" || pkg.Path == "" {
+			return nil, fmt.Errorf("every package needs a name and a path")
+		}
+	}
+	if len(names) == 0 {
+		return repo.Packages, nil
+	}
+
+	packages := make([]config.ReleasePackage, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, pkg := range repo.Packages {
+			if pkg.Name == name {
+				packages = append(packages, pkg)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown package %q (available: %s)", name, packageNames(repo.Packages))
+		}
+	}
+	return packages, nil
+}
+
+// packageNames joins the names of packages
+func packageNames(packages []config.ReleasePackage) string {
+	names := make([]string, len(packages))
+	for i, pkg := range packages {
+		names[i] = pkg.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// planPackages analyzes the commits touching each package since its last tag and picks
+// the next version: auto follows the commits, patch, minor and major bump the current
+// version, and an explicit version is used as is
+func (m *Module) planPackages(packages []config.ReleasePackage, bump string) ([]packagePlan, error) {
+	plans := make([]packagePlan, 0, len(packages))
+	for _, pkg := range packages {
+		current, tag := packageVersion(pkg)
+		analysis, err := m.analyzeCommits(tag, pkg.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to analyze %s: %v", pkg.Name, err)
+		}
+
+		plan := packagePlan{Package: pkg, Current: current, CurrentTag: tag, Analysis: analysis}
+		next := m.calculateNextVersions(current)
+		switch bump {
+		case "", "auto":
+			if analysis.Bump != BumpNone {
+				plan.Next = next.forBump(analysis.Bump)
+			}
+		case "patch":
+			plan.Next = next.Patch
+		case "minor":
+			plan.Next = next.Minor
+		case "major":
+			plan.Next = next.Major
+		default:
+			if err := validateVersion(bump); err != nil {
+				return nil, fmt.Errorf("invalid --bump %q: use auto, patch, minor, major or a version such as v1.2.3", bump)
+			}
+			plan.Next = bump
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// changedPackages returns the plans of packages that need a release
+func changedPackages(plans []packagePlan) []packagePlan {
+	var changed []packagePlan
+	for _, plan := range plans {
+		if plan.Changed() {
+			changed = append(changed, plan)
+		}
+	}
+	return changed
+}
+
+// packageTags joins the planned tags of packages
+func packageTags(plans []packagePlan) string {
+	tags := make([]string, len(plans))
+	for i, plan := range plans {
+		tags[i] = plan.Tag()
+	}
+	return strings.Join(tags, ", ")
+}
+
+// packageTable renders the current and next version of every package
+func packageTable(plans []packagePlan) *ui.Table {
+	table := ui.NewTable("Packages")
+	table.AddHeader("Package", "Path", "Current", "Commits", "Bump", "Next")
+	for _, plan := range plans {
+		next := "-"
+		if plan.Changed() {
+			next = plan.Tag()
+		}
+		table.AddRow(plan.Package.Name, plan.Package.Path, valueOr(plan.CurrentTag, "untagged"),
+			fmt.Sprintf("%d", len(plan.Analysis.Commits)), plan.Analysis.Bump.String(), next)
+	}
+	return table
+}
+
+// checkPackageGates checks the release gates of packages. The working tree, branch,
+// remote, steps and CI are checked once; tags and signatures per package.
+func (m *Module) checkPackageGates(cfg *config.Config, repo config.ReleaseRepo, plans []packagePlan) []gateResult {
+	results := []gateResult{
+		m.checkCleanTree(),
+		m.checkBranch(repo),
+		m.checkRemote(repo),
+	}
+	for _, plan := range plans {
+		results = append(results, m.checkTagAbsent(repo, plan.Tag()))
+	}
+	results = append(results,
+		m.checkStep(gateTest, "Tests pass", repo, ""),
+		m.checkStep(gateLint, "Lint passes", repo, ""),
+		m.checkCI(cfg, repo.RequireCI),
+	)
+	for _, plan := range plans {
+		signed := m.checkSignedCommits(repo, plan.CurrentTag, plan.Package.Path)
+		signed.Name = fmt.Sprintf("%s: %s", plan.Package.Name, signed.Name)
+		results = append(results, signed)
+	}
+	return results
+}
+
+// enforcePackageGates fails unless every package release gate passes or is overridden
+func (m *Module) enforcePackageGates(cfg *config.Config, repo config.ReleaseRepo, plans []packagePlan, overrides map[string]bool) error {
+	return enforceGates(fmt.Sprintf("Release Gates for %s", packageTags(plans)), func() []gateResult {
+		return m.checkPackageGates(cfg, repo, plans)
+	}, overrides)
+}
+
+// packageHookRepo adds RELEASE_PACKAGE and RELEASE_TAG to the env of a package's hooks
+func packageHookRepo(repo config.ReleaseRepo, plan packagePlan) config.ReleaseRepo {
+	env := make(map[string]string, len(repo.Env)+2)
+	for key, value := range repo.Env {
+		env[key] = value
+	}
+	env["RELEASE_PACKAGE"] = plan.Package.Name
+	env["RELEASE_TAG"] = plan.Tag()
+	repo.Env = env
+	return repo
+}
+
+// writePackageRelease adds the release entry to the package's CHANGELOG.md and writes the
+// version into its version files. It returns the changed files relative to the repository.
+func (m *Module) writePackageRelease(repo config.ReleaseRepo, plan packagePlan, githubRepo string) ([]string, error) {
+	dir := filepath.Join(repo.Path, plan.Package.Path)
+	var files []string
+
+	entry, err := m.buildChangelogEntry(plan.Analysis.Commits, nil, githubRepo)
+	if err != nil {
+		return nil, err
+	}
+	if !entry.Empty() {
+		path := filepath.Join(dir, "CHANGELOG.md")
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			if err := os.WriteFile(path, []byte(fmt.Sprintf(packageChangelogHeader, plan.Package.Name)), 0644); err != nil {
+				return nil, fmt.Errorf("failed to create %s: %v", path, err)
+			}
+		}
+		if err := m.saveChangelogEntryTo(path, plan.Next, plan.Tag(), plan.CurrentTag, githubRepo, entry); err != nil {
+			return nil, err
+		}
+		files = append(files, filepath.Join(plan.Package.Path, "CHANGELOG.md"))
+	}
+
+	pkgRepo := repo
+	pkgRepo.Path = dir
+	pkgRepo.VersionFiles = plan.Package.VersionFiles
+	changed, err := bumpVersionFiles(pkgRepo, plan.Next)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update version files of %s: %v", plan.Package.Name, err)
+	}
+	for _, file := range changed {
+		files = append(files, filepath.Join(plan.Package.Path, file))
+	}
+	return files, nil
+}
+
+// publishPackages releases packages together: it writes their changelogs and version files
+// into one release commit, tags every package, pushes the branch and tags, and runs the
+// pre-tag and post-tag hooks once per package
+func (m *Module) publishPackages(repo config.ReleaseRepo, plans []packagePlan) error {
+	for _, plan := range plans {
+		if err := m.runSteps(packageHookRepo(repo, plan), repo.Hooks.PreTag, plan.Next, false); err != nil {
+			return fmt.Errorf("pre-tag hook of %s failed, nothing was tagged: %v", plan.Package.Name, err)
+		}
+	}
+
+	githubRepo := repoOrEmpty(m.getGitHubRepo())
+	var files []string
+	for _, plan := range plans {
+		changed, err := m.writePackageRelease(repo, plan, githubRepo)
+		if err != nil {
+			return err
+		}
+		files = append(files, changed...)
+	}
+	if len(files) > 0 {
+		if err := m.commitReleaseFiles(repo, fmt.Sprintf("chore(release): %s", packageTags(plans)), files); err != nil {
+			return err
+		}
+	}
+
+	tags := make([]string, 0, len(plans))
+	for _, plan := range plans {
+		if err := m.createTag(plan.Tag(), fmt.Sprintf("Release %s %s", plan.Package.Name, plan.Next)); err != nil {
+			return err
+		}
+		tags = append(tags, plan.Tag())
+	}
+
+	if err := m.runCommand("git", "push", repo.Remote, repo.Branch); err != nil {
+		return fmt.Errorf("failed to push changes: %v", err)
+	}
+	if err := m.runCommand("git", append([]string{"push", repo.Remote}, tags...)...); err != nil {
+		return fmt.Errorf("failed to push tags: %v", err)
+	}
+
+	for _, plan := range plans {
+		if err := m.runSteps(packageHookRepo(repo, plan), repo.Hooks.PostTag, plan.Next, false); err != nil {
+			return fmt.Errorf("post-tag hook of %s failed after %s was pushed: %v", plan.Package.Name, plan.Tag(), err)
+		}
+	}
+
+	ui.ShowSuccess(fmt.Sprintf("✅ Released %s!", packageTags(plans)))
+	fmt.Println()
+	return nil
+}
+
+// handlePackageReleaseMenu releases all changed packages or one package of a monorepo
+func (m *Module) handlePackageReleaseMenu(cfg *config.Config, repo config.ReleaseRepo) error {
+	fmt.Println()
+	packages, err := selectPackages(repo, nil)
+	if err != nil {
+		return err
+	}
+	plans, err := m.planPackages(packages, "auto")
+	if err != nil {
+		return err
+	}
+	fmt.Println(packageTable(plans).Render())
+	fmt.Println()
+
+	changed := changedPackages(plans)
+	var options []string
+	if len(changed) > 0 {
+		options = append(options, fmt.Sprintf("Release All Changed Packages (%s)", packageTags(changed)))
+	}
+	for _, plan := range plans {
+		options = append(options, fmt.Sprintf("Release %s (%s)", plan.Package.Name, valueOr(plan.CurrentTag, "untagged")))
+	}
+	options = append(options, "Back")
+
+	choice, err := ui.SelectFromList("Select packages to release:", options)
+	if err != nil || choice == len(options)-1 {
+		return nil
+	}
+	if len(changed) > 0 {
+		if choice == 0 {
+			return m.createPackageRelease(cfg, repo, changed)
+		}
+		choice--
+	}
+
+	plan := plans[choice]
+	m.showBumpAnalysis(plan.Analysis)
+	next := m.calculateNextVersions(plan.Current)
+	versions := []string{next.Patch, next.Minor, next.Major, ""}
+	bumps := []BumpLevel{BumpPatch, BumpMinor, BumpMajor, BumpNone}
+	labels := []string{
+		fmt.Sprintf("Patch Release (%s%s)", packageTagPrefix(plan.Package), next.Patch),
+		fmt.Sprintf("Minor Release (%s%s)", packageTagPrefix(plan.Package), next.Minor),
+		fmt.Sprintf("Major Release (%s%s)", packageTagPrefix(plan.Package), next.Major),
+		"Custom Version",
+		"Back",
+	}
+	recommended := 0
+	for i, bump := range bumps {
+		if bump != BumpNone && bump == plan.Analysis.Bump {
+			labels[i] += " (recommended)"
+			recommended = i
+		}
+	}
+
+	choice, err = ui.SelectFromListWithDefault("Select release type:", labels, recommended)
+	if err != nil || choice == len(labels)-1 {
+		return nil
+	}
+	plan.Next = versions[choice]
+	if plan.Next == "" {
+		if plan.Next, err = ui.GetInput("Enter custom version (e.g., v1.2.3)", "", false, validateVersion); err != nil {
+			return err
+		}
+	}
+	return m.createPackageRelease(cfg, repo, []packagePlan{plan})
+}
+
+// createPackageRelease checks the gates of packages and releases them after confirmation
+func (m *Module) createPackageRelease(cfg *config.Config, repo config.ReleaseRepo, plans []packagePlan) error {
+	fmt.Println()
+	ui.ShowInfo(fmt.Sprintf("Releasing %s...", packageTags(plans)))
+	fmt.Println()
+
+	// Failed gates abort the interactive flow; overriding needs the explicit CLI flag
+	if err := m.enforcePackageGates(cfg, repo, plans, nil); err != nil {
+		ui.ShowError(err.Error())
+		ui.ShowInfo("To release anyway, override from the command line: devtools release-manager packages release --override <gates> [package ...]")
+		return nil
+	}
+
+	if !ui.GetConfirmation(fmt.Sprintf("All gates passed. Release %s?", packageTags(plans))) {
+		ui.ShowInfo("Release cancelled")
+		return nil
+	}
+
+	return ui.ShowLoadingAnimation("Creating release", func() error {
+		return m.publishPackages(repo, plans)
+	})
+}
@@ -0,0 +1,165 @@
+package releasemanager
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kkz6/devtools/internal/config"
+	"github.com/kkz6/devtools/internal/ui"
+)
+
+// Step failure policies
+const (
+	failureAbort    = "abort"
+	failureContinue = "continue"
+	failurePrompt   = "prompt"
+)
+
+// stepCommand prepares the shell command of a step in its working directory. The
+// environment is the process environment, the repository env, the step env and
+// RELEASE_VERSION when a version is being released.
+func stepCommand(repo config.ReleaseRepo, step config.ReleaseStep, version string) *exec.Cmd {
+	dir := repo.Path
+	if step.Dir != "" {
+		dir = expandPath(step.Dir)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(repo.Path, dir)
+		}
+	}
+
+	cmd := exec.Command("sh", "-c", step.Run)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "RELEASE_STEP="+step.Name)
+	if version != "" {
+		cmd.Env = append(cmd.Env, "RELEASE_VERSION="+version)
+	}
+	cmd.Env = append(cmd.Env, envList(repo.Env)...)
+	cmd.Env = append(cmd.Env, envList(step.Env)...)
+	return cmd
+}
+
+// envList converts an env map into sorted KEY=value pairs
+func envList(env map[string]string) []string {
+	list := make([]string, 0, len(env))
+	for key, value := range env {
+		list = append(list, key+"="+value)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// validateStep checks the command and failure policy of a step
+func validateStep(step config.ReleaseStep) error {
+	if strings.TrimSpace(step.Run) == "" {
+		return fmt.Errorf("step %q has no run command", step.Name)
+	}
+	switch step.OnFailure {
+	case "", failureAbort, failureContinue, failurePrompt:
+		return nil
+	}
+	return fmt.Errorf("step %q has an invalid on_failure %q: use abort, continue or prompt", step.Name, step.OnFailure)
+}
+
+// runStep runs a step with its output shown and applies its failure policy. The prompt
+// policy only asks when interactive is set and aborts otherwise.
+func (m *Module) runStep(repo config.ReleaseRepo, step config.ReleaseStep, version string, interactive bool) error {
+	if err := validateStep(step); err != nil {
+		return err
+	}
+
+	ui.ShowInfo(fmt.Sprintf("▶ %s: %s", step.Name, step.Run))
+	cmd := stepCommand(repo, step, version)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	elapsed := time.Since(start).Round(100 * time.Millisecond)
+	if err == nil {
+		ui.ShowSuccess(fmt.Sprintf("✅ %s finished in %s", step.Name, elapsed))
+		return nil
+	}
+
+	switch step.OnFailure {
+	case failureContinue:
+		ui.ShowWarning(fmt.Sprintf("⚠️  %s failed (%v), continuing", step.Name, err))
+		return nil
+	case failurePrompt:
+		if interactive && ui.GetConfirmation(fmt.Sprintf("%s failed (%v). Continue anyway?", step.Name, err)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("step %q failed: %v", step.Name, err)
+}
+
+// runSteps runs steps in order, stopping at the first failure that is not tolerated
+func (m *Module) runSteps(repo config.ReleaseRepo, steps []config.ReleaseStep, version string, interactive bool) error {
+	for _, step := range steps {
+		if err := m.runStep(repo, step, version, interactive); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// findStep returns the pipeline step with a name
+func findStep(repo config.ReleaseRepo, name string) (config.ReleaseStep, bool) {
+	for _, step := range repo.Steps {
+		if step.Name == name {
+			return step, true
+		}
+	}
+	return config.ReleaseStep{}, false
+}
+
+// selectSteps returns the named pipeline steps in the given order, or all steps
+func selectSteps(repo config.ReleaseRepo, names []string) ([]config.ReleaseStep, error) {
+	if len(names) == 0 {
+		return repo.Steps, nil
+	}
+
+	steps := make([]config.ReleaseStep, 0, len(names))
+	for _, name := range names {
+		step, ok := findStep(repo, name)
+		if !ok {
+			return nil, fmt.Errorf("unknown step %q, the pipeline has: %s", name, strings.Join(stepNames(repo.Steps), ", "))
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// stepNames returns the names of steps
+func stepNames(steps []config.ReleaseStep) []string {
+	names := make([]string, 0, len(steps))
+	for _, step := range steps {
+		names = append(names, step.Name)
+	}
+	return names
+}
+
+// runPipeline runs the whole pipeline of the current repository
+func (m *Module) runPipeline(repo config.ReleaseRepo) error {
+	if len(repo.Steps) == 0 {
+		ui.ShowWarning("No pipeline steps configured for this repository")
+		ui.ShowInfo("Add steps under release_manager.repos in the configuration")
+		return nil
+	}
+
+	fmt.Println()
+	ui.ShowInfo(fmt.Sprintf("🏗  Running pipeline: %s", strings.Join(stepNames(repo.Steps), " → ")))
+	fmt.Println()
+
+	if err := m.runSteps(repo, repo.Steps, "", true); err != nil {
+		return err
+	}
+	ui.ShowSuccess("✅ Pipeline complete")
+	return nil
+}
@@ -0,0 +1,455 @@
+package releasemanager
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/kkz6/devtools/internal/config"
+	"github.com/kkz6/devtools/internal/semver"
+	"github.com/kkz6/devtools/internal/ui"
+)
+
+// githubReleaseOptions controls how a GitHub Release is created or updated
+type githubReleaseOptions struct {
+	Draft      bool
+	Prerelease bool
+	Latest     string   // "true", "false", or "" to decide from the tags
+	Notes      string   // Empty to generate from CHANGELOG.md or the commits
+	Assets     []string // Files or glob patterns to upload
+}
+
+// githubReleaseClient returns a GitHub client and the origin repository, or an error
+// explaining why releases cannot be managed through the API
+func (m *Module) githubReleaseClient(cfg *config.Config) (*githubClient, string, error) {
+	if cfg.GitHub.Token == "" {
+		return nil, "", fmt.Errorf("no GitHub token configured")
+	}
+	repo, err := m.getGitHubRepo()
+	if err != nil {
+		return nil, "", err
+	}
+	return newGitHubClient(cfg.GitHub.Token), repo, nil
+}
+
+// previousTag returns the highest tag below a version. Normal versions skip pre-releases, so
+// the notes of v1.2.0 cover everything since v1.1.0 rather than since v1.2.0-rc.2.
+func (m *Module) previousTag(version string) string {
+	current, err := semver.Parse(version)
+	if err != nil {
+		return ""
+	}
+	output, err := exec.Command("git", "tag", "--list").Output()
+	if err != nil {
+		return ""
+	}
+
+	var previous semver.Version
+	found := false
+	for _, tag := range strings.Fields(string(output)) {
+		v, err := semver.Parse(tag)
+		if err != nil || !v.LessThan(current) || (!current.IsPrerelease() && v.IsPrerelease()) {
+			continue
+		}
+		if !found || previous.LessThan(v) {
+			previous = v
+			found = true
+		}
+	}
+	if !found {
+		return ""
+	}
+	return previous.String()
+}
+
+// changelogVersionSection returns the body of a version's section in a changelog
+func changelogVersionSection(content, version string) (string, bool) {
+	headings := []string{"## [" + version + "]", "## [" + strings.TrimPrefix(version, "v") + "]"}
+
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		matched := false
+		for _, heading := range headings {
+			if strings.HasPrefix(line, heading) {
+				matched = true
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		end := i + 1
+		for end < len(lines) && !strings.HasPrefix(lines[end], "## ") && !changelogLinkRef.MatchString(lines[end]) {
+			end++
+		}
+		return strings.TrimSpace(strings.Join(lines[i+1:end], "\n")), true
+	}
+	return "", false
+}
+
+// releaseNotesFor returns the notes of a release: its CHANGELOG.md section when there is one,
+// otherwise notes generated from the commits since the previous tag
+func (m *Module) releaseNotesFor(version string) (string, error) {
+	if content, err := os.ReadFile("CHANGELOG.md"); err == nil {
+		if section, ok := changelogVersionSection(string(content), version); ok && section != "" {
+			return section, nil
+		}
+	}
+
+	// Once the tag exists the notes end at it rather than at HEAD
+	until := "HEAD"
+	if m.runCommandSilent("git", "rev-parse", "--verify", "--quiet", "refs/tags/"+version) == nil {
+		until = version
+	}
+	previous := m.previousTag(version)
+	notes, err := m.releaseNotes(previous, until)
+	if err != nil {
+		return "", err
+	}
+	if previous != "" {
+		if repo, err := m.getGitHubRepo(); err == nil {
+			notes += fmt.Sprintf("\n**Full Changelog**: https://github.com/%s/compare/%s...%s\n", repo, previous, version)
+		}
+	}
+	return notes, nil
+}
+
+// expandAssets expands file and glob patterns into the files to upload
+func expandAssets(patterns []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(expandPath(pattern))
+		if err != nil {
+			return nil, fmt.Errorf("invalid asset pattern %q: %v", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %q", pattern)
+		}
+		for _, match := range matches {
+			if info, err := os.Stat(match); err != nil || info.IsDir() || seen[match] {
+				continue
+			}
+			seen[match] = true
+			files = append(files, match)
+		}
+	}
+	return files, nil
+}
+
+// expandPath expands a leading ~ to the home directory
+func expandPath(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
+
+// shouldMarkLatest decides whether a release becomes the latest: only published normal
+// versions that are the highest normal version tag
+func (m *Module) shouldMarkLatest(version string, opts githubReleaseOptions) string {
+	if opts.Latest != "" {
+		return opts.Latest
+	}
+	if opts.Draft || opts.Prerelease {
+		return "false"
+	}
+
+	output, err := exec.Command("git", "tag", "--list").Output()
+	if err != nil {
+		return "legacy"
+	}
+	latest, ok := semver.Latest(append(strings.Fields(string(output)), version), false)
+	if ok && semver.Compare(latest.String(), version) == 0 {
+		return "true"
+	}
+	return "false"
+}
+
+// publishGitHubRelease creates the GitHub Release of a tag, or updates it when it exists,
+// and uploads the assets
+func (m *Module) publishGitHubRelease(client *githubClient, repo, version string, opts githubReleaseOptions) (*githubRelease, error) {
+	assets, err := expandAssets(opts.Assets)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := opts.Notes
+	if notes == "" {
+		if notes, err = m.releaseNotesFor(version); err != nil {
+			return nil, err
+		}
+	}
+
+	request := githubReleaseRequest{
+		TagName:    version,
+		Name:       version,
+		Body:       notes,
+		Draft:      opts.Draft,
+		Prerelease: opts.Prerelease,
+		MakeLatest: m.shouldMarkLatest(version, opts),
+	}
+
+	existing, err := client.getReleaseByTag(repo, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up the release of %s: %v", version, err)
+	}
+
+	var release *githubRelease
+	if existing != nil {
+		ui.ShowInfo(fmt.Sprintf("Updating the existing GitHub Release of %s", version))
+		release, err = client.updateRelease(repo, existing.ID, request)
+	} else {
+		release, err = client.createRelease(repo, request)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save the GitHub Release: %v", err)
+	}
+
+	if len(assets) > 0 {
+		progress := ui.NewProgressBar("Uploading assets", len(assets))
+		for _, asset := range assets {
+			progress.UpdateTitle(fmt.Sprintf("Uploading %s", filepath.Base(asset)))
+			uploaded, err := client.uploadAsset(repo, release, asset)
+			if err != nil {
+				progress.Finish()
+				return release, fmt.Errorf("failed to upload %s: %v", asset, err)
+			}
+			release.Assets = append(release.Assets, *uploaded)
+			progress.Increment()
+		}
+		progress.Finish()
+	}
+
+	return release, nil
+}
+
+// promptGitHubRelease asks how to create the GitHub Release of a new version.
+// It returns nil when the release is left to a GitHub Actions workflow.
+func (m *Module) promptGitHubRelease(version string, built bool) (*githubReleaseOptions, error) {
+	choice, err := ui.SelectFromList("GitHub Release:", []string{
+		"Publish GitHub Release",
+		"Create Draft Release",
+		"Skip (a GitHub Actions workflow creates it)",
+	})
+	if err != nil || choice == 2 {
+		return nil, nil
+	}
+
+	opts := &githubReleaseOptions{Draft: choice == 1}
+	if v, err := semver.Parse(version); err == nil && v.IsPrerelease() {
+		opts.Prerelease = ui.GetConfirmation(fmt.Sprintf("Mark %s as a pre-release?", version))
+	}
+
+	// The archives of a build are attached once they exist
+	if built {
+		ui.ShowInfo("The release archives, checksums.txt and manifest.json will be uploaded")
+		return opts, nil
+	}
+
+	defaultAssets := ""
+	if _, err := os.Stat("dist"); err == nil {
+		defaultAssets = "dist/*"
+	}
+	assets, err := ui.GetInput("Assets to upload (comma-separated files or globs, empty for none)", defaultAssets, false, func(s string) error {
+		_, err := expandAssets(splitPatterns(s))
+		return err
+	})
+	if err != nil && err.Error() != "cancelled" {
+		return nil, err
+	}
+	opts.Assets = splitPatterns(assets)
+
+	return opts, nil
+}
+
+// splitPatterns splits a comma-separated list of patterns
+func splitPatterns(value string) []string {
+	var patterns []string
+	for _, pattern := range strings.Split(value, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// showGitHubRelease prints the outcome of publishing a GitHub Release
+func showGitHubRelease(release *githubRelease) {
+	status := "published"
+	if release.Draft {
+		status = "saved as a draft"
+	}
+	ui.ShowSuccess(fmt.Sprintf("✅ GitHub Release %s %s", release.TagName, status))
+	if len(release.Assets) > 0 {
+		fmt.Printf("  %d assets attached\n", len(release.Assets))
+	}
+	fmt.Printf("  %s\n", release.HTMLURL)
+}
+
+// releaseStatus describes the state of a release
+func releaseStatus(release githubRelease, latestID int64) string {
+	switch {
+	case release.Draft:
+		return "Draft"
+	case release.Prerelease:
+		return "Pre-release"
+	case release.ID == latestID:
+		return "Latest"
+	default:
+		return "Published"
+	}
+}
+
+// manageGitHubReleases lists the repository's releases and edits, deletes or marks one as latest
+func (m *Module) manageGitHubReleases(cfg *config.Config) error {
+	fmt.Println()
+	client, repo, err := m.githubReleaseClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	for {
+		releases, err := client.listReleases(repo)
+		if err != nil {
+			return fmt.Errorf("failed to list releases: %v", err)
+		}
+		if len(releases) == 0 {
+			ui.ShowInfo(fmt.Sprintf("%s has no releases", repo))
+			return nil
+		}
+
+		var latestID int64
+		if latest, err := client.getLatestRelease(repo); err == nil && latest != nil {
+			latestID = latest.ID
+		}
+
+		table := ui.NewTable(fmt.Sprintf("GitHub Releases of %s", repo))
+		table.AddHeader("Tag", "Name", "Status", "Assets", "Published")
+		options := make([]string, 0, len(releases)+1)
+		for _, release := range releases {
+			published := "-"
+			if release.PublishedAt != nil {
+				published = release.PublishedAt.Local().Format("2006-01-02")
+			}
+			status := releaseStatus(release, latestID)
+			table.AddRow(release.TagName, release.Name, status, fmt.Sprintf("%d", len(release.Assets)), published)
+			options = append(options, fmt.Sprintf("%s (%s)", release.TagName, status))
+		}
+		options = append(options, "Back")
+		fmt.Println(table.Render())
+
+		choice, err := ui.SelectFromList("Select release:", options)
+		if err != nil || choice == len(releases) {
+			return nil
+		}
+
+		if err := m.editGitHubRelease(client, repo, releases[choice], latestID); err != nil {
+			ui.ShowError(err.Error())
+		}
+	}
+}
+
+// editGitHubRelease applies one action to a release
+func (m *Module) editGitHubRelease(client *githubClient, repo string, release githubRelease, latestID int64) error {
+	draftAction := "Publish Draft"
+	if !release.Draft {
+		draftAction = "Convert to Draft"
+	}
+	prereleaseAction := "Mark as Pre-release"
+	if release.Prerelease {
+		prereleaseAction = "Mark as Full Release"
+	}
+
+	choice, err := ui.SelectFromList(fmt.Sprintf("%s:", release.TagName), []string{
+		"Edit Notes",
+		"Regenerate Notes",
+		draftAction,
+		prereleaseAction,
+		"Mark as Latest",
+		"Upload Assets",
+		"Delete Release",
+		"Back",
+	})
+	if err != nil || choice == 7 {
+		return nil
+	}
+
+	request := githubReleaseRequest{
+		Name:       release.Name,
+		Body:       release.Body,
+		Draft:      release.Draft,
+		Prerelease: release.Prerelease,
+	}
+
+	switch choice {
+	case 0: // Edit notes
+		body, err := m.editText("RELEASE-*.md", release.Body)
+		if err != nil {
+			return err
+		}
+		request.Body = body
+	case 1: // Regenerate notes
+		body, err := m.releaseNotesFor(release.TagName)
+		if err != nil {
+			return err
+		}
+		request.Body = body
+	case 2: // Draft
+		request.Draft = !release.Draft
+		if !request.Draft {
+			// GitHub makes a newly published release the latest unless told otherwise
+			request.MakeLatest = m.shouldMarkLatest(release.TagName, githubReleaseOptions{Prerelease: release.Prerelease})
+		}
+	case 3: // Pre-release
+		request.Prerelease = !release.Prerelease
+	case 4: // Latest
+		if release.Draft || release.Prerelease {
+			return fmt.Errorf("drafts and pre-releases cannot be the latest release")
+		}
+		if release.ID == latestID {
+			ui.ShowInfo(fmt.Sprintf("%s is already the latest release", release.TagName))
+			return nil
+		}
+		request.MakeLatest = "true"
+	case 5: // Upload assets
+		patterns, err := ui.GetInput("Assets to upload (comma-separated files or globs)", "dist/*", false, func(s string) error {
+			_, err := expandAssets(splitPatterns(s))
+			return err
+		})
+		if err != nil {
+			return nil
+		}
+		files, err := expandAssets(splitPatterns(patterns))
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			if _, err := client.uploadAsset(repo, &release, file); err != nil {
+				return fmt.Errorf("failed to upload %s: %v", file, err)
+			}
+			ui.ShowSuccess(fmt.Sprintf("Uploaded %s", filepath.Base(file)))
+		}
+		return nil
+	case 6: // Delete
+		if !ui.GetConfirmation(fmt.Sprintf("Delete the GitHub Release %s? The tag is kept.", release.TagName)) {
+			return nil
+		}
+		if err := client.deleteRelease(repo, release.ID); err != nil {
+			return fmt.Errorf("failed to delete release: %v", err)
+		}
+		ui.ShowSuccess(fmt.Sprintf("Deleted the GitHub Release %s", release.TagName))
+		return nil
+	}
+
+	updated, err := client.updateRelease(repo, release.ID, request)
+	if err != nil {
+		return fmt.Errorf("failed to update release: %v", err)
+	}
+	ui.ShowSuccess(fmt.Sprintf("Updated %s", updated.TagName))
+	return nil
+}
@@ -0,0 +1,104 @@
+package releasemanager
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/kkz6/devtools/internal/config"
+)
+
+// releaseRepo returns the release configuration of the current checkout with defaults
+// applied. Checkouts without configuration get the defaults: main, origin, and the
+// pipeline steps detected from the project type.
+func (m *Module) releaseRepo(cfg *config.Config) config.ReleaseRepo {
+	repo := config.ReleaseRepo{}
+
+	root := m.repoRoot()
+	for _, candidate := range cfg.Release.Repos {
+		if candidate != nil && samePath(expandPath(candidate.Path), root) {
+			repo = *candidate
+			break
+		}
+	}
+	if repo.Path == "" {
+		repo.Path = root
+	}
+	repo.Path = expandPath(repo.Path)
+	if len(repo.Steps) == 0 {
+		repo.Steps = defaultSteps(repo.Path)
+	}
+
+	if repo.Branch == "" {
+		repo.Branch = "main"
+	}
+	if repo.Remote == "" {
+		repo.Remote = "origin"
+	}
+	return repo
+}
+
+// defaultSteps returns the conventional build, test and lint steps of a project, detected
+// from its manifest
+func defaultSteps(root string) []config.ReleaseStep {
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(root, name))
+		return err == nil
+	}
+
+	switch {
+	case exists("go.mod"):
+		return []config.ReleaseStep{
+			{Name: "build", Run: "go build ./..."},
+			{Name: "test", Run: "go test ./..."},
+			{Name: "lint", Run: "golangci-lint run", OnFailure: failureContinue},
+		}
+	case exists("pubspec.yaml"):
+		return []config.ReleaseStep{
+			{Name: "test", Run: "flutter test"},
+			{Name: "lint", Run: "flutter analyze"},
+		}
+	case exists("package.json"):
+		return []config.ReleaseStep{
+			{Name: "build", Run: "npm run build --if-present"},
+			{Name: "test", Run: "npm test"},
+			{Name: "lint", Run: "npm run lint --if-present"},
+		}
+	case exists("pyproject.toml"):
+		return []config.ReleaseStep{
+			{Name: "build", Run: "python -m build"},
+			{Name: "test", Run: "python -m pytest"},
+		}
+	}
+	return nil
+}
+
+// repoRoot returns the top-level directory of the current checkout, or the working directory
+func (m *Module) repoRoot() string {
+	if output, err := exec.Command("git", "rev-parse", "--show-toplevel").Output(); err == nil {
+		return strings.TrimSpace(string(output))
+	}
+	dir, _ := os.Getwd()
+	return dir
+}
+
+// samePath reports whether two paths name the same directory
+func samePath(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	if absA, err := filepath.Abs(a); err == nil {
+		a = absA
+	}
+	if absB, err := filepath.Abs(b); err == nil {
+		b = absB
+	}
+	if resolved, err := filepath.EvalSymlinks(a); err == nil {
+		a = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(b); err == nil {
+		b = resolved
+	}
+	return a == b
+}
@@ -0,0 +1,204 @@
+package releasemanager
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Signature states of tags and commits
+const (
+	signatureGood       = "good"
+	signatureBad        = "bad"
+	signatureUnverified = "unverified"
+	signatureUnsigned   = "unsigned"
+)
+
+var (
+	signatureBlock = regexp.MustCompile(`-----BEGIN (PGP|SSH) SIGNATURE-----`)
+	sshSigner      = regexp.MustCompile(`Good "git" signature for (\S+) with (\S+) key (\S+)`)
+	gpgSigner      = regexp.MustCompile(`Good signature from "([^"]+)"`)
+)
+
+// tagSignature is the verification result of a tag
+type tagSignature struct {
+	State  string
+	Signer string
+	Detail string
+}
+
+// gitConfig returns the effective value of a git configuration key, or ""
+func gitConfig(key string) string {
+	output, err := exec.Command("git", "config", "--get", key).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// signingFormat returns the configured signature format: "ssh", "x509" or "openpgp"
+func signingFormat() string {
+	if format := gitConfig("gpg.format"); format != "" {
+		return format
+	}
+	return "openpgp"
+}
+
+// signingConfigured reports whether git is set up to sign, as done by the Git Signing
+// module: a signing key or commit or tag signing enabled
+func signingConfigured() bool {
+	return gitConfig("user.signingkey") != "" ||
+		gitConfig("tag.gpgsign") == "true" ||
+		gitConfig("commit.gpgsign") == "true"
+}
+
+// verifyArgs returns the git options needed to verify SSH signatures. When no
+// gpg.ssh.allowedSignersFile is configured, a temporary one trusting the own signing key
+// for user.email is created; cleanup removes it.
+func verifyArgs() (args []string, cleanup func(), err error) {
+	cleanup = func() {}
+	if signingFormat() != "ssh" {
+		return nil, cleanup, nil
+	}
+	if allowed := gitConfig("gpg.ssh.allowedSignersFile"); allowed != "" {
+		return []string{"-c", "gpg.ssh.allowedSignersFile=" + expandPath(allowed)}, cleanup, nil
+	}
+
+	email, key := gitConfig("user.email"), ownSigningKey()
+	if email == "" || key == "" {
+		return nil, cleanup, nil
+	}
+	file, err := os.CreateTemp("", "devtools-allowed-signers-")
+	if err != nil {
+		return nil, cleanup, err
+	}
+	cleanup = func() { os.Remove(file.Name()) }
+	if _, err := fmt.Fprintf(file, "%s namespaces=\"git\" %s\n", email, key); err != nil {
+		file.Close()
+		cleanup()
+		return nil, func() {}, err
+	}
+	file.Close()
+	return []string{"-c", "gpg.ssh.allowedSignersFile=" + file.Name()}, cleanup, nil
+}
+
+// ownSigningKey returns the public key configured as user.signingkey, which is either
+// the key itself, optionally prefixed with "key::", or the path of a key file
+func ownSigningKey() string {
+	key := strings.TrimPrefix(gitConfig("user.signingkey"), "key::")
+	if key == "" || strings.HasPrefix(key, "ssh-") || strings.HasPrefix(key, "ecdsa-") || strings.HasPrefix(key, "sk-") {
+		return key
+	}
+
+	path := expandPath(key)
+	if !strings.HasSuffix(path, ".pub") {
+		if _, err := os.Stat(path + ".pub"); err == nil {
+			path += ".pub"
+		}
+	}
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// verifyTag checks the signature of a tag with git tag -v
+func verifyTag(tag string, args []string) tagSignature {
+	output, err := exec.Command("git", "cat-file", "-p", "refs/tags/"+tag).Output()
+	if err != nil {
+		return tagSignature{State: signatureUnverified, Detail: err.Error()}
+	}
+	if !signatureBlock.Match(output) {
+		kind, _ := exec.Command("git", "cat-file", "-t", "refs/tags/"+tag).Output()
+		if strings.TrimSpace(string(kind)) == "commit" {
+			return tagSignature{State: signatureUnsigned, Detail: "lightweight tag"}
+		}
+		return tagSignature{State: signatureUnsigned, Detail: "annotated, no signature"}
+	}
+
+	verify := append(append([]string(nil), args...), "tag", "-v", tag)
+	result, err := exec.Command("git", verify...).CombinedOutput()
+	text := string(result)
+	if err == nil {
+		if match := sshSigner.FindStringSubmatch(text); match != nil {
+			return tagSignature{State: signatureGood, Signer: match[1], Detail: match[2] + " " + match[3]}
+		}
+		if match := gpgSigner.FindStringSubmatch(text); match != nil {
+			return tagSignature{State: signatureGood, Signer: match[1], Detail: "GPG"}
+		}
+		return tagSignature{State: signatureGood}
+	}
+
+	switch {
+	case strings.Contains(text, "allowedSignersFile needs to be configured"):
+		return tagSignature{State: signatureUnverified, Detail: "set gpg.ssh.allowedSignersFile to verify"}
+	case strings.Contains(text, "No principal matched"), strings.Contains(text, "No public key"),
+		strings.Contains(text, "Can't check signature"):
+		return tagSignature{State: signatureUnverified, Detail: "signer not trusted or key missing"}
+	}
+	return tagSignature{State: signatureBad, Detail: lastLine(text, err)}
+}
+
+// signatureLabel formats a signature state for display
+func signatureLabel(state string) string {
+	switch state {
+	case signatureGood:
+		return "✅ good"
+	case signatureBad:
+		return "❌ bad"
+	case signatureUnverified:
+		return "⚠️  unverified"
+	}
+	return "➖ unsigned"
+}
+
+// unsignedCommit is a commit without a valid signature
+type unsignedCommit struct {
+	Hash    string
+	Status  string
+	Subject string
+}
+
+// commitSignatureStatus describes the %G? signature codes of git log
+var commitSignatureStatus = map[string]string{
+	"B": "bad signature",
+	"X": "expired signature",
+	"Y": "signed with an expired key",
+	"R": "signed with a revoked key",
+	"E": "signature cannot be checked",
+	"N": "not signed",
+}
+
+// unsignedCommitsSince returns the commits after a tag, optionally only those touching
+// paths, whose signatures are missing or invalid. Signatures of unknown validity (U) count
+// as signed.
+func unsignedCommitsSince(tag string, args []string, paths ...string) ([]unsignedCommit, error) {
+	revRange := "HEAD"
+	if tag != "" {
+		revRange = tag + "..HEAD"
+	}
+	logArgs := append(append([]string(nil), args...), "log", "--format=%H%x1f%G?%x1f%s", revRange)
+	if len(paths) > 0 {
+		logArgs = append(append(logArgs, "--"), paths...)
+	}
+	output, err := exec.Command("git", logArgs...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit signatures: %v", err)
+	}
+
+	var unsigned []unsignedCommit
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.SplitN(line, "\x1f", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		if status, ok := commitSignatureStatus[fields[1]]; ok {
+			unsigned = append(unsigned, unsignedCommit{Hash: fields[0][:7], Status: status, Subject: fields[2]})
+		}
+	}
+	return unsigned, nil
+}
@@ -0,0 +1,332 @@
+package releasemanager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kkz6/devtools/internal/config"
+	"github.com/kkz6/devtools/internal/semver"
+	"github.com/kkz6/devtools/internal/ui"
+)
+
+// Version file patterns. The first group of each pattern is the version value.
+var (
+	jsonVersionPattern    = regexp.MustCompile(`"version"\s*:\s*"([^"]*)"`)
+	pubspecVersionPattern = regexp.MustCompile(`(?m)^version:\s*([^\s#]+)`)
+	tomlVersionPattern    = regexp.MustCompile(`(?m)^\s*version\s*=\s*"([^"]*)"`)
+	goVersionPattern      = regexp.MustCompile(`(?m)^\s*(?:const\s+)?Version\s*(?:string\s*)?=\s*"([^"]*)"`)
+	helmVersionPattern    = regexp.MustCompile(`(?m)^version:\s*"?([^"\s#]+)"?`)
+	helmAppVersionPattern = regexp.MustCompile(`(?m)^appVersion:\s*"?([^"\s#]+)"?`)
+	tomlSectionPattern    = regexp.MustCompile(`(?m)^\s*\[([^\]]+)\]\s*$`)
+)
+
+// versionFileFormats maps well-known file names to their format
+var versionFileFormats = map[string]string{
+	"package.json":   "json",
+	"composer.json":  "json",
+	"pubspec.yaml":   "pubspec",
+	"Cargo.toml":     "cargo",
+	"pyproject.toml": "pyproject",
+	"Chart.yaml":     "helm",
+}
+
+// versionFileFormat returns the format of a version file, detected from its name when unset
+func versionFileFormat(file config.VersionFile) string {
+	if file.Pattern != "" {
+		return "regex"
+	}
+	if file.Format != "" {
+		return strings.ToLower(file.Format)
+	}
+	if format, ok := versionFileFormats[filepath.Base(file.Path)]; ok {
+		return format
+	}
+	if strings.HasSuffix(file.Path, ".go") {
+		return "go"
+	}
+	return ""
+}
+
+// versionSpans returns the byte ranges of the version values in a version file
+func versionSpans(file config.VersionFile, content string) ([][2]int, error) {
+	var patterns []*regexp.Regexp
+	var section []string
+
+	switch format := versionFileFormat(file); format {
+	case "regex":
+		re, err := regexp.Compile(file.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern for %s: %v", file.Path, err)
+		}
+		if re.NumSubexp() < 1 {
+			return nil, fmt.Errorf("pattern for %s needs a group around the version", file.Path)
+		}
+		patterns = []*regexp.Regexp{re}
+	case "json":
+		patterns = []*regexp.Regexp{jsonVersionPattern}
+	case "pubspec":
+		patterns = []*regexp.Regexp{pubspecVersionPattern}
+	case "cargo":
+		patterns, section = []*regexp.Regexp{tomlVersionPattern}, []string{"package", "workspace.package"}
+	case "pyproject":
+		patterns, section = []*regexp.Regexp{tomlVersionPattern}, []string{"project", "tool.poetry"}
+	case "go":
+		patterns = []*regexp.Regexp{goVersionPattern}
+	case "helm":
+		patterns = []*regexp.Regexp{helmVersionPattern, helmAppVersionPattern}
+	case "":
+		return nil, fmt.Errorf("cannot detect the format of %s, set format or pattern", file.Path)
+	default:
+		return nil, fmt.Errorf("unknown format %q for %s: use json, pubspec, cargo, pyproject, go, helm or a pattern", format, file.Path)
+	}
+
+	start, end := 0, len(content)
+	if section != nil {
+		var ok bool
+		if start, end, ok = tomlSection(content, section); !ok {
+			return nil, fmt.Errorf("no [%s] section in %s", strings.Join(section, "] or ["), file.Path)
+		}
+	}
+
+	var spans [][2]int
+	for _, re := range patterns {
+		// Only the first occurrence is the project version, later ones may be dependencies
+		match := re.FindStringSubmatchIndex(content[start:end])
+		if match == nil || match[2] < 0 {
+			continue
+		}
+		spans = append(spans, [2]int{start + match[2], start + match[3]})
+	}
+	if len(spans) == 0 {
+		return nil, fmt.Errorf("no version found in %s", file.Path)
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	return spans, nil
+}
+
+// tomlSection returns the byte range of the first of the named TOML tables in content
+func tomlSection(content string, names []string) (int, int, bool) {
+	headers := tomlSectionPattern.FindAllStringSubmatchIndex(content, -1)
+	for _, name := range names {
+		for i, header := range headers {
+			if strings.TrimSpace(content[header[2]:header[3]]) != name {
+				continue
+			}
+			end := len(content)
+			if i+1 < len(headers) {
+				end = headers[i+1][0]
+			}
+			return header[1], end, true
+		}
+	}
+	return 0, 0, false
+}
+
+// versionFileValue formats a release version the way a file writes it: with a "v" only
+// when the file already used one, and with a pubspec build number incremented. Values
+// that already carry the version are kept.
+func versionFileValue(file config.VersionFile, old string, version semver.Version) string {
+	if current, err := semver.Parse(old); err == nil && current.Compare(version) == 0 {
+		return old
+	}
+
+	next := version
+	next.Prefix = ""
+	if strings.HasPrefix(old, "v") {
+		next.Prefix = "v"
+	}
+	value := next.String()
+
+	if versionFileFormat(file) == "pubspec" {
+		if i := strings.Index(old, "+"); i != -1 {
+			if build, err := strconv.Atoi(old[i+1:]); err == nil {
+				value = fmt.Sprintf("%s+%d", strings.SplitN(value, "+", 2)[0], build+1)
+			}
+		}
+	}
+	return value
+}
+
+// versionFileState is the version found in a version file
+type versionFileState struct {
+	Path   string
+	Values []string
+	Err    error
+}
+
+// readVersionFiles reads the versions of the repository's version files
+func readVersionFiles(repo config.ReleaseRepo) []versionFileState {
+	states := make([]versionFileState, 0, len(repo.VersionFiles))
+	for _, file := range repo.VersionFiles {
+		state := versionFileState{Path: file.Path}
+		content, err := os.ReadFile(filepath.Join(repo.Path, file.Path))
+		if err != nil {
+			state.Err = err
+			states = append(states, state)
+			continue
+		}
+		spans, err := versionSpans(file, string(content))
+		if err != nil {
+			state.Err = err
+		}
+		for _, span := range spans {
+			state.Values = append(state.Values, string(content[span[0]:span[1]]))
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+// bumpVersionFiles writes a version into the repository's version files and returns the
+// paths that changed. All files are checked before any is written.
+func bumpVersionFiles(repo config.ReleaseRepo, version string) ([]string, error) {
+	v, err := semver.Parse(version)
+	if err != nil {
+		return nil, err
+	}
+
+	type update struct {
+		path    string
+		content string
+	}
+	var updates []update
+	for _, file := range repo.VersionFiles {
+		path := filepath.Join(repo.Path, file.Path)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", file.Path, err)
+		}
+		content := string(data)
+		spans, err := versionSpans(file, content)
+		if err != nil {
+			return nil, err
+		}
+
+		// Replace from the end so earlier offsets stay valid
+		updated := content
+		for i := len(spans) - 1; i >= 0; i-- {
+			span := spans[i]
+			value := versionFileValue(file, content[span[0]:span[1]], v)
+			updated = updated[:span[0]] + value + updated[span[1]:]
+		}
+		if updated != content {
+			updates = append(updates, update{file.Path, updated})
+		}
+	}
+
+	changed := make([]string, 0, len(updates))
+	for _, u := range updates {
+		path := filepath.Join(repo.Path, u.path)
+		info, err := os.Stat(path)
+		if err != nil {
+			return changed, err
+		}
+		if err := os.WriteFile(path, []byte(u.content), info.Mode().Perm()); err != nil {
+			return changed, fmt.Errorf("failed to write %s: %v", u.path, err)
+		}
+		changed = append(changed, u.path)
+	}
+	return changed, nil
+}
+
+// commitVersionBump updates the version files and commits them as the release commit.
+// Nothing is committed when the files already carry the version.
+func (m *Module) commitVersionBump(repo config.ReleaseRepo, version string) error {
+	if len(repo.VersionFiles) == 0 {
+		return nil
+	}
+
+	changed, err := bumpVersionFiles(repo, version)
+	if err != nil {
+		return fmt.Errorf("failed to update version files: %v", err)
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	return m.commitReleaseFiles(repo, fmt.Sprintf("chore(release): %s", version), changed)
+}
+
+// commitReleaseFiles commits only the given files, relative to the repository, as the
+// release commit
+func (m *Module) commitReleaseFiles(repo config.ReleaseRepo, message string, files []string) error {
+	add := append([]string{"-C", repo.Path, "add", "--"}, files...)
+	if err := m.runCommand("git", add...); err != nil {
+		return fmt.Errorf("failed to stage release files: %v", err)
+	}
+	commit := append([]string{"-C", repo.Path, "commit", "-m", message, "--"}, files...)
+	if err := m.runCommand("git", commit...); err != nil {
+		return fmt.Errorf("failed to commit release files: %v", err)
+	}
+	return nil
+}
+
+// versionMismatches returns the version files that disagree with a version, compared
+// without "v" prefix and build metadata
+func versionMismatches(states []versionFileState, version string) []versionFileState {
+	expected, err := semver.Parse(version)
+	var mismatches []versionFileState
+	for _, state := range states {
+		if state.Err != nil {
+			mismatches = append(mismatches, state)
+			continue
+		}
+		for _, value := range state.Values {
+			v, parseErr := semver.Parse(value)
+			if err != nil || parseErr != nil || v.Compare(expected) != 0 {
+				mismatches = append(mismatches, state)
+				break
+			}
+		}
+	}
+	return mismatches
+}
+
+// checkVersionFiles shows the versions of the version files next to the latest tag and
+// fails when any file disagrees with it
+func (m *Module) checkVersionFiles(repo config.ReleaseRepo) error {
+	if len(repo.VersionFiles) == 0 {
+		ui.ShowWarning("No version files configured for this repository")
+		ui.ShowInfo("Add version_files under release_manager.repos in the configuration")
+		return nil
+	}
+
+	tag, err := m.getCurrentVersion()
+	if err != nil || tag == "v0.0.0" {
+		return fmt.Errorf("no version tag to compare with")
+	}
+
+	states := readVersionFiles(repo)
+	mismatches := versionMismatches(states, tag)
+	mismatched := make(map[string]bool)
+	for _, state := range mismatches {
+		mismatched[state.Path] = true
+	}
+
+	table := ui.NewTable(fmt.Sprintf("Version Files (latest tag %s)", tag))
+	table.AddHeader("File", "Version", "Status")
+	for _, state := range states {
+		value, status := strings.Join(state.Values, ", "), "✅ matches"
+		switch {
+		case state.Err != nil:
+			value, status = "-", "❌ "+state.Err.Error()
+		case mismatched[state.Path]:
+			status = "❌ differs"
+		}
+		table.AddRow(state.Path, value, status)
+	}
+	fmt.Println()
+	fmt.Println(table.Render())
+	fmt.Println()
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%d of %d version files disagree with %s", len(mismatches), len(states), tag)
+	}
+	ui.ShowSuccess(fmt.Sprintf("✅ All version files match %s", tag))
+	return nil
+}
@@ -0,0 +1,339 @@
+// Package semver implements Semantic Versioning 2.0.0 (https://semver.org):
+// parsing, precedence including pre-release identifiers, build metadata and
+// the version increments used by releases.
+package semver
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version. Prefix keeps an optional leading "v" so that
+// tags round-trip unchanged through Parse and String.
+type Version struct {
+	Prefix     string
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	Prerelease []string
+	Build      []string
+}
+
+// Parse parses a semantic version with an optional "v" prefix, e.g. "v1.2.0-rc.1+build.5"
+func Parse(s string) (Version, error) {
+	var v Version
+	rest := strings.TrimSpace(s)
+	if strings.HasPrefix(rest, "v") || strings.HasPrefix(rest, "V") {
+		v.Prefix = rest[:1]
+		rest = rest[1:]
+	}
+
+	if i := strings.Index(rest, "+"); i != -1 {
+		build, err := parseIdentifiers(rest[i+1:], false)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q: build metadata: %w", s, err)
+		}
+		v.Build = build
+		rest = rest[:i]
+	}
+
+	if i := strings.Index(rest, "-"); i != -1 {
+		pre, err := parseIdentifiers(rest[i+1:], true)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q: pre-release: %w", s, err)
+		}
+		v.Prerelease = pre
+		rest = rest[:i]
+	}
+
+	parts := strings.Split(rest, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("invalid version %q: expected MAJOR.MINOR.PATCH", s)
+	}
+	numbers := make([]uint64, 3)
+	for i, part := range parts {
+		n, err := parseNumber(part)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q: %w", s, err)
+		}
+		numbers[i] = n
+	}
+	v.Major, v.Minor, v.Patch = numbers[0], numbers[1], numbers[2]
+
+	return v, nil
+}
+
+// MustParse parses a version and panics when it is invalid
+func MustParse(s string) Version {
+	v, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// IsValid reports whether s is a semantic version
+func IsValid(s string) bool {
+	_, err := Parse(s)
+	return err == nil
+}
+
+// parseNumber parses a numeric version component, which must not have leading zeros
+func parseNumber(s string) (uint64, error) {
+	if s == "" {
+		return 0, fmt.Errorf("empty version number")
+	}
+	if len(s) > 1 && s[0] == '0' {
+		return 0, fmt.Errorf("version number %q has a leading zero", s)
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid version number %q", s)
+	}
+	return n, nil
+}
+
+// parseIdentifiers parses dot-separated pre-release or build identifiers
+func parseIdentifiers(s string, prerelease bool) ([]string, error) {
+	ids := strings.Split(s, ".")
+	for _, id := range ids {
+		if id == "" {
+			return nil, fmt.Errorf("empty identifier")
+		}
+		for _, r := range id {
+			if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+				return nil, fmt.Errorf("identifier %q contains %q", id, r)
+			}
+		}
+		if prerelease && isNumeric(id) && len(id) > 1 && id[0] == '0' {
+			return nil, fmt.Errorf("numeric identifier %q has a leading zero", id)
+		}
+	}
+	return ids, nil
+}
+
+// isNumeric reports whether an identifier consists of digits only
+func isNumeric(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the version, including its prefix, pre-release and build metadata
+func (v Version) String() string {
+	s := fmt.Sprintf("%s%d.%d.%d", v.Prefix, v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	if len(v.Build) > 0 {
+		s += "+" + strings.Join(v.Build, ".")
+	}
+	return s
+}
+
+// Core returns MAJOR.MINOR.PATCH with the prefix, without pre-release and build metadata
+func (v Version) Core() string {
+	return fmt.Sprintf("%s%d.%d.%d", v.Prefix, v.Major, v.Minor, v.Patch)
+}
+
+// IsPrerelease reports whether the version has pre-release identifiers
+func (v Version) IsPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+// PrereleaseLabel returns the first pre-release identifier, e.g. "rc" for 1.2.0-rc.1
+func (v Version) PrereleaseLabel() string {
+	if len(v.Prerelease) == 0 {
+		return ""
+	}
+	return v.Prerelease[0]
+}
+
+// Compare returns -1, 0 or 1 when v has lower, equal or higher precedence than o.
+// Build metadata does not affect precedence.
+func (v Version) Compare(o Version) int {
+	if c := compareUint(v.Major, o.Major); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Minor, o.Minor); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Patch, o.Patch); c != 0 {
+		return c
+	}
+
+	// A pre-release has lower precedence than the associated normal version
+	switch {
+	case len(v.Prerelease) == 0 && len(o.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(o.Prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.Prerelease) && i < len(o.Prerelease); i++ {
+		if c := compareIdentifier(v.Prerelease[i], o.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	// A larger set of pre-release fields has higher precedence when all preceding ones are equal
+	return compareUint(uint64(len(v.Prerelease)), uint64(len(o.Prerelease)))
+}
+
+// LessThan reports whether v has lower precedence than o
+func (v Version) LessThan(o Version) bool {
+	return v.Compare(o) < 0
+}
+
+// compareUint compares two numbers
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareIdentifier compares pre-release identifiers: numeric ones numerically and below
+// alphanumeric ones, alphanumeric ones in ASCII order
+func compareIdentifier(a, b string) int {
+	aNumeric, bNumeric := isNumeric(a), isNumeric(b)
+	switch {
+	case aNumeric && bNumeric:
+		x, _ := strconv.ParseUint(a, 10, 64)
+		y, _ := strconv.ParseUint(b, 10, 64)
+		return compareUint(x, y)
+	case aNumeric:
+		return -1
+	case bNumeric:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// Compare compares two version strings by precedence. Invalid versions sort before valid
+// ones and are compared as plain strings among themselves.
+func Compare(a, b string) int {
+	va, errA := Parse(a)
+	vb, errB := Parse(b)
+	switch {
+	case errA != nil && errB != nil:
+		return strings.Compare(a, b)
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	}
+	return va.Compare(vb)
+}
+
+// Release returns the normal version of a pre-release, e.g. 1.2.0-rc.2 becomes 1.2.0
+func (v Version) Release() Version {
+	return Version{Prefix: v.Prefix, Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+}
+
+// BumpMajor returns the next major version. A pre-release of a major version
+// (e.g. 2.0.0-rc.1) is released as that version.
+func (v Version) BumpMajor() Version {
+	if v.IsPrerelease() && v.Minor == 0 && v.Patch == 0 {
+		return v.Release()
+	}
+	return Version{Prefix: v.Prefix, Major: v.Major + 1}
+}
+
+// BumpMinor returns the next minor version. A pre-release of a minor version
+// (e.g. 1.3.0-beta.2) is released as that version.
+func (v Version) BumpMinor() Version {
+	if v.IsPrerelease() && v.Patch == 0 {
+		return v.Release()
+	}
+	return Version{Prefix: v.Prefix, Major: v.Major, Minor: v.Minor + 1}
+}
+
+// BumpPatch returns the next patch version. A pre-release is released as its normal version.
+func (v Version) BumpPatch() Version {
+	if v.IsPrerelease() {
+		return v.Release()
+	}
+	return Version{Prefix: v.Prefix, Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+}
+
+// WithPrerelease returns the version with the given pre-release identifiers and no build metadata
+func (v Version) WithPrerelease(ids ...string) Version {
+	next := v.Release()
+	next.Prerelease = append([]string(nil), ids...)
+	return next
+}
+
+// WithBuild returns the version with the given build metadata
+func (v Version) WithBuild(ids ...string) Version {
+	next := v
+	next.Build = append([]string(nil), ids...)
+	return next
+}
+
+// NextPrerelease returns the next pre-release of the same version with the given label:
+// 1.2.0-rc.1 becomes 1.2.0-rc.2, 1.2.0-beta.3 becomes 1.2.0-rc.1 for "rc". It fails for
+// normal versions and when the result would not have higher precedence, e.g. going from
+// rc back to alpha; such releases need a version bump first.
+func (v Version) NextPrerelease(label string) (Version, error) {
+	if !v.IsPrerelease() {
+		return Version{}, fmt.Errorf("%s is not a pre-release", v)
+	}
+
+	var next Version
+	if v.PrereleaseLabel() == label {
+		ids := append([]string(nil), v.Prerelease...)
+		last := ids[len(ids)-1]
+		if len(ids) > 1 && isNumeric(last) {
+			n, _ := strconv.ParseUint(last, 10, 64)
+			ids[len(ids)-1] = strconv.FormatUint(n+1, 10)
+		} else {
+			ids = append(ids, "1")
+		}
+		next = v.WithPrerelease(ids...)
+	} else {
+		next = v.WithPrerelease(label, "1")
+	}
+
+	if !v.LessThan(next) {
+		return Version{}, fmt.Errorf("%s does not follow %s", next, v)
+	}
+	return next, nil
+}
+
+// Sort sorts version strings in ascending precedence, invalid versions first
+func Sort(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return Compare(versions[i], versions[j]) < 0
+	})
+}
+
+// Latest returns the highest valid version among tags, optionally ignoring pre-releases.
+// The boolean is false when no tag is a semantic version.
+func Latest(tags []string, includePrerelease bool) (Version, bool) {
+	var latest Version
+	found := false
+	for _, tag := range tags {
+		v, err := Parse(tag)
+		if err != nil || (!includePrerelease && v.IsPrerelease()) {
+			continue
+		}
+		if !found || latest.LessThan(v) {
+			latest = v
+			found = true
+		}
+	}
+	return latest, found
+}
@@ -18,5 +18,11 @@ type Module interface {
 	Info() ModuleInfo
 }
 
+// CommandRunner is implemented by modules that can also be driven from the
+// command line, e.g. "devtools <module-id> <command> [flags]"
+type CommandRunner interface {
+	RunCommand(cfg *config.Config, args []string) error
+}
+
 // ErrNavigateBack is returned when user wants to go back to main menu
 var ErrNavigateBack = errors.New("navigate back") 
\ No newline at end of file
@@ -124,6 +124,11 @@ func (m selectionModel) View() string {
 
 // SelectFromListInteractive allows selecting from a list using arrow keys
 func SelectFromListInteractive(title string, options []string) (int, error) {
+	return SelectFromListWithDefault(title, options, 0)
+}
+
+// SelectFromListWithDefault allows selecting from a list with the cursor starting on the given option
+func SelectFromListWithDefault(title string, options []string, defaultIndex int) (int, error) {
 	// Create list items
 	items := []list.Item{}
 	for i, option := range options {
@@ -156,6 +161,10 @@ func SelectFromListInteractive(title string, options []string) (int, error) {
 		),
 	}
 
+	if defaultIndex > 0 && defaultIndex < len(items) {
+		l.Select(defaultIndex)
+	}
+
 	m := selectionModel{
 		list:   l,
 		title:  title,
@@ -1,4 +1,4 @@
-package cursorreport
+package ui
 
 import (
 	"fmt"
@@ -25,6 +25,11 @@ func NewTable(title string) *Table {
 	}
 }
 
+// Title returns the title of the table
+func (t *Table) Title() string {
+	return t.title
+}
+
 // AddHeader adds headers to the table
 func (t *Table) AddHeader(headers ...string) {
 	t.headers = headers
@@ -148,4 +153,43 @@ func (t *Table) Render() string {
 	result.WriteString(borderStyle.Render("┘"))
 	
 	return result.String()
-} 
\ No newline at end of file
+}
+
+// Markdown renders the table as a GitHub-flavored markdown table
+func (t *Table) Markdown() string {
+	if len(t.headers) == 0 {
+		return ""
+	}
+
+	escape := func(cell string) string {
+		cell = strings.ReplaceAll(cell, "|", "\\|")
+		return strings.ReplaceAll(cell, "\n", " ")
+	}
+
+	var result strings.Builder
+	if t.title != "" {
+		result.WriteString(fmt.Sprintf("### %s\n\n", t.title))
+	}
+
+	cells := make([]string, len(t.headers))
+	for i, h := range t.headers {
+		cells[i] = escape(h)
+	}
+	result.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	result.WriteString("|" + strings.Repeat(" --- |", len(t.headers)) + "\n")
+
+	for _, row := range t.rows {
+		for i, cell := range row {
+			cells[i] = escape(cell)
+		}
+		result.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	return result.String()
+}
+
+// Records returns the header followed by every row, e.g. for CSV export
+func (t *Table) Records() [][]string {
+	records := [][]string{t.headers}
+	return append(records, t.rows...)
+}
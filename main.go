@@ -35,6 +35,11 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Run a module command non-interactively, e.g. "devtools bugmanager serve"
+	if flag.NArg() > 0 {
+		os.Exit(runCommand(flag.Args()))
+	}
+
 	// Clear screen and show banner
 	fmt.Print("\033[H\033[2J")
 	ui.ShowBanner()
@@ -121,4 +126,35 @@ func main() {
 		fmt.Print("Press Enter to return to main menu...")
 		fmt.Scanln()
 	}
-} 
\ No newline at end of file
+} 
+
+// runCommand runs a module command from the command line and returns the exit code
+func runCommand(args []string) int {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Printf("Warning: Could not load config: %v", err)
+		cfg = config.New()
+	}
+
+	registry := modules.NewRegistry()
+	modules.RegisterAll(registry)
+
+	module, err := registry.Get(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	runner, ok := module.(types.CommandRunner)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: module %s has no command-line commands\n", args[0])
+		return 1
+	}
+
+	if err := runner.RunCommand(cfg, args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	return 0
+}